@@ -0,0 +1,74 @@
+package bjoin
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// WriteCSV writes pairs of the index to w in CSV format with header "a,b".
+// The a elements that doesn't have join values are written with empty b.
+func (j *Index) WriteCSV(ctx context.Context, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"a", "b"}); err != nil {
+		return err
+	}
+	for pair := range j.PairsGen(ctx) {
+		a := strconv.FormatUint(pair.A, 10)
+		if len(pair.B) == 0 {
+			if err := cw.Write([]string{a, ""}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, b := range pair.B {
+			if err := cw.Write([]string{a, strconv.FormatUint(b, 10)}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// jsonPair represents pair of elements in JSON Lines format.
+type jsonPair struct {
+	A uint64  `json:"a"`
+	B *uint64 `json:"b"`
+}
+
+// WriteJSONL writes pairs of the index to w in JSON Lines format, one {"a":..., "b":...} object per line.
+// The a elements that doesn't have join values are written with null b.
+func (j *Index) WriteJSONL(ctx context.Context, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for pair := range j.PairsGen(ctx) {
+		if len(pair.B) == 0 {
+			if err := enc.Encode(jsonPair{A: pair.A}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, b := range pair.B {
+			if err := enc.Encode(jsonPair{A: pair.A, B: &b}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,48 @@
+package bjoin
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+)
+
+func TestIndex_Export(t *testing.T) {
+	join := New(10)
+	a := roaring64.New()
+	a.AddMany([]uint64{1, 2})
+	b := roaring64.New()
+	b.AddMany([]uint64{3, 4})
+	if err := join.TryAddPairs(a, b); err != nil {
+		t.Fatal(err)
+	}
+	a = roaring64.New()
+	a.Add(5)
+	if err := join.TryAddPairs(a, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := join.WriteCSV(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "a,b\n1,3\n1,4\n2,3\n2,4\n5,\n"
+	if buf.String() != want {
+		t.Errorf("WriteCSV() got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := join.WriteJSONL(context.Background(), &buf); err != nil {
+		t.Fatal(err)
+	}
+	want = `{"a":1,"b":3}
+{"a":1,"b":4}
+{"a":2,"b":3}
+{"a":2,"b":4}
+{"a":5,"b":null}
+`
+	if buf.String() != want {
+		t.Errorf("WriteJSONL() got %q, want %q", buf.String(), want)
+	}
+}
@@ -3,16 +3,22 @@ package bjoin
 import (
 	"context"
 	"errors"
+	"math"
 
 	"github.com/RoaringBitmap/roaring/roaring64"
 )
 
 // The package bjoin provides a data structure to collect and retrieve results of join two bitmap index.
 
-// Index represent cross product matrix of two bitmap index
+// Index represent cross product matrix of two bitmap index.
+// Pairs are stored in a single bitmap as a*(offset+1)+b+1 while the encoded value fits in uint64,
+// otherwise the index switches to the wide encoding: map of a to bitmap of b.
 type Index struct {
 	cp     *roaring64.Bitmap // cross product matrix when each number represent pair of elements a and b
 	offset uint64            // len of b
+
+	wideA *roaring64.Bitmap            // a elements of the wide encoding
+	wide  map[uint64]*roaring64.Bitmap // b elements for each a of the wide encoding, empty if a doesn't have join values
 }
 
 // Pair represent pair of elements.
@@ -21,19 +27,87 @@ type Pair struct {
 	B []uint64
 }
 
+var (
+	ErrDifferentOffset = errors.New("indexes have different offset")
+	ErrOutOfRange      = errors.New("b element is out of the index offset")
+)
+
 // New returns new BitmapJoinIndex with given offset.
 func New(offset uint64) *Index {
-	return &Index{
+	j := &Index{
 		cp:     roaring64.New(),
 		offset: offset,
 	}
+	if offset == math.MaxUint64 {
+		j.widen()
+	}
+	return j
 }
 
 // IsEmpty returns true if index have no elements.
 func (j *Index) IsEmpty() bool {
+	if j.isWide() {
+		return j.wideA.IsEmpty()
+	}
 	return j.cp.IsEmpty()
 }
 
+// Offset returns len of b elements.
+func (j *Index) Offset() uint64 {
+	return j.offset
+}
+
+// IsWide returns true if index uses the wide encoding.
+func (j *Index) IsWide() bool {
+	return j.isWide()
+}
+
+//go:inline
+func (j *Index) isWide() bool {
+	return j.wide != nil
+}
+
+// fits checks all pairs of a can be encoded in the cross product matrix without uint64 overflow.
+//
+//go:inline
+func (j *Index) fits(a uint64) bool {
+	return j.offset < math.MaxUint64 && a < math.MaxUint64/(j.offset+1)
+}
+
+// widen converts the cross product matrix to the wide encoding.
+func (j *Index) widen() {
+	if j.isWide() {
+		return
+	}
+	j.wideA, j.wide = j.rows()
+	j.cp = roaring64.New()
+}
+
+// rows returns a elements and corresponded b elements of the index.
+// For the wide encoding it returns internal data, that shouldn't be changed.
+func (j *Index) rows() (*roaring64.Bitmap, map[uint64]*roaring64.Bitmap) {
+	if j.isWide() {
+		return j.wideA, j.wide
+	}
+	as := roaring64.New()
+	rows := map[uint64]*roaring64.Bitmap{}
+	it := j.cp.Iterator()
+	for it.HasNext() {
+		idx := it.Next()
+		a := j.a(idx)
+		row, ok := rows[a]
+		if !ok {
+			row = roaring64.New()
+			rows[a] = row
+			as.Add(a)
+		}
+		if b, ok := j.b(idx); ok {
+			row.Add(b)
+		}
+	}
+	return as, rows
+}
+
 //go:inline
 func (j *Index) idxA(a uint64) uint64 {
 	return a * (j.offset + 1)
@@ -63,36 +137,86 @@ func (j *Index) hasB(idx uint64) bool {
 	return idx%(j.offset+1) != 0
 }
 
-// AddPairs add pairs of two bitmaps.
+// AddPairs add pairs of two bitmaps, previous pairs of a elements are replaced.
+// Elements of b greater or equal offset are skipped, use TryAddPairs to detect them.
+// If pairs can't be encoded in the cross product matrix the index switches to the wide encoding.
 func (j *Index) AddPairs(a, b *roaring64.Bitmap) {
+	if j.TryAddPairs(a, b) == nil {
+		return
+	}
+	b = b.Clone()
+	b.RemoveRange(j.offset, math.MaxUint64)
+	b.Remove(math.MaxUint64)
+	_ = j.TryAddPairs(a, b)
+}
+
+// TryAddPairs add pairs of two bitmaps like AddPairs,
+// but returns ErrOutOfRange and doesn't change index if b contains elements greater or equal offset.
+// The index with math.MaxUint64 offset accepts all b elements.
+func (j *Index) TryAddPairs(a, b *roaring64.Bitmap) error {
+	if a == nil || a.IsEmpty() {
+		return nil
+	}
+	if b != nil && !b.IsEmpty() && j.offset != math.MaxUint64 && b.Maximum() >= j.offset {
+		return ErrOutOfRange
+	}
+	if !j.isWide() && !j.fits(a.Maximum()) {
+		j.widen()
+	}
+	if j.isWide() {
+		j.addWidePairs(a, b)
+		return nil
+	}
 	itA := a.Iterator()
 	for itA.HasNext() {
 		a := itA.Next()
-		j.cp.RemoveRange(j.idxA(a), j.idxA(a)+j.offset)
+		j.cp.RemoveRange(j.idxA(a), j.idxA(a)+j.offset+1)
 		if b == nil || b.IsEmpty() {
 			j.cp.Add(j.idxA(a))
 			continue
 		}
 		itB := b.Iterator()
 		for itB.HasNext() {
-			b := itB.Next()
-			if b < j.offset {
-				j.cp.Add(j.idx(a, b))
-			}
+			j.cp.Add(j.idx(a, itB.Next()))
 		}
 	}
+	return nil
+}
+
+// addWidePairs add pairs of two bitmaps to the wide encoding.
+func (j *Index) addWidePairs(a, b *roaring64.Bitmap) {
+	j.wideA.Or(a)
+	itA := a.Iterator()
+	for itA.HasNext() {
+		if b == nil {
+			j.wide[itA.Next()] = roaring64.New()
+			continue
+		}
+		j.wide[itA.Next()] = b.Clone()
+	}
 }
 
 // Clone() makes copy of bitmap join index.
 func (j *Index) Clone() *Index {
-	return &Index{
+	ni := &Index{
 		offset: j.offset,
 		cp:     j.cp.Clone(),
 	}
+	if j.isWide() {
+		ni.wideA = j.wideA.Clone()
+		ni.wide = make(map[uint64]*roaring64.Bitmap, len(j.wide))
+		for a, row := range j.wide {
+			ni.wide[a] = row.Clone()
+		}
+	}
+	return ni
 }
 
 // PairsGen returns channel of pairs.
 func (j *Index) PairsGen(ctx context.Context) <-chan Pair {
+	if j.isWide() {
+		return j.widePairsGen(ctx)
+	}
 	out := make(chan Pair)
 	go func(ctx context.Context) {
 		defer close(out)
@@ -130,11 +254,47 @@ func (j *Index) PairsGen(ctx context.Context) <-chan Pair {
 	return out
 }
 
+// widePairsGen returns channel of pairs for the wide encoding.
+func (j *Index) widePairsGen(ctx context.Context) <-chan Pair {
+	out := make(chan Pair)
+	go func(ctx context.Context) {
+		defer close(out)
+		it := j.wideA.Iterator()
+		for it.HasNext() {
+			pair := Pair{A: it.Next()}
+			if row := j.wide[pair.A]; !row.IsEmpty() {
+				pair.B = row.ToArray()
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- pair:
+			}
+		}
+	}(ctx)
+	return out
+}
+
 // SingleGen returns channel with contained a elements that doesn't have join values.
 func (j *Index) SingleGen(ctx context.Context) <-chan uint64 {
 	out := make(chan uint64)
 	go func(ctx context.Context) {
 		defer close(out)
+		if j.isWide() {
+			it := j.wideA.Iterator()
+			for it.HasNext() {
+				a := it.Next()
+				if !j.wide[a].IsEmpty() {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- a:
+				}
+			}
+			return
+		}
 		it := j.cp.Iterator()
 		for it.HasNext() {
 			idx := it.Next()
@@ -155,37 +315,77 @@ func (j *Index) SingleGen(ctx context.Context) <-chan uint64 {
 }
 
 // ABGen returns channel with single pair of joined elements.
+// The a elements that doesn't have join values are skipped.
 func (j *Index) ABGen(ctx context.Context) <-chan [2]uint64 {
 	out := make(chan [2]uint64)
 	go func(ctx context.Context) {
 		defer close(out)
+		if j.isWide() {
+			itA := j.wideA.Iterator()
+			for itA.HasNext() {
+				a := itA.Next()
+				itB := j.wide[a].Iterator()
+				for itB.HasNext() {
+					select {
+					case <-ctx.Done():
+						return
+					case out <- [2]uint64{a, itB.Next()}:
+					}
+				}
+			}
+			return
+		}
 		it := j.cp.Iterator()
 		for it.HasNext() {
 			idx := it.Next()
-			a := idx / (j.offset + 1)
-			b := idx - a*(j.offset+1)
+			b, ok := j.b(idx)
+			if !ok {
+				continue
+			}
 			select {
 			case <-ctx.Done():
 				return
-			case out <- [2]uint64{a, b}:
+			case out <- [2]uint64{j.a(idx), b}:
 			}
 		}
 	}(ctx)
 	return out
 }
 
-var ErrDifferentOffset = errors.New("indexes have different offset")
-
 // And computes the intersection between two bitmaps and stores the result in the current bitmap
 func (j *Index) And(in *Index) error {
 	if in == nil {
 		j.cp = roaring64.New()
+		if j.isWide() {
+			j.wideA, j.wide = roaring64.New(), map[uint64]*roaring64.Bitmap{}
+		}
 		return nil
 	}
 	if j.offset != in.offset {
 		return ErrDifferentOffset
 	}
-	j.cp.And(in.cp)
+	if !j.isWide() && !in.isWide() {
+		j.cp.And(in.cp)
+		return nil
+	}
+	j.widen()
+	inA, inRows := in.rows()
+	it := j.wideA.Clone().Iterator()
+	for it.HasNext() {
+		a := it.Next()
+		row := j.wide[a]
+		if !inA.Contains(a) {
+			j.removeWideA(a)
+			continue
+		}
+		if row.IsEmpty() && inRows[a].IsEmpty() {
+			continue
+		}
+		row.And(inRows[a])
+		if row.IsEmpty() {
+			j.removeWideA(a)
+		}
+	}
 	return nil
 }
 
@@ -197,7 +397,23 @@ func (j *Index) Or(in *Index) error {
 	if j.offset != in.offset {
 		return ErrDifferentOffset
 	}
-	j.cp.Or(in.cp)
+	if !j.isWide() && !in.isWide() {
+		j.cp.Or(in.cp)
+		return nil
+	}
+	j.widen()
+	inA, inRows := in.rows()
+	it := inA.Iterator()
+	for it.HasNext() {
+		a := it.Next()
+		row, ok := j.wide[a]
+		if !ok {
+			j.wideA.Add(a)
+			j.wide[a] = inRows[a].Clone()
+			continue
+		}
+		row.Or(inRows[a])
+	}
 	return nil
 }
 
@@ -209,16 +425,47 @@ func (j *Index) AndNot(in *Index) error {
 	if j.offset != in.offset {
 		return ErrDifferentOffset
 	}
-	j.cp.AndNot(in.cp)
+	if !j.isWide() && !in.isWide() {
+		j.cp.AndNot(in.cp)
+		return nil
+	}
+	j.widen()
+	inA, inRows := in.rows()
+	it := roaring64.And(j.wideA, inA).Iterator()
+	for it.HasNext() {
+		a := it.Next()
+		row := j.wide[a]
+		if row.IsEmpty() {
+			if inRows[a].IsEmpty() {
+				j.removeWideA(a)
+			}
+			continue
+		}
+		row.AndNot(inRows[a])
+		if row.IsEmpty() {
+			j.removeWideA(a)
+		}
+	}
 	return nil
 }
 
+// removeWideA removes a element from the wide encoding.
+func (j *Index) removeWideA(a uint64) {
+	j.wideA.Remove(a)
+	delete(j.wide, a)
+}
+
 // CrossJoin makes BitmapJoinIndex with pairs.
+// If b contains math.MaxUint64 the index offset is math.MaxUint64 and the index uses the wide encoding.
 func CrossJoin(a, b *roaring64.Bitmap) *Index {
-	j := &Index{
-		cp:     roaring64.New(),
-		offset: b.Maximum() + 1,
+	offset := uint64(0)
+	if b != nil && !b.IsEmpty() {
+		offset = b.Maximum()
+		if offset < math.MaxUint64 {
+			offset++
+		}
 	}
+	j := New(offset)
 	j.AddPairs(a, b)
 	return j
 }
@@ -2,6 +2,7 @@ package bjoin
 
 import (
 	"context"
+	"math"
 	"testing"
 
 	"github.com/RoaringBitmap/roaring/roaring64"
@@ -107,3 +108,135 @@ func TestIndex(t *testing.T) {
 		i++
 	}
 }
+
+func TestIndexWide(t *testing.T) {
+	const offset = uint64(1) << 40
+	a := roaring64.New()
+	a.AddMany([]uint64{1, 1 << 30})
+	b := roaring64.New()
+	bb := []uint64{0, 1 << 35, offset - 1}
+	b.AddMany(bb)
+
+	join := New(offset)
+	if err := join.TryAddPairs(a, b); err != nil {
+		t.Fatalf("TryAddPairs() unexpected error: %v", err)
+	}
+	if !join.IsWide() {
+		t.Fatal("AddPairs() doesn't switch index to the wide encoding")
+	}
+	single := roaring64.New()
+	single.Add(1 << 31)
+	if err := join.TryAddPairs(single, nil); err != nil {
+		t.Fatalf("TryAddPairs() unexpected error: %v", err)
+	}
+
+	want := []Pair{{A: 1, B: bb}, {A: 1 << 30, B: bb}, {A: 1 << 31}}
+	i := 0
+	for pair := range join.PairsGen(context.Background()) {
+		if pair.A != want[i].A || slices.Compare(pair.B, want[i].B) != 0 {
+			t.Errorf("pair has wrong value, got %v, want %v", pair, want[i])
+		}
+		i++
+	}
+	if i != len(want) {
+		t.Errorf("PairsGen() returns %d pairs, want %d", i, len(want))
+	}
+	for a := range join.SingleGen(context.Background()) {
+		if a != 1<<31 {
+			t.Errorf("SingleGen() returns wrong a, got %d, want %d", a, 1<<31)
+		}
+	}
+	n := 0
+	for ab := range join.ABGen(context.Background()) {
+		if ab[1] != bb[n%len(bb)] {
+			t.Errorf("ABGen() returns wrong b, got %d, want %d", ab[1], bb[n%len(bb)])
+		}
+		n++
+	}
+	if n != 2*len(bb) {
+		t.Errorf("ABGen() returns %d pairs, want %d", n, 2*len(bb))
+	}
+
+	narrow := New(offset)
+	a = roaring64.New()
+	a.Add(1)
+	b = roaring64.New()
+	b.Add(0)
+	if err := narrow.TryAddPairs(a, b); err != nil {
+		t.Fatalf("TryAddPairs() unexpected error: %v", err)
+	}
+	if narrow.IsWide() {
+		t.Fatal("AddPairs() switches index to the wide encoding without overflow")
+	}
+	clone := join.Clone()
+	if err := clone.And(narrow); err != nil {
+		t.Fatalf("And() unexpected error: %v", err)
+	}
+	for pair := range clone.PairsGen(context.Background()) {
+		if pair.A != 1 || slices.Compare(pair.B, []uint64{0}) != 0 {
+			t.Errorf("And() returns wrong pair, got %v", pair)
+		}
+	}
+	if err := join.AndNot(narrow); err != nil {
+		t.Fatalf("AndNot() unexpected error: %v", err)
+	}
+	for pair := range join.PairsGen(context.Background()) {
+		if pair.A == 1 && slices.Contains(pair.B, 0) {
+			t.Errorf("AndNot() doesn't remove pair, got %v", pair)
+		}
+	}
+
+	b.Add(offset)
+	if err := narrow.TryAddPairs(a, b); err != ErrOutOfRange {
+		t.Errorf("TryAddPairs() error = %v, want %v", err, ErrOutOfRange)
+	}
+	narrow.AddPairs(a, b)
+	for pair := range narrow.PairsGen(context.Background()) {
+		if pair.A != 1 || slices.Compare(pair.B, []uint64{0}) != 0 {
+			t.Errorf("AddPairs() doesn't skip out of range b, got %v", pair)
+		}
+	}
+}
+
+func TestCrossJoin(t *testing.T) {
+	a := roaring64.BitmapOf(1, 2)
+	b := roaring64.BitmapOf(3, math.MaxUint64)
+	join := CrossJoin(a, b)
+	if join.Offset() != math.MaxUint64 || !join.IsWide() {
+		t.Fatalf("CrossJoin() offset = %d, wide = %v", join.Offset(), join.IsWide())
+	}
+	n := 0
+	for pair := range join.PairsGen(context.Background()) {
+		if slices.Compare(pair.B, []uint64{3, math.MaxUint64}) != 0 {
+			t.Errorf("CrossJoin() returns wrong pair, got %v", pair)
+		}
+		n++
+	}
+	if n != 2 {
+		t.Errorf("CrossJoin() returns %d pairs, want 2", n)
+	}
+	if join := CrossJoin(a, roaring64.New()); join.Offset() != 0 || join.IsEmpty() {
+		t.Errorf("CrossJoin() with empty b returns offset %d, empty %v", join.Offset(), join.IsEmpty())
+	}
+}
+
+func TestIndex_Stats(t *testing.T) {
+	a := roaring64.BitmapOf(1, 2)
+	b := roaring64.BitmapOf(3, 4, 5)
+	for _, offset := range []uint64{10, 1 << 62} {
+		join := New(offset)
+		if err := join.TryAddPairs(a, b); err != nil {
+			t.Fatal(err)
+		}
+		if err := join.TryAddPairs(roaring64.BitmapOf(7), nil); err != nil {
+			t.Fatal(err)
+		}
+		s := join.Stats()
+		if s.Items != 3 || s.Singles != 1 || s.Pairs != 6 {
+			t.Errorf("Stats() for offset %d got items %d, singles %d, pairs %d, want 3, 1, 6", offset, s.Items, s.Singles, s.Pairs)
+		}
+		if s.Wide != (offset != 10) {
+			t.Errorf("Stats() for offset %d got wide %v", offset, s.Wide)
+		}
+	}
+}
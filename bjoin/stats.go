@@ -0,0 +1,70 @@
+package bjoin
+
+// Stats represents statistics of the join index.
+type Stats struct {
+	Wide            bool   `json:"wide"`
+	Offset          uint64 `json:"offset"`
+	Items           uint64 `json:"items"`   // count of a elements
+	Singles         uint64 `json:"singles"` // count of a elements without join values
+	Pairs           uint64 `json:"pairs"`
+	InMemoryBytes   uint64 `json:"in_memory_bytes"`
+	SerializedBytes uint64 `json:"serialized_bytes"`
+}
+
+// Stats returns statistics of the join index.
+func (j *Index) Stats() Stats {
+	s := Stats{
+		Wide:   j.isWide(),
+		Offset: j.offset,
+	}
+	if !j.isWide() {
+		s.InMemoryBytes = j.cp.GetSizeInBytes()
+		s.SerializedBytes = j.cp.GetSerializedSizeInBytes()
+		it := j.cp.Iterator()
+		last := uint64(0)
+		for it.HasNext() {
+			idx := it.Next()
+			a := j.a(idx)
+			if s.Items == 0 || a != last {
+				s.Items++
+				last = a
+			}
+			if j.hasB(idx) {
+				s.Pairs++
+				continue
+			}
+			s.Singles++
+		}
+		return s
+	}
+	s.Items = j.wideA.GetCardinality()
+	s.InMemoryBytes = j.wideA.GetSizeInBytes()
+	s.SerializedBytes = j.wideA.GetSerializedSizeInBytes()
+	for _, row := range j.wide {
+		s.InMemoryBytes += row.GetSizeInBytes()
+		s.SerializedBytes += row.GetSerializedSizeInBytes()
+		if row.IsEmpty() {
+			s.Singles++
+			continue
+		}
+		s.Pairs += row.GetCardinality()
+	}
+	return s
+}
+
+// Metrics returns statistics as flat metrics.
+func (s Stats) Metrics() map[string]float64 {
+	wide := 0.
+	if s.Wide {
+		wide = 1
+	}
+	return map[string]float64{
+		"bjoin_wide":             wide,
+		"bjoin_offset":           float64(s.Offset),
+		"bjoin_items":            float64(s.Items),
+		"bjoin_singles":          float64(s.Singles),
+		"bjoin_pairs":            float64(s.Pairs),
+		"bjoin_in_memory_bytes":  float64(s.InMemoryBytes),
+		"bjoin_serialized_bytes": float64(s.SerializedBytes),
+	}
+}
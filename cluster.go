@@ -0,0 +1,165 @@
+package geobin
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/VGSML/geobin/orbf"
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/VGSML/geobin/orbf/sphere"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+	"github.com/uber/h3-go/v4"
+	"golang.org/x/exp/slices"
+)
+
+// maxKMeansIterations limits iterations of k-means if assignments of items don't converge.
+const maxKMeansIterations = 100
+
+var ErrInvalidClusters = errors.New("number of clusters should be positive")
+
+// ClusterKMeans perform clustering of indexed items by k-means algorithm.
+// Items are represented by their points on surface, so the point of concave polygon lies inside it,
+// distances are great-circle distances. Seeds are chosen deterministically: the point of the item
+// with the smallest index and then the point farthest from chosen seeds. If items have less distinct
+// points than clusters, less clusters are used. Cluster function is called for items ordered by index.
+func (i *Index) ClusterKMeans(ctx context.Context, clusters int, clusterFunc ClusterFunc) error {
+	if clusters <= 0 {
+		return ErrInvalidClusters
+	}
+	ids, points := i.clusterPoints()
+	return kmeans(ctx, ids, points, kmeansSeeds(points, clusters), clusterFunc)
+}
+
+// ClusterKMeansWithSeeds perform clustering of indexed items by k-means algorithm with given seeds.
+// Centers of seed cells are initial cluster centers, cluster index is the index of the seed.
+func (i *Index) ClusterKMeansWithSeeds(ctx context.Context, seeds []h3.Cell, clusterFunc ClusterFunc) error {
+	if len(seeds) == 0 {
+		return ErrInvalidClusters
+	}
+	centers := make([]sphere.Vector, len(seeds))
+	for n, c := range seeds {
+		ll := c.LatLng()
+		centers[n] = sphere.FromPoint(orb.Point{ll.Lng, ll.Lat})
+	}
+	ids, points := i.clusterPoints()
+	return kmeans(ctx, ids, points, centers, clusterFunc)
+}
+
+// clusterPoints returns item indexes in ascending order and unit vectors of their points on surface.
+func (i *Index) clusterPoints() ([]int, []sphere.Vector) {
+	ids := make([]int, 0, len(i.items))
+	for idx := range i.items {
+		ids = append(ids, idx)
+	}
+	sort.Ints(ids)
+	points := make([]sphere.Vector, len(ids))
+	for n, idx := range ids {
+		points[n] = sphere.FromPoint(i.itemSurfacePointWGS84(i.items[idx]))
+	}
+	return ids, points
+}
+
+// itemSurfacePointWGS84 returns point on surface of item in WGS84 projection.
+// For items that doesn't store geometry returns center of indexed cells.
+func (i *Index) itemSurfacePointWGS84(item Item) orb.Point {
+	g, ok := item.(Geometry)
+	if !ok {
+		return i.itemCentroidWGS84(item)
+	}
+	geom := g.Geom()
+	if i.proj == WGS84 {
+		geom = orbf.SplitAntimeridian(geom)
+	}
+	p, ok := planar.PointOnSurface(geom).(orb.Point)
+	if !ok {
+		p = geom.Bound().Center()
+	}
+	if i.proj == Mercator {
+		return project.Point(p, project.Mercator.ToWGS84)
+	}
+	return p
+}
+
+// kmeansSeeds returns the first point and then the points farthest from chosen seeds.
+func kmeansSeeds(points []sphere.Vector, clusters int) []sphere.Vector {
+	if len(points) == 0 {
+		return nil
+	}
+	seeds := []sphere.Vector{points[0]}
+	// nearest is the cosine of angle to the nearest seed
+	nearest := make([]float64, len(points))
+	for n, p := range points {
+		nearest[n] = p.Dot(points[0])
+	}
+	for len(seeds) < clusters {
+		far := 0
+		for n := range points {
+			if nearest[n] < nearest[far] {
+				far = n
+			}
+		}
+		if slices.Contains(seeds, points[far]) {
+			// the rest of points are equal to seeds
+			break
+		}
+		seeds = append(seeds, points[far])
+		for n, p := range points {
+			nearest[n] = max(nearest[n], p.Dot(points[far]))
+		}
+	}
+	return seeds
+}
+
+// kmeans assigns points to the nearest centers and moves centers to the mean of assigned points
+// until assignments don't change, centers of empty clusters are not moved.
+func kmeans(ctx context.Context, ids []int, points, centers []sphere.Vector, clusterFunc ClusterFunc) error {
+	assign := make([]int, len(points))
+	for n := range assign {
+		assign[n] = -1
+	}
+	for iter := 0; iter < maxKMeansIterations; iter++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		changed := false
+		for n, p := range points {
+			if c := nearestCenter(centers, p); c != assign[n] {
+				assign[n], changed = c, true
+			}
+		}
+		if !changed {
+			break
+		}
+		sums := make([]sphere.Vector, len(centers))
+		for n, p := range points {
+			s := &sums[assign[n]]
+			s[0], s[1], s[2] = s[0]+p[0], s[1]+p[1], s[2]+p[2]
+		}
+		for c, s := range sums {
+			if norm := math.Sqrt(s.Dot(s)); norm > 0 {
+				centers[c] = sphere.Vector{s[0] / norm, s[1] / norm, s[2] / norm}
+			}
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for n, idx := range ids {
+		clusterFunc(assign[n], idx)
+	}
+	return nil
+}
+
+// nearestCenter returns index of the center nearest to the point, the first one of equally near centers.
+func nearestCenter(centers []sphere.Vector, p sphere.Vector) int {
+	best := 0
+	for c := range centers[1:] {
+		if p.Dot(centers[c+1]) > p.Dot(centers[best]) {
+			best = c + 1
+		}
+	}
+	return best
+}
@@ -0,0 +1,99 @@
+package geobin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/VGSML/geobin/orbf"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+	"github.com/uber/h3-go/v4"
+)
+
+func TestIndex_ClusterKMeans(t *testing.T) {
+	// two groups of squares and U-shaped polygon near the second group
+	groups := [][]orb.Polygon{gridSquares(orb.Point{10, 10}, 2, 0.01), gridSquares(orb.Point{20, 20}, 2, 0.01)}
+	concave := orb.Polygon{{{20, 21}, {20.3, 21}, {20.3, 21.3}, {20.2, 21.3}, {20.2, 21.1}, {20.1, 21.1}, {20.1, 21.3}, {20, 21.3}, {20, 21}}}
+	for _, proj := range []Projection{WGS84, Mercator} {
+		var options []IndexOptions
+		if proj == Mercator {
+			options = append(options, WithMercatorProjection())
+		}
+		index := NewIndex(append(options, WithMaxResolution(9))...)
+		want := map[int]int{}
+		insert := func(idx int, poly orb.Polygon, group int) {
+			geom := orb.Geometry(poly)
+			if proj == Mercator {
+				geom = project.Geometry(orb.Clone(poly), project.WGS84.ToMercator)
+			}
+			index.Insert(idx, geom)
+			want[idx] = group
+		}
+		for group, polys := range groups {
+			for n, poly := range polys {
+				insert(group*10+n, poly, group)
+			}
+		}
+		insert(100, concave, 1)
+		// centroid of the U-shaped polygon is out of it
+		if p := index.itemSurfacePointWGS84(index.items[100]); !orbf.Contains(concave, p) {
+			t.Errorf("projection %v: point on surface %v is out of polygon", proj, p)
+		}
+
+		got := map[int]int{}
+		var order []int
+		clusterFunc := func(clusterIdx, idx int) {
+			got[idx] = clusterIdx
+			order = append(order, idx)
+		}
+		if err := index.ClusterKMeans(context.Background(), 2, clusterFunc); err != nil {
+			t.Fatal(err)
+		}
+		for idx, group := range want {
+			if (got[idx] == got[0]) != (group == 0) {
+				t.Errorf("projection %v: item %d in cluster %d, want the same cluster as group %d", proj, idx, got[idx], group)
+			}
+		}
+		for n := 1; n < len(order); n++ {
+			if order[n-1] >= order[n] {
+				t.Errorf("projection %v: cluster function called not in index order: %v", proj, order)
+				break
+			}
+		}
+
+		seeds := []h3.Cell{
+			h3.LatLngToCell(h3.LatLng{Lat: 20, Lng: 20}, 5),
+			h3.LatLngToCell(h3.LatLng{Lat: 10, Lng: 10}, 5),
+			h3.LatLngToCell(h3.LatLng{Lat: -40, Lng: -40}, 5),
+		}
+		got = map[int]int{}
+		if err := index.ClusterKMeansWithSeeds(context.Background(), seeds, clusterFunc); err != nil {
+			t.Fatal(err)
+		}
+		for idx, group := range want {
+			if got[idx] != 1-group {
+				t.Errorf("projection %v: item %d with seeds in cluster %d, want %d", proj, idx, got[idx], 1-group)
+			}
+		}
+	}
+
+	index := NewIndex()
+	index.Insert(0, orb.Point{10, 10})
+	index.Insert(1, orb.Point{10, 10})
+	got := map[int]int{}
+	if err := index.ClusterKMeans(context.Background(), 3, func(clusterIdx, idx int) { got[idx] = clusterIdx }); err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != 0 || got[1] != 0 {
+		t.Errorf("equal points should be in the same cluster, got %v", got)
+	}
+	if err := index.ClusterKMeans(context.Background(), 0, func(int, int) {}); !errors.Is(err, ErrInvalidClusters) {
+		t.Errorf("ClusterKMeans() with zero clusters error = %v, want %v", err, ErrInvalidClusters)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := index.ClusterKMeans(ctx, 1, func(int, int) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("ClusterKMeans() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,168 @@
+package geobin
+
+import (
+	"context"
+	"time"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/VGSML/geobin/orbf"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+// QueryKind kind of the index query.
+type QueryKind int
+
+const (
+	IntersectionQuery QueryKind = iota + 1
+	ContainsInQuery
+)
+
+func (k QueryKind) String() string {
+	switch k {
+	case IntersectionQuery:
+		return "IntersectionWith"
+	case ContainsInQuery:
+		return "ContainsInItems"
+	}
+	return "unknown"
+}
+
+// QueryReport explains execution of the index query.
+type QueryReport struct {
+	Kind QueryKind `json:"kind"`
+	// QueryCells count of cells generated for the query geometry.
+	QueryCells int `json:"query_cells"`
+	// Candidates count of items selected by the bitmap index.
+	Candidates uint64 `json:"candidates"`
+	// Matches count of items that passed the refinement by geometries.
+	Matches int `json:"matches"`
+	// Reprojected count of geometries reprojected between WGS84 and Mercator during the refinement.
+	Reprojected int `json:"reprojected"`
+	// Split count of geometries split at antimeridian during the refinement.
+	Split int `json:"split"`
+
+	CellsTime  time.Duration `json:"cells_time"`
+	BitmapTime time.Duration `json:"bitmap_time"`
+	RefineTime time.Duration `json:"refine_time"`
+}
+
+// Total returns total time of the query.
+func (r QueryReport) Total() time.Duration {
+	return r.CellsTime + r.BitmapTime + r.RefineTime
+}
+
+// QueryTraceFunc is a function that will be called with report after each index query.
+type QueryTraceFunc func(ctx context.Context, report QueryReport)
+
+// WithQueryTrace sets function to trace index queries.
+func WithQueryTrace(traceFunc QueryTraceFunc) IndexOptions {
+	return func(index *Index) {
+		index.traceFunc = traceFunc
+	}
+}
+
+// ExplainContainsInItems returns items contains in given geometry and report of the query execution.
+func (i *Index) ExplainContainsInItems(ctx context.Context, in orb.Geometry) ([]int, QueryReport) {
+	return i.query(ctx, in, ContainsInQuery, true)
+}
+
+// ExplainIntersectionWith returns items that intersects with given geometry and report of the query execution.
+func (i *Index) ExplainIntersectionWith(ctx context.Context, in orb.Geometry) ([]int, QueryReport) {
+	return i.query(ctx, in, IntersectionQuery, true)
+}
+
+// query selects candidates from the bitmap index and refines them by items.
+// If explain is true collects the query report and calls trace function.
+func (i *Index) query(ctx context.Context, in orb.Geometry, kind QueryKind, explain bool) ([]int, QueryReport) {
+	report := QueryReport{Kind: kind}
+	var stats *queryStats
+	if explain {
+		stats = &queryStats{}
+		ctx = context.WithValue(ctx, queryStatsKey{}, stats)
+	}
+	start := time.Now()
+
+	inItem := i.newItemFunc(0, in, i.res, i.proj)
+	cells := inItem.indexedCells()
+	report.QueryCells = len(cells)
+	report.CellsTime = time.Since(start)
+	start = time.Now()
+
+	var m *roaring64.Bitmap
+	if kind == ContainsInQuery {
+		m = i.bitmap.ContainsInItems(cells)
+	} else {
+		m = i.bitmap.Intersection(cells)
+	}
+	report.Candidates = m.GetCardinality()
+	report.BitmapTime = time.Since(start)
+	start = time.Now()
+
+	it := m.Iterator()
+	var out []int
+	for it.HasNext() {
+		id := int(it.Next())
+		item, ok := i.items[id]
+		if !ok {
+			continue
+		}
+		if kind == ContainsInQuery && item.ContainsIn(ctx, inItem) ||
+			kind == IntersectionQuery && item.Intersects(ctx, inItem) {
+			out = append(out, id)
+		}
+	}
+	report.RefineTime = time.Since(start)
+	report.Matches = len(out)
+	if !explain {
+		return out, report
+	}
+	report.Reprojected = stats.reprojected
+	report.Split = stats.split
+	if i.traceFunc != nil {
+		i.traceFunc(ctx, report)
+	}
+	return out, report
+}
+
+type queryStatsKey struct{}
+
+// queryStats collects statistics of the query during items refinement.
+type queryStats struct {
+	reprojected int
+	split       int
+}
+
+// addReprojected adds count of reprojected geometries to the query statistics.
+func addReprojected(ctx context.Context, n int) {
+	if stats, ok := ctx.Value(queryStatsKey{}).(*queryStats); ok {
+		stats.reprojected += n
+	}
+}
+
+// toMercator returns copy of WGS84 geometry projected to Mercator and counts it in the query statistics.
+func toMercator(ctx context.Context, geom orb.Geometry) orb.Geometry {
+	addReprojected(ctx, 1)
+	return project.Geometry(orb.Clone(geom), project.WGS84.ToMercator)
+}
+
+// overlayPair prepares WGS84 geometries to planar predicates as orbf does
+// and counts split and reprojected geometries in the query statistics.
+func overlayPair(ctx context.Context, a, b orb.Geometry) (orb.Geometry, orb.Geometry) {
+	geom1, geom2, report := orbf.PrepareOverlay(a, b)
+	stats, ok := ctx.Value(queryStatsKey{}).(*queryStats)
+	if !ok {
+		return geom1, geom2
+	}
+	for _, done := range []bool{report.ProjectedA, report.ProjectedB} {
+		if done {
+			stats.reprojected++
+		}
+	}
+	for _, done := range []bool{report.SplitA, report.SplitB} {
+		if done {
+			stats.split++
+		}
+	}
+	return geom1, geom2
+}
@@ -0,0 +1,78 @@
+package geobin
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+func TestIndex_ExplainIntersectionWith(t *testing.T) {
+	var traced []QueryReport
+	index := NewIndex(WithMaxResolution(9), WithQueryTrace(func(_ context.Context, report QueryReport) {
+		traced = append(traced, report)
+	}))
+	for idx, poly := range gridSquares(orb.Point{10, 10}, 3, 0.01) {
+		index.Insert(idx, poly)
+	}
+	area := orb.Bound{Min: orb.Point{10.005, 10.005}, Max: orb.Point{10.015, 10.015}}
+
+	got, report := index.ExplainIntersectionWith(context.Background(), area)
+	slices.Sort(got)
+	if !slices.Equal(got, []int{0, 1, 3, 4}) {
+		t.Errorf("ExplainIntersectionWith() got %v, want [0 1 3 4]", got)
+	}
+	if report.Kind != IntersectionQuery || report.QueryCells == 0 || report.Matches != len(got) ||
+		report.Candidates < uint64(report.Matches) {
+		t.Errorf("ExplainIntersectionWith() unexpected report %+v", report)
+	}
+	// both geometries of each candidate are projected to Mercator by the refinement
+	if report.Reprojected != 2*int(report.Candidates) {
+		t.Errorf("ExplainIntersectionWith() reprojected %d geometries for %d candidates", report.Reprojected, report.Candidates)
+	}
+	if report.Total() < report.RefineTime {
+		t.Errorf("Total() is less than refinement time")
+	}
+
+	ids := index.IntersectionWith(context.Background(), area)
+	if len(traced) != 2 || len(ids) != len(got) || traced[0].Matches != report.Matches || traced[1].Matches != report.Matches {
+		t.Errorf("trace function called %d times with %+v", len(traced), traced)
+	}
+
+	got, report = index.ExplainContainsInItems(context.Background(), gridSquares(orb.Point{10.01, 10.01}, 1, 0.01)[0])
+	if !slices.Equal(got, []int{4}) || report.Kind != ContainsInQuery || report.Matches != 1 {
+		t.Errorf("ExplainContainsInItems() got %v, report %+v", got, report)
+	}
+
+	polar := NewIndex(WithMaxResolution(5))
+	polar.Insert(1, orb.Bound{Min: orb.Point{10, 86}, Max: orb.Point{12, 87}})
+	got, report = polar.ExplainIntersectionWith(context.Background(), orb.Point{11, 86.5})
+	if !slices.Equal(got, []int{1}) || report.Reprojected != 0 {
+		t.Errorf("ExplainIntersectionWith() of polar items got %v, reprojected %d, want [1], 0", got, report.Reprojected)
+	}
+
+	// the item crossing antimeridian is split before projection, the query point is not
+	crossing := NewIndex(WithMaxResolution(7))
+	crossing.Insert(1, orb.Polygon{{{179.9, 10}, {-179.9, 10}, {-179.9, 10.1}, {179.9, 10.1}, {179.9, 10}}})
+	got, report = crossing.ExplainIntersectionWith(context.Background(), orb.Point{-179.95, 10.05})
+	if !slices.Equal(got, []int{1}) || report.Split != 1 || report.Reprojected != 2 {
+		t.Errorf("ExplainIntersectionWith() of item crossing antimeridian got %v, report %+v", got, report)
+	}
+}
+
+func TestIndex_ExplainMercator(t *testing.T) {
+	index := NewIndex(WithMercatorProjection(), WithMaxResolution(9))
+	for idx, poly := range gridSquares(orb.Point{10, 10}, 2, 0.01) {
+		index.Insert(idx, project.Polygon(poly, project.WGS84.ToMercator))
+	}
+	area := project.Bound(orb.Bound{Min: orb.Point{10.005, 10.005}, Max: orb.Point{10.015, 10.015}}, project.WGS84.ToMercator)
+	got, report := index.ExplainIntersectionWith(context.Background(), area)
+	if len(got) != 4 || report.Matches != 4 {
+		t.Errorf("ExplainIntersectionWith() got %v, report %+v", got, report)
+	}
+	if report.Reprojected != 0 {
+		t.Errorf("ExplainIntersectionWith() in Mercator reprojected %d geometries, want 0", report.Reprojected)
+	}
+}
@@ -0,0 +1,86 @@
+package geobin
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"github.com/VGSML/geobin/bjoin"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/project"
+)
+
+// JoinPropertiesFunc returns properties of the GeoJSON feature for item a and joined items b.
+type JoinPropertiesFunc func(ctx context.Context, a int, b []int) geojson.Properties
+
+// WriteJoinGeoJSON writes results of the join as GeoJSON FeatureCollection to w.
+// The join should be made with the index as A side. Each feature has geometry of item a,
+// resolved through the index items, and properties returned by propsFunc.
+// If propsFunc is nil, properties contain ids of item a and joined items b.
+// Items that doesn't store geometry are written with null geometry.
+func (i *Index) WriteJoinGeoJSON(ctx context.Context, w io.Writer, join *bjoin.Index, propsFunc JoinPropertiesFunc) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if propsFunc == nil {
+		propsFunc = joinIdsProperties
+	}
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(`{"type":"FeatureCollection","features":[`); err != nil {
+		return err
+	}
+	first := true
+	for pair := range join.PairsGen(ctx) {
+		b := make([]int, 0, len(pair.B))
+		for _, id := range pair.B {
+			b = append(b, int(id))
+		}
+		f := geojson.NewFeature(i.itemGeometryWGS84(int(pair.A)))
+		f.Properties = propsFunc(ctx, int(pair.A), b)
+		data, err := f.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if !first {
+			if err := bw.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := bw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, err := bw.WriteString("]}"); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// itemGeometryWGS84 returns geometry of the item in WGS84 projection or nil if item doesn't store geometry.
+func (i *Index) itemGeometryWGS84(idx int) orb.Geometry {
+	item, ok := i.items[idx]
+	if !ok {
+		return nil
+	}
+	g, ok := item.(Geometry)
+	if !ok {
+		return nil
+	}
+	if i.proj == Mercator {
+		return project.Geometry(orb.Clone(g.Geom()), project.Mercator.ToWGS84)
+	}
+	return g.Geom()
+}
+
+// joinIdsProperties returns properties with ids of joined items.
+func joinIdsProperties(_ context.Context, a int, b []int) geojson.Properties {
+	return geojson.Properties{
+		"a": a,
+		"b": b,
+	}
+}
@@ -0,0 +1,104 @@
+package geobin
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/VGSML/geobin/bjoin"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/project"
+)
+
+func TestIndex_WriteJoinGeoJSON(t *testing.T) {
+	polys := []orb.Polygon{
+		{{{10, 10}, {10.1, 10}, {10.1, 10.1}, {10, 10.1}, {10, 10}}},
+		{{{20, 20}, {20.1, 20}, {20.1, 20.1}, {20, 20.1}, {20, 20}}},
+	}
+	for _, proj := range []Projection{WGS84, Mercator} {
+		var options []IndexOptions
+		if proj == Mercator {
+			options = append(options, WithMercatorProjection())
+		}
+		index := NewIndex(options...)
+		for idx, poly := range polys {
+			geom := orb.Geometry(poly)
+			if proj == Mercator {
+				geom = project.Geometry(orb.Clone(poly), project.WGS84.ToMercator)
+			}
+			index.Insert(idx, geom)
+		}
+		join := bjoin.New(10)
+		join.AddPairs(roaring64.BitmapOf(0), roaring64.BitmapOf(3, 5))
+		join.AddPairs(roaring64.BitmapOf(1), nil)
+
+		var buf bytes.Buffer
+		if err := index.WriteJoinGeoJSON(context.Background(), &buf, join, nil); err != nil {
+			t.Fatal(err)
+		}
+		fc, err := geojson.UnmarshalFeatureCollection(buf.Bytes())
+		if err != nil {
+			t.Fatalf("WriteJoinGeoJSON() writes invalid GeoJSON: %v", err)
+		}
+		if len(fc.Features) != 2 {
+			t.Fatalf("WriteJoinGeoJSON() writes %d features, want 2", len(fc.Features))
+		}
+		for n, f := range fc.Features {
+			if a := f.Properties.MustInt("a"); a != n {
+				t.Errorf("feature %d has a = %d", n, a)
+			}
+			if !boundAlmostEqual(f.Geometry.Bound(), polys[n].Bound()) {
+				t.Errorf("feature %d has geometry %v, want %v", n, f.Geometry, polys[n])
+			}
+		}
+		if b, ok := fc.Features[0].Properties["b"].([]interface{}); !ok || len(b) != 2 || b[0] != 3. || b[1] != 5. {
+			t.Errorf("feature 0 has b = %v, want [3 5]", fc.Features[0].Properties["b"])
+		}
+		if b, ok := fc.Features[1].Properties["b"].([]interface{}); !ok || len(b) != 0 {
+			t.Errorf("feature 1 has b = %v, want []", fc.Features[1].Properties["b"])
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		buf.Reset()
+		if err := index.WriteJoinGeoJSON(ctx, &buf, join, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("WriteJoinGeoJSON() with canceled context error = %v, want %v", err, context.Canceled)
+		}
+	}
+}
+
+func TestIndex_WriteJoinGeoJSONProperties(t *testing.T) {
+	index := NewIndex(WithIndexedItems(false), WithMaxResolution(5))
+	index.Insert(1, orb.Point{10, 10})
+	join := bjoin.New(10)
+	join.AddPairs(roaring64.BitmapOf(1), roaring64.BitmapOf(2))
+
+	var buf bytes.Buffer
+	err := index.WriteJoinGeoJSON(context.Background(), &buf, join, func(_ context.Context, a int, b []int) geojson.Properties {
+		return geojson.Properties{"count": len(b)}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fc, err := geojson.UnmarshalFeatureCollection(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// indexed items don't store geometry
+	if len(fc.Features) != 1 || fc.Features[0].Geometry != nil || fc.Features[0].Properties.MustInt("count") != 1 {
+		t.Errorf("WriteJoinGeoJSON() writes unexpected features %s", buf.String())
+	}
+}
+
+func boundAlmostEqual(a, b orb.Bound) bool {
+	const eps = 1e-9
+	for _, d := range []float64{a.Min[0] - b.Min[0], a.Min[1] - b.Min[1], a.Max[0] - b.Max[0], a.Max[1] - b.Max[1]} {
+		if d > eps || d < -eps {
+			return false
+		}
+	}
+	return true
+}
@@ -2,11 +2,18 @@ package geobin
 
 import (
 	"context"
+	"runtime"
+	"sync"
 
 	"github.com/RoaringBitmap/roaring"
+	"github.com/RoaringBitmap/roaring/roaring64"
 	"github.com/VGSML/geobin/bjoin"
 	"github.com/VGSML/geobin/h3b"
+	"github.com/VGSML/geobin/h3f"
+	"github.com/VGSML/geobin/orbf"
+	"github.com/VGSML/geobin/orbf/planar"
 	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
 	"github.com/uber/h3-go/v4"
 )
 
@@ -32,15 +39,26 @@ type Index struct {
 	res         int
 	newItemFunc func(idx int, geom orb.Geometry, res int, proj Projection) Item
 	items       map[int]Item
+	traceFunc   QueryTraceFunc
+	validate    bool
+	repair      bool
+	rejectFunc  RejectFunc
 }
 
 type IndexOptions func(index *Index)
 
 // Use full h3 indexed items instead only contains cells indexed item.
+// Polygons are indexed by all overlapping cells.
 func WithIndexedItems(compact bool) IndexOptions {
+	return WithIndexedItemsContainment(compact, h3f.ContainmentOverlap)
+}
+
+// WithIndexedItemsContainment use full h3 indexed items, polygons are indexed by cells
+// selected by given containment mode.
+func WithIndexedItemsContainment(compact bool, mode h3f.Containment) IndexOptions {
 	return func(index *Index) {
 		index.newItemFunc = func(idx int, geom orb.Geometry, res int, proj Projection) Item {
-			return newIndexedItem(idx, geom, res, compact, index.proj)
+			return newIndexedItem(idx, geom, res, compact, mode, index.proj)
 		}
 
 	}
@@ -53,6 +71,36 @@ func WithCustomIndexedItems(newItemFunc func(idx int, geom orb.Geometry, res int
 	}
 }
 
+// WithBoundCoverer use bound indexed items, that indexed by covering of item bound
+// by cells of mixed resolutions instead of common parent or corner cells.
+// The maximal resolution of coverer is limited by the index resolution.
+func WithBoundCoverer(coverer h3f.Coverer) IndexOptions {
+	return func(index *Index) {
+		index.newItemFunc = func(idx int, geom orb.Geometry, res int, proj Projection) Item {
+			return newCoveredItem(idx, geom, geom.Bound(), coverResLimit(coverer, res), proj)
+		}
+	}
+}
+
+// WithGeometryCoverer use bound indexed items, that indexed by covering of item geometry by cells of mixed resolutions.
+// The maximal resolution of coverer is limited by the index resolution.
+func WithGeometryCoverer(coverer h3f.Coverer) IndexOptions {
+	return func(index *Index) {
+		index.newItemFunc = func(idx int, geom orb.Geometry, res int, proj Projection) Item {
+			return newCoveredItem(idx, geom, geom, coverResLimit(coverer, res), proj)
+		}
+	}
+}
+
+// coverResLimit returns copy of the coverer with maximal resolution limited by res,
+// coverer is copied because item function is called concurrently by InsertMany.
+func coverResLimit(coverer h3f.Coverer, res int) h3f.Coverer {
+	if coverer.MaxRes <= 0 || coverer.MaxRes > res {
+		coverer.MaxRes = res
+	}
+	return coverer
+}
+
 // Sets maximal h3 resolution (1..14).
 func WithMaxResolution(res int) IndexOptions {
 	return func(index *Index) {
@@ -67,6 +115,22 @@ func WithMercatorProjection() IndexOptions {
 	}
 }
 
+// RejectFunc is a function that will be called for each geometry rejected by validation on insert.
+type RejectFunc func(idx int, geom orb.Geometry, reason planar.ValidityReason)
+
+// WithValidation checks geometries by planar.IsValid on insert, if repair is set invalid geometries are
+// repaired by planar.MakeValid. WGS84 geometries are checked split at antimeridian.
+// Geometries that are invalid or empty after repair are not inserted
+// and reject function is called for them, reject function can be nil.
+// Reject function is called concurrently by InsertMany, so it should be safe for concurrent use.
+func WithValidation(repair bool, rejectFunc RejectFunc) IndexOptions {
+	return func(index *Index) {
+		index.validate = true
+		index.repair = repair
+		index.rejectFunc = rejectFunc
+	}
+}
+
 // New creates new index with options.
 func NewIndex(options ...IndexOptions) *Index {
 	index := &Index{
@@ -83,7 +147,12 @@ func NewIndex(options ...IndexOptions) *Index {
 }
 
 // Insert adds element to index.
+// With validation option invalid geometry is repaired or rejected.
 func (i *Index) Insert(idx int, item orb.Geometry) {
+	item, ok := i.validGeometry(idx, item)
+	if !ok {
+		return
+	}
 	indexItem := i.newItemFunc(idx, item, int(i.bitmap.Res()), i.proj)
 	for _, cell := range indexItem.indexedCells() {
 		i.bitmap.Insert(uint64(idx), cell)
@@ -91,6 +160,86 @@ func (i *Index) Insert(idx int, item orb.Geometry) {
 	i.items[idx] = indexItem
 }
 
+// IndexGeometry represents geometry with item index.
+type IndexGeometry struct {
+	Idx  int
+	Geom orb.Geometry
+}
+
+// InsertMany adds elements to index.
+// Index items are created concurrently, so custom item function (WithCustomIndexedItems) should be safe for concurrent use.
+func (i *Index) InsertMany(ctx context.Context, geoms []IndexGeometry) error {
+	items := make([]Item, len(geoms))
+	cells := make([]h3b.ItemCells, len(geoms))
+	workers := runtime.GOMAXPROCS(0)
+	res := int(i.bitmap.Res())
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for n := w; n < len(geoms); n += workers {
+				if ctx.Err() != nil {
+					return
+				}
+				geom, ok := i.validGeometry(geoms[n].Idx, geoms[n].Geom)
+				if !ok {
+					continue
+				}
+				items[n] = i.newItemFunc(geoms[n].Idx, geom, res, i.proj)
+				cells[n] = h3b.ItemCells{
+					Idx:   uint64(geoms[n].Idx),
+					Cells: items[n].indexedCells(),
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	inserted := cells[:0]
+	for n, item := range items {
+		if item == nil {
+			continue
+		}
+		inserted = append(inserted, cells[n])
+		i.items[geoms[n].Idx] = item
+	}
+	i.bitmap.InsertMany(inserted)
+	return nil
+}
+
+// validGeometry returns geometry to insert and false if geometry is rejected by validation.
+// WGS84 geometries are checked in parts split at antimeridian, so geometries that cross it
+// aren't self-intersecting in planar coordinates, repaired geometries are returned split.
+func (i *Index) validGeometry(idx int, geom orb.Geometry) (orb.Geometry, bool) {
+	if !i.validate {
+		return geom, true
+	}
+	checked := geom
+	if i.proj == WGS84 {
+		checked = orbf.SplitAntimeridian(geom)
+	}
+	valid, reason := planar.IsValid(checked)
+	if valid {
+		return geom, true
+	}
+	if i.repair {
+		if repaired := planar.MakeValid(checked); repaired != nil {
+			if valid, reason = planar.IsValid(repaired); valid {
+				return repaired, true
+			}
+		}
+	}
+	if i.rejectFunc != nil {
+		i.rejectFunc(idx, geom, reason)
+	}
+	return nil, false
+}
+
 func (i *Index) Projection() Projection {
 	return i.proj
 }
@@ -112,39 +261,54 @@ func (i *Index) Remove(idx int) bool {
 	return i.bitmap.Remove(uint64(idx))
 }
 
+// RemoveMany delete indexed elements, returns true if at least one element was removed.
+func (i *Index) RemoveMany(ids []int) bool {
+	bm := roaring64.New()
+	for _, idx := range ids {
+		delete(i.items, idx)
+		bm.Add(uint64(idx))
+	}
+	return i.bitmap.RemoveMany(bm)
+}
+
+// Compact releases unused memory of the index after removing elements.
+func (i *Index) Compact() {
+	i.bitmap.Compact()
+}
+
 // ContainsInItems returns items contains in given geometry
 func (i *Index) ContainsInItems(ctx context.Context, in orb.Geometry) []int {
-	inItem := i.newItemFunc(0, in, i.res, i.proj)
-	m := i.bitmap.ContainsInItems(inItem.indexedCells())
-	it := m.Iterator()
-	var out []int
-	for it.HasNext() {
-		id := int(it.Next())
-		item, ok := i.items[id]
-		if !ok {
-			continue
-		}
-		if item.ContainsIn(ctx, inItem) {
-			out = append(out, id)
-		}
-	}
+	out, _ := i.query(ctx, in, ContainsInQuery, i.traceFunc != nil)
 	return out
 }
 
 // IntersectionWith returns items that intersects with given geometry
 func (i *Index) IntersectionWith(ctx context.Context, in orb.Geometry) []int {
-	inItem := i.newItemFunc(0, in, i.res, i.proj)
-	m := i.bitmap.Intersection(inItem.indexedCells())
-	it := m.Iterator()
-	var out []int
-	for it.HasNext() {
-		id := int(it.Next())
-		item, ok := i.items[id]
-		if !ok {
-			continue
+	out, _ := i.query(ctx, in, IntersectionQuery, i.traceFunc != nil)
+	return out
+}
+
+// WithinDistance returns items within distance in meters of given geometry.
+// Geometry is in the index projection, candidates intersecting the buffer of geometry
+// are refined by orbf.Distance, items that don't store geometry are not refined.
+func (i *Index) WithinDistance(ctx context.Context, in orb.Geometry, meters float64) []int {
+	wgs := in
+	if i.proj == Mercator {
+		wgs = project.Geometry(orb.Clone(in), project.Mercator.ToWGS84)
+	}
+	search := in
+	if meters > 0 {
+		search = orbf.Buffer(wgs, meters, planar.BufferOptions{})
+		if i.proj == Mercator {
+			search = project.Geometry(search, project.WGS84.ToMercator)
 		}
-		if item.Intersects(ctx, inItem) {
-			out = append(out, id)
+	}
+	candidates := i.IntersectionWith(ctx, search)
+	out := candidates[:0]
+	for _, idx := range candidates {
+		geom := i.itemGeometryWGS84(idx)
+		if geom == nil || orbf.Distance(wgs, geom) <= meters {
+			out = append(out, idx)
 		}
 	}
 	return out
@@ -171,16 +335,6 @@ func (i *Index) ClusterIntersects(ctx context.Context, clusterFunc ClusterFunc)
 	return nil
 }
 
-// ClusterKMeans perform clustering of indexed items by k-means algorithm.
-func (i *Index) ClusterKMeans(ctx context.Context, clusters int, clusterFunc ClusterFunc) error {
-	return nil
-}
-
-// ClusterKMeansWithSeeds perform clustering of indexed items by k-means algorithm with given seeds.
-func (i *Index) ClusterKMeansWithSeeds(ctx context.Context, seeds []h3.Cell, clusterFunc ClusterFunc) error {
-	return nil
-}
-
 // ClusterDBSCAN perform clustering of indexed items by DBSCAN algorithm.
 func (i *Index) ClusterDBSCAN(ctx context.Context, eps float64, minPts int, clusterFunc ClusterFunc) error {
 	return nil
@@ -25,28 +25,57 @@ type Item interface {
 }
 
 func newBoundIndexedItem(idx int, geom orb.Geometry, res int, proj Projection) Item {
-	bound := geom.Bound()
+	wgs := geom
 	if proj == Mercator {
-		bound = project.Bound(bound, project.Mercator.ToWGS84)
+		wgs = project.Geometry(orb.Clone(geom), project.Mercator.ToWGS84)
 	}
+	var pc []h3.Cell
+	// index each part of geometry that crosses antimeridian by its own bound
+	switch g := orbf.SplitAntimeridian(wgs).(type) {
+	case orb.MultiLineString:
+		for _, l := range g {
+			pc = append(pc, boundCells(l.Bound(), res)...)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			pc = append(pc, boundCells(p.Bound(), res)...)
+		}
+	case orb.Collection:
+		for _, g := range g {
+			pc = append(pc, boundCells(g.Bound(), res)...)
+		}
+	default:
+		pc = boundCells(g.Bound(), res)
+	}
+
+	return &BoundIndexedItem{
+		proj:      proj,
+		idx:       idx,
+		geom:      geom,
+		baseCells: pc,
+	}
+}
+
+// boundCells returns common parent cell of the bound corners or both corner cells.
+func boundCells(bound orb.Bound, res int) []h3.Cell {
 	cell1 := h3.LatLngToCell(h3.LatLng{Lat: bound.Min.Lat(), Lng: bound.Min.Lon()}, res)
 	cell2 := h3.LatLngToCell(h3.LatLng{Lat: bound.Max.Lat(), Lng: bound.Max.Lon()}, res)
-	pc := []h3.Cell{cell1}
-	cell := h3f.ParentIndex(
-		cell1,
-		cell2,
-	)
-	if cell != 0 {
-		pc[0] = cell
-	} else {
-		pc = append(pc, cell2)
+	if cell := h3f.ParentIndex(cell1, cell2); cell != 0 {
+		return []h3.Cell{cell}
 	}
+	return []h3.Cell{cell1, cell2}
+}
 
+// newCoveredItem returns bound indexed item, that indexed by cells covering given geometry.
+func newCoveredItem(idx int, geom, cover orb.Geometry, coverer h3f.Coverer, proj Projection) Item {
+	if proj == Mercator {
+		cover = project.Geometry(orb.Clone(cover), project.Mercator.ToWGS84)
+	}
 	return &BoundIndexedItem{
 		proj:      proj,
 		idx:       idx,
 		geom:      geom,
-		baseCells: pc,
+		baseCells: coverer.Cover(cover),
 	}
 }
 
@@ -73,25 +102,22 @@ func (item *BoundIndexedItem) Intersects(ctx context.Context, in Item) bool {
 	switch in := in.(type) {
 	case *BoundIndexedItem:
 		if item.proj == WGS84 && item.proj == in.proj {
-			return orbf.Intersects(item.geom, in.geom)
+			geom1, geom2 := overlayPair(ctx, item.geom, in.geom)
+			return geom1 != nil && geom2 != nil && planar.Intersects(geom1, geom2)
 		}
 		if item.proj == Mercator && item.proj == in.proj {
 			return planar.Intersects(item.geom, in.geom)
 		}
 		if item.proj == Mercator {
-			return planar.Intersects(item.geom,
-				project.Geometry(orb.Clone(in.geom), project.WGS84.ToMercator),
-			)
+			return planar.Intersects(item.geom, toMercator(ctx, in.geom))
 		}
-		return planar.Intersects(
-			project.Geometry(orb.Clone(item.geom), project.WGS84.ToMercator),
-			in.geom,
-		)
+		return planar.Intersects(toMercator(ctx, item.geom), in.geom)
 	case Geometry:
 		if item.proj == Mercator {
 			return planar.Intersects(item.geom, in.Geom())
 		}
-		return orbf.Intersects(item.geom, in.Geom())
+		geom1, geom2 := overlayPair(ctx, item.geom, in.Geom())
+		return geom1 != nil && geom2 != nil && planar.Intersects(geom1, geom2)
 	default:
 		return false
 	}
@@ -101,25 +127,22 @@ func (item *BoundIndexedItem) ContainsIn(ctx context.Context, in Item) bool {
 	switch in := in.(type) {
 	case *BoundIndexedItem:
 		if item.proj == WGS84 && item.proj == in.proj {
-			return orbf.Contains(item.geom, in.geom)
+			geom1, geom2 := overlayPair(ctx, item.geom, in.geom)
+			return geom1 != nil && geom2 != nil && planar.Contains(geom1, geom2)
 		}
 		if item.proj == Mercator && item.proj == in.proj {
 			return planar.Contains(item.geom, in.geom)
 		}
 		if item.proj == Mercator {
-			return planar.Contains(item.geom,
-				project.Geometry(orb.Clone(in.geom), project.WGS84.ToMercator),
-			)
+			return planar.Contains(item.geom, toMercator(ctx, in.geom))
 		}
-		return planar.Contains(
-			project.Geometry(orb.Clone(item.geom), project.WGS84.ToMercator),
-			in.geom,
-		)
+		return planar.Contains(toMercator(ctx, item.geom), in.geom)
 	case Geometry:
 		if item.proj == Mercator {
 			return planar.Contains(item.geom, in.Geom())
 		}
-		return orbf.Contains(item.geom, in.Geom())
+		geom1, geom2 := overlayPair(ctx, item.geom, in.Geom())
+		return geom1 != nil && geom2 != nil && planar.Contains(geom1, geom2)
 	default:
 		return false
 	}
@@ -130,12 +153,12 @@ type IndexedItem struct {
 	index *h3b.Index // for single geometry item - point, line, polygon
 }
 
-func newIndexedItem(idx int, geom orb.Geometry, res int, compact bool, proj Projection) Item {
+func newIndexedItem(idx int, geom orb.Geometry, res int, compact bool, mode h3f.Containment, proj Projection) Item {
 	if proj == Mercator {
 		geom = project.Geometry(orb.Clone(geom), project.Mercator.ToWGS84)
 	}
 	bm := h3b.New(res)
-	for i, cell := range h3f.GeometryCells(geom, res, compact) {
+	for i, cell := range h3f.GeometryCellsContainment(geom, res, compact, mode) {
 		bm.Insert(uint64(i), cell)
 	}
 	return &IndexedItem{
@@ -0,0 +1,176 @@
+package geobin
+
+import (
+	"context"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/VGSML/geobin/h3f"
+	"github.com/VGSML/geobin/internal/fixture"
+	"github.com/VGSML/geobin/orbf"
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/paulmach/orb"
+)
+
+func TestIndex_RemoveMany(t *testing.T) {
+	index := NewIndex(WithGeometryCoverer(h3f.Coverer{MaxCells: 64, MaxRes: 9}))
+	for idx, poly := range gridSquares(orb.Point{10, 10}, 2, 0.01) {
+		index.Insert(idx, poly)
+	}
+	area := orb.Bound{Min: orb.Point{10.005, 10.005}, Max: orb.Point{10.015, 10.015}}.ToPolygon()
+
+	if index.RemoveMany([]int{10, 11}) {
+		t.Error("RemoveMany() returns true for not indexed items")
+	}
+	if !index.RemoveMany([]int{1, 3}) {
+		t.Error("RemoveMany() returns false for indexed items")
+	}
+	got := index.IntersectionWith(context.Background(), area)
+	slices.Sort(got)
+	if !slices.Equal(got, []int{0, 2}) {
+		t.Errorf("IntersectionWith() after RemoveMany() got %v, want [0 2]", got)
+	}
+
+	index.SetMaxItemIndex(7)
+	index.RemoveMany([]int{2})
+	index.Compact()
+	if index.MaxItemIndex() != 7 {
+		t.Errorf("MaxItemIndex() after Compact() got %d, want 7", index.MaxItemIndex())
+	}
+	if got := index.IntersectionWith(context.Background(), area); !slices.Equal(got, []int{0}) {
+		t.Errorf("IntersectionWith() after Compact() got %v, want [0]", got)
+	}
+	index.Remove(0)
+	index.Compact()
+	if got := index.IntersectionWith(context.Background(), area); len(got) != 0 {
+		t.Errorf("IntersectionWith() of empty index got %v", got)
+	}
+}
+
+// TestIndex_InsertMany should be run with -race, items are created by several workers.
+func TestIndex_InsertMany(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	polys := gridSquares(orb.Point{10, 10}, 10, 0.01)
+	geoms := make([]IndexGeometry, len(polys))
+	for idx, poly := range polys {
+		geoms[idx] = IndexGeometry{Idx: idx, Geom: poly}
+	}
+	for _, opt := range []IndexOptions{
+		WithBoundCoverer(h3f.Coverer{MaxCells: 16}),
+		WithGeometryCoverer(h3f.Coverer{MaxCells: 16, MaxRes: 12}),
+		WithIndexedItems(true),
+	} {
+		want := NewIndex(opt, WithMaxResolution(9))
+		for _, g := range geoms {
+			want.Insert(g.Idx, g.Geom)
+		}
+		got := NewIndex(opt, WithMaxResolution(9))
+		if err := got.InsertMany(context.Background(), geoms); err != nil {
+			t.Fatal(err)
+		}
+		for idx := range polys {
+			gotCells, wantCells := got.items[idx].indexedCells(), want.items[idx].indexedCells()
+			slices.Sort(gotCells)
+			slices.Sort(wantCells)
+			if !slices.Equal(gotCells, wantCells) {
+				t.Errorf("item %d cells got %v, want %v", idx, gotCells, wantCells)
+			}
+			for _, c := range gotCells {
+				if c.Resolution() > 9 {
+					t.Errorf("item %d cell %v has resolution greater than index resolution", idx, c)
+				}
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	index := NewIndex()
+	if err := index.InsertMany(ctx, geoms); err == nil {
+		t.Error("InsertMany() with canceled context returns nil error")
+	}
+}
+
+func TestIndex_WithValidation(t *testing.T) {
+	geoms := []IndexGeometry{
+		{Idx: 0, Geom: gridSquares(orb.Point{10, 10}, 1, 0.01)[0]},
+		{Idx: 1, Geom: orb.Polygon{{{10, 10}, {10.01, 10.01}, {10.01, 10}, {10, 10.01}, {10, 10}}}},
+		{Idx: 2, Geom: orb.Polygon{{{10, 10}, {10, 10.01}, {10.01, 10.01}, {10.01, 10}, {10, 10}}}},
+		{Idx: 3, Geom: fixture.Chukotka()},
+		{Idx: 4, Geom: orb.LineString{{10, 10}, {10, 10}}},
+	}
+	tests := []struct {
+		name     string
+		repair   bool
+		inserted []int
+		rejected map[int]planar.ValidityReason
+	}{
+		{
+			name:     "reject",
+			inserted: []int{0, 3},
+			rejected: map[int]planar.ValidityReason{
+				1: planar.ReasonSelfIntersection,
+				2: planar.ReasonWrongOrientation,
+				4: planar.ReasonTooFewPoints,
+			},
+		},
+		{name: "repair", repair: true, inserted: []int{0, 1, 2, 3, 4}, rejected: map[int]planar.ValidityReason{}},
+	}
+	for _, tt := range tests {
+		for _, many := range []bool{false, true} {
+			var mu sync.Mutex
+			inserted := map[int]orb.Geometry{}
+			rejected := map[int]planar.ValidityReason{}
+			index := NewIndex(
+				WithCustomIndexedItems(func(idx int, geom orb.Geometry, res int, proj Projection) Item {
+					mu.Lock()
+					defer mu.Unlock()
+					inserted[idx] = geom
+					return newBoundIndexedItem(idx, geom, res, proj)
+				}),
+				WithValidation(tt.repair, func(idx int, geom orb.Geometry, reason planar.ValidityReason) {
+					mu.Lock()
+					defer mu.Unlock()
+					rejected[idx] = reason
+				}),
+			)
+			if many {
+				if err := index.InsertMany(context.Background(), geoms); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				for _, g := range geoms {
+					index.Insert(g.Idx, g.Geom)
+				}
+			}
+			var got []int
+			for idx := range inserted {
+				if _, ok := index.items[idx]; ok {
+					got = append(got, idx)
+				}
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.inserted) {
+				t.Errorf("%s (many %v): inserted %v, want %v", tt.name, many, got, tt.inserted)
+			}
+			if len(rejected) != len(tt.rejected) {
+				t.Errorf("%s (many %v): rejected %v, want %v", tt.name, many, rejected, tt.rejected)
+			}
+			for idx, reason := range tt.rejected {
+				if rejected[idx] != reason {
+					t.Errorf("%s (many %v): item %d rejected with %q, want %q", tt.name, many, idx, rejected[idx], reason)
+				}
+			}
+			for idx, geom := range inserted {
+				if valid, reason := planar.IsValid(orbf.SplitAntimeridian(geom)); !valid {
+					t.Errorf("%s (many %v): item %d inserted invalid: %s", tt.name, many, idx, reason)
+				}
+			}
+			if _, ok := inserted[3].(orb.Polygon); !ok {
+				t.Errorf("%s (many %v): valid polygon across antimeridian should be inserted as is, got %v", tt.name, many, inserted[3])
+			}
+		}
+	}
+}
@@ -1,9 +1,13 @@
 package h3b
 
 import (
+	"sync"
+
 	"github.com/RoaringBitmap/roaring/roaring64"
 	"github.com/VGSML/geobin/h3f"
+	"github.com/paulmach/orb"
 	"github.com/uber/h3-go/v4"
+	"golang.org/x/exp/slices"
 )
 
 // Index bitmap index for h3.Cells.
@@ -12,6 +16,7 @@ type Index struct {
 	minRes       int8
 	baseCellsLen int8
 	maxItemIndex uint64
+	setItemIndex uint64 // maximum item index set by SetMaxItemIndex, it is kept by Compact
 
 	baseCellsMask *roaring64.Bitmap
 	baseCellMap   [122]*roaring64.Bitmap   // bitmaps for each base cell
@@ -39,6 +44,7 @@ func (i *Index) MaxItemIndex() uint64 {
 // SetMaxItemIndex SetMaxItemIndex maximum item index.
 // Sets and returns true if given index greatest or equal current maximum index.
 func (i *Index) SetMaxItemIndex(idx uint64) bool {
+	i.setItemIndex = max(i.setItemIndex, idx)
 	i.maxItemIndex = max(i.maxItemIndex, idx)
 	return i.maxItemIndex == idx
 }
@@ -67,26 +73,120 @@ func (i *Index) Insert(idx uint64, cell h3.Cell) {
 		}
 		i.resMaps[res][crn].Add(idx)
 	}
-	i.minRes = min(i.minRes, int8(cellRes))
-	if i.minRes == 0 {
-		i.minRes = 1
-	}
+	i.minRes = minCellRes(i.minRes, cellRes)
 	i.maxItemIndex = max(i.maxItemIndex, idx)
 }
 
+// minCellRes returns minimal resolution of indexed cells after adding cell of given resolution.
+// Resolution 0 is stored as 1, so 0 means index without cells.
+func minCellRes(minRes int8, cellRes int) int8 {
+	res := int8(max(cellRes, 1))
+	if minRes == 0 {
+		return res
+	}
+	return min(minRes, res)
+}
+
+// ItemCells represents cells of the indexed item.
+type ItemCells struct {
+	Idx   uint64
+	Cells []h3.Cell
+}
+
+// InsertMany adds cells of items to the index.
+// Items are bucketed by base cells and cell numbers, each bitmap is filled by single AddMany call.
+// Base cells bitmaps are filled in parallel for each base cell, resolution bitmaps - for each resolution.
+func (i *Index) InsertMany(items []ItemCells) {
+	var baseItems [122][]uint64
+	minRes := i.minRes
+	for _, item := range items {
+		for _, cell := range item.Cells {
+			bn := h3f.BaseCellNum(cell)
+			baseItems[bn] = append(baseItems[bn], item.Idx)
+			minRes = minCellRes(minRes, h3f.H3Res(cell))
+		}
+		i.maxItemIndex = max(i.maxItemIndex, item.Idx)
+	}
+	i.minRes = minRes
+
+	var wg sync.WaitGroup
+	for bn := range baseItems {
+		if len(baseItems[bn]) == 0 {
+			continue
+		}
+		if i.baseCellMap[bn] == nil {
+			i.baseCellMap[bn] = roaring64.New()
+			i.baseCellsLen += 1
+			i.baseCellsMask.Add(uint64(bn))
+		}
+		wg.Add(1)
+		go func(bm *roaring64.Bitmap, ids []uint64) {
+			defer wg.Done()
+			slices.Sort(ids)
+			bm.AddMany(ids)
+		}(i.baseCellMap[bn], baseItems[bn])
+	}
+	for res := 0; res < int(i.res); res++ {
+		wg.Add(1)
+		go func(res int) {
+			defer wg.Done()
+			i.insertManyRes(res, items)
+		}(res)
+	}
+	wg.Wait()
+}
+
+// insertManyRes adds items to the resolution bitmaps.
+func (i *Index) insertManyRes(res int, items []ItemCells) {
+	var cellItems [8][]uint64
+	for _, item := range items {
+		for _, cell := range item.Cells {
+			cellRes := h3f.H3Res(cell)
+			if cellRes < res {
+				continue
+			}
+			if cellRes == res {
+				cellItems[7] = append(cellItems[7], item.Idx)
+				continue
+			}
+			crn := h3f.CellIndexInRes(cell, res+1)
+			cellItems[crn] = append(cellItems[crn], item.Idx)
+		}
+	}
+	for cn, ids := range cellItems {
+		if len(ids) == 0 {
+			continue
+		}
+		if i.resMaps[res][cn] == nil {
+			i.resMaps[res][cn] = roaring64.New()
+		}
+		slices.Sort(ids)
+		i.resMaps[res][cn].AddMany(ids)
+	}
+}
+
 // Remove delete indexed element.
 func (i *Index) Remove(idx uint64) bool {
+	return i.RemoveMany(roaring64.BitmapOf(idx))
+}
+
+// RemoveMany delete indexed elements, returns true if at least one element was removed.
+// Empty base cells bitmaps are released, empty resolution bitmaps are kept until Compact.
+func (i *Index) RemoveMany(ids *roaring64.Bitmap) bool {
+	if ids == nil || ids.IsEmpty() {
+		return false
+	}
 	rm := false
 	for bn, r := range i.baseCellMap {
-		if r == nil {
+		if r == nil || !r.Intersects(ids) {
 			continue
 		}
-		if r.Contains(idx) {
-			r.Remove(idx)
-			rm = true
-			if r.IsEmpty() {
-				i.baseCellsMask.Remove(uint64(bn))
-			}
+		r.AndNot(ids)
+		rm = true
+		if r.IsEmpty() {
+			i.baseCellMap[bn] = nil
+			i.baseCellsMask.Remove(uint64(bn))
+			i.baseCellsLen--
 		}
 	}
 	if !rm {
@@ -94,12 +194,71 @@ func (i *Index) Remove(idx uint64) bool {
 	}
 	for _, rm := range i.resMaps {
 		for _, r := range rm {
-			r.Remove(idx)
+			if r != nil && r.Intersects(ids) {
+				r.AndNot(ids)
+			}
 		}
 	}
 	return true
 }
 
+// Compact releases empty bitmaps, recomputes count of base cells, minimal resolution of cells
+// and maximum item index and optimizes bitmaps with run-length encoding.
+// Maximum item index isn't less than the index set by SetMaxItemIndex.
+func (i *Index) Compact() {
+	i.baseCellsLen = 0
+	i.maxItemIndex = i.setItemIndex
+	i.baseCellsMask.Clear()
+	for bn, r := range i.baseCellMap {
+		if r == nil {
+			continue
+		}
+		if r.IsEmpty() {
+			i.baseCellMap[bn] = nil
+			continue
+		}
+		i.baseCellsLen++
+		i.baseCellsMask.Add(uint64(bn))
+		i.maxItemIndex = max(i.maxItemIndex, r.Maximum())
+	}
+	for res := range i.resMaps {
+		for cn, r := range i.resMaps[res] {
+			if r != nil && r.IsEmpty() {
+				i.resMaps[res][cn] = nil
+			}
+		}
+	}
+	i.minRes = 0
+	if i.baseCellsLen != 0 {
+		// cells of the index resolution and higher are not marked in resMaps
+		i.minRes = minCellRes(0, int(i.res))
+		for res := 0; res < int(i.res); res++ {
+			if r := i.resMaps[res][7]; r != nil && !r.IsEmpty() {
+				i.minRes = minCellRes(0, res)
+				break
+			}
+		}
+	}
+	i.RunOptimize()
+}
+
+// RunOptimize optimizes bitmaps of the index with run-length encoding.
+func (i *Index) RunOptimize() {
+	i.baseCellsMask.RunOptimize()
+	for _, r := range i.baseCellMap {
+		if r != nil {
+			r.RunOptimize()
+		}
+	}
+	for _, rm := range i.resMaps {
+		for _, r := range rm {
+			if r != nil {
+				r.RunOptimize()
+			}
+		}
+	}
+}
+
 // BaseCellsCount returns count of different base cells
 func (i *Index) BaseCellsCount() int {
 	return int(i.baseCellsLen)
@@ -118,118 +277,150 @@ func (i *Index) BaseCellsNum() []int8 {
 
 // HasCell checks index has intersects point with cell.
 func (i *Index) HasCell(cell h3.Cell) bool {
+	return !i.ItemsInCell(cell).IsEmpty()
+}
+
+// ItemsInCell returns items that have intersects point with cell:
+// items with cells equal, covering (parent) or inside (children) given cell.
+// Digits of cells are stored by resolutions, so item with several cells
+// is also returned for cells that combine digits of its cells.
+func (i *Index) ItemsInCell(cell h3.Cell) *roaring64.Bitmap {
 	bn := h3f.BaseCellNum(cell)
 	if i.baseCellMap[bn] == nil {
-		return false
+		return roaring64.New()
 	}
 	cellRes := h3f.H3Res(cell)
 	base := i.baseCellMap[bn].Clone()
-
-	for r := i.minRes - 1; r < i.res && r < int8(cellRes); r++ {
-		crn := h3f.CellIndexInRes(cell, int(r+1))
-		resMap := i.resMaps[r][crn]
-		if resMap == nil {
-			resMap = roaring64.New()
+	// items with parent cells of given cell
+	parents := roaring64.New()
+	for r := 0; r < int(i.res) && r < cellRes; r++ {
+		if rm := i.resMaps[r][7]; rm != nil {
+			parents.Or(roaring64.And(base, rm))
 		}
-		if i.resMaps[r][7] != nil {
-			resMap.Or(i.resMaps[r][7])
+		rm := i.resMaps[r][h3f.CellIndexInRes(cell, r+1)]
+		if rm == nil {
+			base.Clear()
+			break
 		}
-		if resMap.IsEmpty() {
-			return false
+		base.And(rm)
+	}
+	base.Or(parents)
+	return base
+}
+
+// CellCounts returns count of items for each occupied cell in given resolution.
+// Items with cells of higher resolution are counted in their parent cell, items with cells
+// of lower resolution are counted in their own cells, so the result can contain cells of lower resolution.
+// Cells of resolution higher than the index resolution are counted in cells of the index resolution.
+// Like ItemsInCell, item with several cells is also counted in cells
+// that combine digits of its cells.
+func (i *Index) CellCounts(res int) map[h3.Cell]uint64 {
+	out := map[h3.Cell]uint64{}
+	if res < 0 || res > 15 {
+		return out
+	}
+	for bn, bm := range i.baseCellMap {
+		if bm == nil || bm.IsEmpty() {
+			continue
 		}
-		base.And(resMap)
+		pentagon := h3f.IsBaseCellPentagon(bn)
+		var digits [15]int
+		i.cellCounts(bn, pentagon, bm, 0, res, &digits, out)
 	}
+	return out
+}
 
-	return !base.IsEmpty()
+// cellCounts walks cells tree of the base cell and adds count of items for cells in given resolution to out.
+// The base contains items with cells inside current cell.
+func (i *Index) cellCounts(bn int, pentagon bool, base *roaring64.Bitmap, r, res int, digits *[15]int, out map[h3.Cell]uint64) {
+	if r == res || r >= int(i.res) {
+		out[h3f.BuildH3CellRes(r, bn, digits)] += base.GetCardinality()
+		return
+	}
+	// items with cell of current resolution
+	if rm := i.resMaps[r][7]; rm != nil {
+		if n := roaring64.And(base, rm).GetCardinality(); n != 0 {
+			out[h3f.BuildH3CellRes(r, bn, digits)] += n
+		}
+	}
+	for cn := 0; cn < 7; cn++ {
+		if cn == 1 && pentagonDeletedDigit(pentagon, digits, r) {
+			continue
+		}
+		rm := i.resMaps[r][cn]
+		if rm == nil {
+			continue
+		}
+		children := roaring64.And(base, rm)
+		if children.IsEmpty() {
+			continue
+		}
+		digits[r] = cn
+		i.cellCounts(bn, pentagon, children, r+1, res, digits, out)
+	}
+	digits[r] = 0
 }
 
 // ItemCells returns h3 cells for item.
+// Cells of resolution higher than the index resolution are returned as their parent cells.
+// Digits of cells are stored by resolutions, so for item with several cells
+// the result can contain combinations of digits of the item cells.
 func (i *Index) ItemCells(idx uint64) []h3.Cell {
-	out := make([]h3.Cell, 0, 2)
-	filter := roaring64.New()
-	filter.Add(idx)
+	var out []h3.Cell
 	for bn, bm := range i.baseCellMap {
-		if bm == nil {
+		if bm == nil || !bm.Contains(idx) {
 			continue
 		}
-		base := roaring64.And(bm, filter)
-		if base.IsEmpty() {
+		pentagon := h3f.IsBaseCellPentagon(bn)
+		var digits [15]int
+		out = i.itemCells(idx, bn, pentagon, 0, &digits, out)
+	}
+	return out
+}
+
+// itemCells walks cells tree of the base cell and appends cells of the item to out.
+func (i *Index) itemCells(idx uint64, bn int, pentagon bool, r int, digits *[15]int, out []h3.Cell) []h3.Cell {
+	if r >= int(i.res) {
+		return append(out, h3f.BuildH3CellRes(r, bn, digits))
+	}
+	if rm := i.resMaps[r][7]; rm != nil && rm.Contains(idx) {
+		out = append(out, h3f.BuildH3CellRes(r, bn, digits))
+	}
+	for cn := 0; cn < 7; cn++ {
+		if cn == 1 && pentagonDeletedDigit(pentagon, digits, r) {
 			continue
 		}
-		var levels [][]int
-		for _, rm := range i.resMaps {
-			level := make([][]int, 0, len(levels))
-			for cn, cm := range rm {
-				if !cm.Contains(idx) {
-					continue
-				}
-				if cn == 7 {
-					for _, item := range levels {
-						out = append(out, h3f.BuildH3Cell(bn, item...))
-					}
-					continue
-				}
-				for i := range levels {
-					level = append(level, append(levels[i], cn))
-				}
-			}
-			levels = level
+		if rm := i.resMaps[r][cn]; rm == nil || !rm.Contains(idx) {
+			continue
 		}
+		digits[r] = cn
+		out = i.itemCells(idx, bn, pentagon, r+1, digits, out)
 	}
+	digits[r] = 0
 	return out
 }
 
+// CoverageGeometry returns geometry of cells stored for item, as returned by ItemCells.
+func (i *Index) CoverageGeometry(idx uint64) orb.MultiPolygon {
+	return h3f.CellsToMultiPolygon(i.ItemCells(idx))
+}
+
 // Intersects checks index has intersects point with cells.
 func (i *Index) Intersects(cells []h3.Cell) bool {
-	allItems := roaring64.New()
 	for _, cell := range cells {
-		bn := h3f.BaseCellNum(cell)
-		if i.baseCellMap[bn] == nil {
-			continue
-		}
-		base := i.baseCellMap[bn].Clone()
-		cellRes := h3f.H3Res(cell)
-		for r := i.minRes - 1; r < i.res && r < int8(cellRes); r++ {
-			crn := h3f.CellIndexInRes(cell, int(r+1))
-			resMap := i.resMaps[r][crn]
-			if resMap == nil {
-				resMap = roaring64.New()
-			}
-			if i.resMaps[r][7] != nil {
-				resMap.Or(i.resMaps[r][7])
-			}
-			base.And(resMap)
+		if i.HasCell(cell) {
+			return true
 		}
-		allItems.Or(base)
 	}
-
-	return !allItems.IsEmpty()
+	return false
 }
 
 // Intersection returns indexes that have intersects point with cells.
 func (i *Index) Intersection(cells []h3.Cell) *roaring64.Bitmap {
 	allItems := roaring64.New()
 	for _, cell := range cells {
-		bn := h3f.BaseCellNum(cell)
-		if i.baseCellMap[bn] == nil {
-			continue
-		}
-		base := i.baseCellMap[bn].Clone()
-		cellRes := h3f.H3Res(cell)
-		for r := i.minRes - 1; r < i.res && r < int8(cellRes); r++ {
-			crn := h3f.CellIndexInRes(cell, int(r+1))
-			resMap := i.resMaps[r][crn]
-			if resMap == nil {
-				resMap = roaring64.New()
-			}
-			if i.resMaps[r][7] != nil {
-				resMap.Or(i.resMaps[r][7])
-			}
-			base.And(resMap)
-		}
-		allItems.Or(base)
+		allItems.Or(i.ItemsInCell(cell))
 	}
-
 	return allItems
 }
 
@@ -321,12 +512,14 @@ func (i *Index) parentCellForBaseCell(baseCellNum int) h3.Cell {
 // Clone makes a copy of BitmapIndex.
 func (i *Index) Clone() *Index {
 	ni := &Index{
-		res:          i.res,
-		minRes:       i.minRes,
-		baseCellsLen: i.baseCellsLen,
-		maxItemIndex: i.maxItemIndex,
-		baseCellMap:  [122]*roaring64.Bitmap{},
-		resMaps:      [15][8]*roaring64.Bitmap{},
+		res:           i.res,
+		minRes:        i.minRes,
+		baseCellsLen:  i.baseCellsLen,
+		maxItemIndex:  i.maxItemIndex,
+		setItemIndex:  i.setItemIndex,
+		baseCellsMask: i.baseCellsMask.Clone(),
+		baseCellMap:   [122]*roaring64.Bitmap{},
+		resMaps:       [15][8]*roaring64.Bitmap{},
 	}
 
 	for bn, bm := range i.baseCellMap {
@@ -8,7 +8,9 @@ import (
 	"github.com/RoaringBitmap/roaring/roaring64"
 	"github.com/VGSML/geobin/h3f"
 	"github.com/VGSML/geobin/internal/fixture"
+	"github.com/paulmach/orb/geo"
 	"github.com/uber/h3-go/v4"
+	"golang.org/x/exp/slices"
 )
 
 func Test_baseCellNum(t *testing.T) {
@@ -94,6 +96,51 @@ func TestBitmapIndex_Insert(t *testing.T) {
 	}
 }
 
+func TestBitmapIndex_InsertMany(t *testing.T) {
+	var items []ItemCells
+	for idx, c := range h3.Cell(0x85267123fffffff).GridDisk(3) {
+		items = append(items, ItemCells{
+			Idx:   uint64(idx),
+			Cells: append(c.Children(7)[:3], c.Parent(idx%5), c),
+		})
+	}
+	items = append(items, ItemCells{Idx: 100, Cells: []h3.Cell{0x8013fffffffffff}})
+
+	want := New(12)
+	for _, item := range items {
+		for _, c := range item.Cells {
+			want.Insert(item.Idx, c)
+		}
+	}
+	got := New(12)
+	got.InsertMany(items[:10])
+	got.InsertMany(items[10:])
+
+	if got.BaseCellsCount() != want.BaseCellsCount() || got.MaxItemIndex() != want.MaxItemIndex() || got.minRes != want.minRes {
+		t.Errorf("counters are different, got %d, %d, %d, want %d, %d, %d",
+			got.BaseCellsCount(), got.MaxItemIndex(), got.minRes,
+			want.BaseCellsCount(), want.MaxItemIndex(), want.minRes,
+		)
+	}
+	if !got.baseCellsMask.Equals(want.baseCellsMask) {
+		t.Errorf("base cells mask is different, got %v, want %v", got.baseCellsMask.ToArray(), want.baseCellsMask.ToArray())
+	}
+	for bn := range want.baseCellMap {
+		if (got.baseCellMap[bn] == nil) != (want.baseCellMap[bn] == nil) ||
+			want.baseCellMap[bn] != nil && !got.baseCellMap[bn].Equals(want.baseCellMap[bn]) {
+			t.Errorf("base cell %d bitmap is different", bn)
+		}
+	}
+	for res := range want.resMaps {
+		for cn := range want.resMaps[res] {
+			if (got.resMaps[res][cn] == nil) != (want.resMaps[res][cn] == nil) ||
+				want.resMaps[res][cn] != nil && !got.resMaps[res][cn].Equals(want.resMaps[res][cn]) {
+				t.Errorf("res %d cell num %d bitmap is different", res, cn)
+			}
+		}
+	}
+}
+
 func TestBitmapIndex_HasCell(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -224,6 +271,178 @@ func TestBitmapIndex_HasCell(t *testing.T) {
 	}
 }
 
+func TestBitmapIndex_ItemsInCell(t *testing.T) {
+	parent := h3.Cell(0x812bbffffffffff)
+	child1 := parent.Children(3)[0]
+	child2 := parent.Children(3)[len(parent.Children(3))-1]
+	b := New(15)
+	b.Insert(0, parent)
+	b.Insert(1, child1)
+	b.Insert(2, child2)
+	b.Insert(3, 0x8426713ffffffff)
+
+	tests := []struct {
+		name string
+		cell h3.Cell
+		want []uint64
+	}{
+		{name: "parent", cell: parent, want: []uint64{0, 1, 2}},
+		{name: "child", cell: child1, want: []uint64{0, 1}},
+		{name: "children of child", cell: child1.Children(5)[3], want: []uint64{0, 1}},
+		{name: "parent of child", cell: child2.Parent(2), want: []uint64{0, 2}},
+		{name: "other base cell", cell: 0x8013fffffffffff, want: []uint64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := b.ItemsInCell(tt.cell).ToArray()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// parent item is counted in its own cell of lower resolution
+	counts := b.CellCounts(2)
+	want := map[h3.Cell]uint64{
+		parent:                               1,
+		child1.Parent(2):                     1,
+		child2.Parent(2):                     1,
+		h3.Cell(0x8426713ffffffff).Parent(2): 1,
+	}
+	if !reflect.DeepEqual(counts, want) {
+		t.Errorf("CellCounts() got %v, want %v", counts, want)
+	}
+}
+
+func TestBitmapIndex_MultiCellItems(t *testing.T) {
+	parent := h3.Cell(0x812bbffffffffff)
+	other := h3.Cell(0x8426713ffffffff)
+	fine := parent.Children(4)
+	b := New(15)
+	b.Insert(0, parent)
+	b.Insert(0, other)
+	b.Insert(1, fine[0])
+	b.Insert(1, fine[len(fine)-1])
+	b.Insert(2, other)
+
+	// digits of cells are stored by resolutions, so item cells are combinations of their digits
+	got := b.ItemCells(0)
+	if !slices.Contains(got, parent) || !slices.Contains(got, other) {
+		t.Errorf("ItemCells() got %v, want to contain %v and %v", got, parent, other)
+	}
+	if got := b.ItemsInCell(fine[6]).ToArray(); !reflect.DeepEqual(got, []uint64{0, 1}) {
+		t.Errorf("ItemsInCell() for combined cell got %v, want [0 1]", got)
+	}
+	if got := b.ItemsInCell(other).ToArray(); !reflect.DeepEqual(got, []uint64{0, 2}) {
+		t.Errorf("ItemsInCell() for other cell got %v, want [0 2]", got)
+	}
+	counts := b.CellCounts(4)
+	if counts[parent] != 1 || counts[other] != 2 || counts[fine[0]] != 1 || counts[fine[6]] != 1 {
+		t.Errorf("CellCounts() got %v", counts)
+	}
+
+	clone := b.Clone()
+	clone.RemoveMany(roaring64.BitmapOf(0))
+	if got := clone.ItemsInCell(other).ToArray(); !reflect.DeepEqual(got, []uint64{2}) {
+		t.Errorf("ItemsInCell() after RemoveMany() got %v, want [2]", got)
+	}
+	if got := b.ItemsInCell(other).ToArray(); !reflect.DeepEqual(got, []uint64{0, 2}) {
+		t.Errorf("ItemsInCell() of cloned index got %v, want [0 2]", got)
+	}
+}
+
+func TestBitmapIndex_RemoveMany(t *testing.T) {
+	parent := h3.Cell(0x812bbffffffffff)
+	b := New(15)
+	b.Insert(0, parent)
+	b.Insert(1, parent.Children(3)[0])
+	b.Insert(2, parent.Children(3)[1])
+	b.Insert(3, 0x8426713ffffffff)
+
+	if b.RemoveMany(roaring64.BitmapOf(10, 11)) {
+		t.Error("RemoveMany() returns true for not indexed items")
+	}
+	if !b.RemoveMany(roaring64.BitmapOf(1, 3)) {
+		t.Error("RemoveMany() returns false for indexed items")
+	}
+	if b.BaseCellsCount() != 1 {
+		t.Errorf("BaseCellsCount() got %d, want %d", b.BaseCellsCount(), 1)
+	}
+	if got := b.ItemsInCell(parent).ToArray(); !reflect.DeepEqual(got, []uint64{0, 2}) {
+		t.Errorf("ItemsInCell() got %v, want %v", got, []uint64{0, 2})
+	}
+
+	if b.minRes != 1 {
+		t.Errorf("minRes got %d, want %d", b.minRes, 1)
+	}
+	b.RemoveMany(roaring64.BitmapOf(0))
+	b.Compact()
+	if b.minRes != 3 || b.MaxItemIndex() != 2 {
+		t.Errorf("Compact() got minRes %d, max item index %d, want 3, 2", b.minRes, b.MaxItemIndex())
+	}
+	b.Insert(0, parent)
+	b.SetMaxItemIndex(5)
+	b.RemoveMany(roaring64.BitmapOf(2))
+	b.Compact()
+	if b.MaxItemIndex() != 5 {
+		t.Errorf("MaxItemIndex() got %d, want %d", b.MaxItemIndex(), 5)
+	}
+	for res, rm := range b.resMaps {
+		for cn, r := range rm {
+			if r != nil && r.IsEmpty() {
+				t.Errorf("Compact() doesn't release empty bitmap res %d, cell num %d", res, cn)
+			}
+		}
+	}
+	if !b.HasCell(parent) {
+		t.Error("HasCell() returns false for indexed cell after Compact()")
+	}
+}
+
+func TestBitmapIndex_Stats(t *testing.T) {
+	parent := h3.Cell(0x812bbffffffffff)
+	b := New(15)
+	b.Insert(0, parent)
+	b.Insert(1, parent.Children(3)[0])
+	b.Insert(1, parent.Children(3)[1])
+	b.Insert(2, 0x8426713ffffffff)
+
+	s := b.Stats()
+	if s.Items != 3 || s.BaseCells != 2 || s.MaxItemIndex != 2 {
+		t.Errorf("Stats() got items %d, base cells %d, max item index %d, want 3, 2, 2", s.Items, s.BaseCells, s.MaxItemIndex)
+	}
+	if s.ResCardinality[1][7] != 1 || s.ResCardinality[3][7] != 1 || s.ResCardinality[4][7] != 1 {
+		t.Errorf("Stats() got wrong full entries %v", s.ResCardinality)
+	}
+	if s.ItemResHistogram[1] != 1 || s.ItemResHistogram[3] != 1 || s.ItemResHistogram[4] != 1 {
+		t.Errorf("Stats() got wrong item resolutions %v", s.ItemResHistogram)
+	}
+	if s.InMemoryBytes == 0 || s.SerializedBytes == 0 {
+		t.Errorf("Stats() got zero bytes, in memory %d, serialized %d", s.InMemoryBytes, s.SerializedBytes)
+	}
+	bitmaps, size := 1, b.baseCellsMask.GetSerializedSizeInBytes()
+	for _, bm := range b.baseCellMap {
+		if bm != nil {
+			bitmaps++
+			size += bm.GetSerializedSizeInBytes()
+		}
+	}
+	for res := range b.resMaps {
+		for _, bm := range b.resMaps[res] {
+			if bm != nil {
+				bitmaps++
+				size += bm.GetSerializedSizeInBytes()
+			}
+		}
+	}
+	if s.Bitmaps != bitmaps || s.SerializedBytes != size {
+		t.Errorf("Stats() got %d bitmaps of %d bytes, want %d bitmaps of %d bytes", s.Bitmaps, s.SerializedBytes, bitmaps, size)
+	}
+	if m := s.Metrics(); m[`h3b_res_cardinality{res="1",cell_num="7"}`] != 1 {
+		t.Errorf("Metrics() got wrong full entries %v", m)
+	}
+}
+
 func TestBitmapIndex_Intersection(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -400,6 +619,12 @@ func TestBitmapIndex_Intersection(t *testing.T) {
 			},
 			want: []uint64{0, 1, 2, 3},
 		},
+		{
+			name:       "grandchild of indexed cell",
+			indexCells: []h3.Cell{0x812bbffffffffff, 0x8426713ffffffff},
+			inputCells: []h3.Cell{h3.Cell(0x812bbffffffffff).Children(9)[100]},
+			want:       []uint64{0},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -739,3 +964,52 @@ func testPrintBitmap(b *Index) {
 		fmt.Println("\t", bn, ":", base.ToArray())
 	}
 }
+
+func TestBitmapIndex_CoverageGeometry(t *testing.T) {
+	center := h3.NewLatLng(52.52, 13.405).Cell(7)
+	index := New(9)
+	disk := center.GridDisk(1)
+	for _, c := range disk {
+		index.Insert(1, c)
+	}
+	index.Insert(2, center.Parent(4))
+	index.Insert(3, h3.NewLatLng(-33.8688, 151.2093).Cell(9))
+
+	tests := []struct {
+		idx   uint64
+		cells []h3.Cell
+	}{
+		{idx: 1, cells: disk},
+		{idx: 2, cells: []h3.Cell{center.Parent(4)}},
+		{idx: 3, cells: []h3.Cell{h3.NewLatLng(-33.8688, 151.2093).Cell(9)}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("item %d", tt.idx), func(t *testing.T) {
+			cells := index.ItemCells(tt.idx)
+			set := map[h3.Cell]struct{}{}
+			for _, c := range cells {
+				set[c] = struct{}{}
+			}
+			for _, c := range tt.cells {
+				if _, ok := set[c]; !ok {
+					t.Errorf("expected cell %v in item cells", c)
+				}
+			}
+			// the index can store combinations of digits of item cells, so coverage can be greater
+			got := index.CoverageGeometry(tt.idx)
+			want := h3f.CellsToMultiPolygon(tt.cells)
+			if len(got) == 0 {
+				t.Fatalf("expected coverage geometry")
+			}
+			if len(cells) == len(tt.cells) && got.Bound() != want.Bound() {
+				t.Errorf("expected bound %v, got %v", want.Bound(), got.Bound())
+			}
+			if a, b := geo.Area(got), geo.Area(want); a < b*0.999 {
+				t.Errorf("expected coverage area not less than %v, got %v", b, a)
+			}
+		})
+	}
+	if index.CoverageGeometry(4) != nil {
+		t.Errorf("expected empty geometry for not indexed item")
+	}
+}
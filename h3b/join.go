@@ -1,6 +1,8 @@
 package h3b
 
 import (
+	"context"
+
 	"github.com/RoaringBitmap/roaring/roaring64"
 	"github.com/VGSML/geobin/bjoin"
 )
@@ -250,3 +252,84 @@ func JoinIntersects(a, b *Index, left bool) *bjoin.Index {
 	}
 	return join
 }
+
+// SelfJoinIntersects perform intersection join of the index with itself.
+// Pairs (a, a) are excluded, if unique is true each unordered pair is returned once as (a, b) with a < b.
+// Unlike JoinIntersects it walks cells tree by each child cell number, so items in sibling cells are not joined.
+// Returns context error if the context is done before the join is completed.
+func SelfJoinIntersects(ctx context.Context, a *Index, unique bool) (*bjoin.Index, error) {
+	pairs := map[uint64]*roaring64.Bitmap{}
+	it := a.baseCellsMask.Iterator()
+	for it.HasNext() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		bm := a.baseCellMap[it.Next()]
+		if bm == nil || bm.GetCardinality() < 2 {
+			continue
+		}
+		a.selfJoinCell(bm, 0, pairs)
+	}
+
+	join := bjoin.New(a.MaxItemIndex() + 1)
+	items := roaring64.New()
+	for idx := range pairs {
+		items.Add(idx)
+	}
+	itA := items.Iterator()
+	for itA.HasNext() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		idx := itA.Next()
+		b := pairs[idx]
+		if unique {
+			b.RemoveRange(0, idx+1)
+		} else {
+			b.Remove(idx)
+		}
+		if b.IsEmpty() {
+			continue
+		}
+		join.AddPairs(roaring64.BitmapOf(idx), b)
+	}
+	return join, nil
+}
+
+// selfJoinCell adds to pairs all items intersects inside cell, represented by base items on given resolution.
+func (i *Index) selfJoinCell(base *roaring64.Bitmap, res int, pairs map[uint64]*roaring64.Bitmap) {
+	if res >= int(i.res) {
+		addPairs(pairs, base, base)
+		return
+	}
+	if rm := i.resMaps[res][7]; rm != nil {
+		full := roaring64.And(base, rm)
+		if !full.IsEmpty() {
+			addPairs(pairs, full, base)
+			addPairs(pairs, base, full)
+		}
+	}
+	for cn := 0; cn < 7; cn++ {
+		rm := i.resMaps[res][cn]
+		if rm == nil {
+			continue
+		}
+		children := roaring64.And(base, rm)
+		if children.GetCardinality() < 2 {
+			continue
+		}
+		i.selfJoinCell(children, res+1, pairs)
+	}
+}
+
+// addPairs adds b items to pairs of each item a.
+func addPairs(pairs map[uint64]*roaring64.Bitmap, a, b *roaring64.Bitmap) {
+	it := a.Iterator()
+	for it.HasNext() {
+		idx := it.Next()
+		if pairs[idx] == nil {
+			pairs[idx] = roaring64.New()
+		}
+		pairs[idx].Or(b)
+	}
+}
@@ -2,6 +2,7 @@ package h3b
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/VGSML/geobin/bjoin"
@@ -526,3 +527,41 @@ func testCheckJoinResult(got *bjoin.Index, want []bjoin.Pair, errorOut func(form
 	}
 	return res
 }
+
+func TestSelfJoinIntersects(t *testing.T) {
+	parent := h3.Cell(0x812bbffffffffff)
+	cells := []h3.Cell{
+		parent,
+		parent.Children(2)[0],
+		parent.Children(2)[1],
+		0x8426713ffffffff,
+	}
+	a := New(15)
+	for i, c := range cells {
+		a.Insert(uint64(i), c)
+	}
+
+	got, err := SelfJoinIntersects(context.Background(), a, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckJoinResult(got, []bjoin.Pair{
+		{A: 0, B: []uint64{1, 2}},
+		{A: 1, B: []uint64{0}},
+		{A: 2, B: []uint64{0}},
+	}, t.Errorf)
+
+	got, err = SelfJoinIntersects(context.Background(), a, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCheckJoinResult(got, []bjoin.Pair{
+		{A: 0, B: []uint64{1, 2}},
+	}, t.Errorf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := SelfJoinIntersects(ctx, a, false); !errors.Is(err, context.Canceled) {
+		t.Errorf("SelfJoinIntersects() with canceled context error = %v, want %v", err, context.Canceled)
+	}
+}
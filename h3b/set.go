@@ -0,0 +1,177 @@
+package h3b
+
+import (
+	"github.com/RoaringBitmap/roaring/roaring64"
+	"github.com/VGSML/geobin/h3f"
+	"github.com/uber/h3-go/v4"
+)
+
+// perform set operations with coverage (union of all items cells) of indexes.
+
+type setOp int
+
+const (
+	opUnion setOp = iota
+	opIntersect
+	opDifference
+)
+
+// UnionCells returns compacted cells covered by a or b.
+func UnionCells(a, b *Index) []h3.Cell {
+	return setCells(a, b, opUnion)
+}
+
+// IntersectCells returns compacted cells covered by both a and b.
+func IntersectCells(a, b *Index) []h3.Cell {
+	return setCells(a, b, opIntersect)
+}
+
+// DifferenceCells returns compacted cells covered by a and not covered by b.
+func DifferenceCells(a, b *Index) []h3.Cell {
+	return setCells(a, b, opDifference)
+}
+
+// Union returns new index with single item 0 covering cells covered by a or b.
+func Union(a, b *Index) *Index {
+	return newCoverageIndex(UnionCells(a, b), max(a.res, b.res))
+}
+
+// Intersect returns new index with single item 0 covering cells covered by both a and b.
+func Intersect(a, b *Index) *Index {
+	return newCoverageIndex(IntersectCells(a, b), max(a.res, b.res))
+}
+
+// Difference returns new index with single item 0 covering cells covered by a and not covered by b.
+func Difference(a, b *Index) *Index {
+	return newCoverageIndex(DifferenceCells(a, b), max(a.res, b.res))
+}
+
+func newCoverageIndex(cells []h3.Cell, res int8) *Index {
+	i := New(int(res))
+	i.InsertMany([]ItemCells{{Idx: 0, Cells: cells}})
+	return i
+}
+
+// coverNode represents items of the index inside the cell.
+// Digits of cells are stored by resolutions, so cells of item with several cells
+// are walked with combinations of their digits, see ItemsInCell.
+type coverNode struct {
+	index *Index
+	base  *roaring64.Bitmap
+	full  bool // cell is completely covered
+}
+
+func (n coverNode) empty() bool {
+	return !n.full && (n.base == nil || n.base.IsEmpty())
+}
+
+// at returns node with resolved cell covering on resolution r.
+func (n coverNode) at(r int) coverNode {
+	if n.full || n.empty() {
+		return n
+	}
+	if r >= int(n.index.res) {
+		n.full = true
+		return n
+	}
+	if rm := n.index.resMaps[r][7]; rm != nil && n.base.Intersects(rm) {
+		n.full = true
+	}
+	return n
+}
+
+// child returns node for the child cell with number cn on resolution r+1.
+func (n coverNode) child(r, cn int) coverNode {
+	if n.full || n.empty() {
+		return n
+	}
+	rm := n.index.resMaps[r][cn]
+	if rm == nil {
+		return coverNode{index: n.index}
+	}
+	return coverNode{
+		index: n.index,
+		base:  roaring64.And(n.base, rm),
+	}
+}
+
+func setCells(a, b *Index, op setOp) []h3.Cell {
+	var out []h3.Cell
+	for bn := 0; bn < 122; bn++ {
+		na := coverNode{index: a, base: a.baseCellMap[bn]}
+		nb := coverNode{index: b, base: b.baseCellMap[bn]}
+		if na.empty() && nb.empty() {
+			continue
+		}
+		var digits [15]int
+		pentagon := h3f.IsBaseCellPentagon(bn)
+		out, _ = appendSetCells(out, op, na, nb, bn, pentagon, 0, &digits)
+	}
+	return out
+}
+
+// appendSetCells appends result cells of the set operation for the cell on resolution r,
+// returns true if the cell is completely covered by the result.
+func appendSetCells(out []h3.Cell, op setOp, na, nb coverNode, bn int, pentagon bool, r int, digits *[15]int) ([]h3.Cell, bool) {
+	na, nb = na.at(r), nb.at(r)
+	cell := func() h3.Cell {
+		return h3f.BuildH3CellRes(r, bn, digits)
+	}
+	switch op {
+	case opUnion:
+		if na.full || nb.full {
+			return append(out, cell()), true
+		}
+		if na.empty() && nb.empty() {
+			return out, false
+		}
+	case opIntersect:
+		if na.empty() || nb.empty() {
+			return out, false
+		}
+		if na.full && nb.full {
+			return append(out, cell()), true
+		}
+	case opDifference:
+		if na.empty() || nb.full {
+			return out, false
+		}
+		if na.full && nb.empty() {
+			return append(out, cell()), true
+		}
+	}
+	if r >= 15 {
+		return out, false
+	}
+
+	start := len(out)
+	allFull := true
+	for cn := 0; cn < 7; cn++ {
+		if cn == 1 && pentagonDeletedDigit(pentagon, digits, r) {
+			continue
+		}
+		digits[r] = cn
+		var full bool
+		out, full = appendSetCells(out, op, na.child(r, cn), nb.child(r, cn), bn, pentagon, r+1, digits)
+		allFull = allFull && full
+	}
+	digits[r] = 7
+	if allFull {
+		return append(out[:start], cell()), true
+	}
+	return out, false
+}
+
+// pentagonDeletedDigit checks the child with number 1 on resolution r+1 doesn't exist:
+// pentagon cells have no children with first non zero digit 1.
+func pentagonDeletedDigit(pentagon bool, digits *[15]int, r int) bool {
+	if !pentagon {
+		return false
+	}
+	for n := 0; n < r; n++ {
+		if digits[n] != 0 {
+			return false
+		}
+	}
+	return true
+}
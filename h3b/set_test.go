@@ -0,0 +1,149 @@
+package h3b
+
+import (
+	"testing"
+
+	"github.com/uber/h3-go/v4"
+	"golang.org/x/exp/slices"
+)
+
+func TestSetCells(t *testing.T) {
+	parent := h3.Cell(0x812bbffffffffff)
+	child := parent.Children(2)[2]
+	var siblings []h3.Cell
+	for _, c := range parent.Children(2) {
+		if c != child {
+			siblings = append(siblings, c)
+		}
+	}
+	other := h3.Cell(0x8426713ffffffff)
+
+	tests := []struct {
+		name string
+		a, b []h3.Cell
+		op   func(a, b *Index) []h3.Cell
+		want []h3.Cell
+	}{
+		{
+			name: "union parent and child",
+			a:    []h3.Cell{parent},
+			b:    []h3.Cell{child, other},
+			op:   UnionCells,
+			want: []h3.Cell{parent, other},
+		},
+		{
+			name: "union all children",
+			a:    siblings,
+			b:    []h3.Cell{child},
+			op:   UnionCells,
+			want: []h3.Cell{parent},
+		},
+		{
+			name: "intersect parent and child",
+			a:    []h3.Cell{parent, other},
+			b:    []h3.Cell{child},
+			op:   IntersectCells,
+			want: []h3.Cell{child},
+		},
+		{
+			name: "intersect different base cells",
+			a:    []h3.Cell{parent},
+			b:    []h3.Cell{other},
+			op:   IntersectCells,
+			want: nil,
+		},
+		{
+			name: "difference parent and child",
+			a:    []h3.Cell{parent},
+			b:    []h3.Cell{child},
+			op:   DifferenceCells,
+			want: siblings,
+		},
+		{
+			name: "difference child and parent",
+			a:    []h3.Cell{child},
+			b:    []h3.Cell{parent},
+			op:   DifferenceCells,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := New(15)
+			for i, c := range tt.a {
+				a.Insert(uint64(i), c)
+			}
+			b := New(15)
+			for i, c := range tt.b {
+				b.Insert(uint64(i), c)
+			}
+			got := tt.op(a, b)
+			slices.Sort(got)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if slices.Compare(got, want) != 0 {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetCellsMixedResolutions(t *testing.T) {
+	parent := h3.Cell(0x812bbffffffffff)
+	// cell of other res 1 cell in the same base cell
+	child := h3.Cell(0x832a00fffffffff)
+	a := New(15)
+	a.Insert(0, parent)
+	a.Insert(1, child)
+	fine := parent.Children(3)
+	b := New(15)
+	for i, c := range []h3.Cell{fine[0], fine[len(fine)-1], child.Parent(2)} {
+		b.Insert(uint64(i), c)
+	}
+
+	tests := []struct {
+		name string
+		op   func(a, b *Index) []h3.Cell
+		a, b *Index
+		want []h3.Cell
+	}{
+		{name: "union with empty", op: UnionCells, a: a, b: New(15), want: []h3.Cell{parent, child}},
+		{name: "union", op: UnionCells, a: a, b: b, want: []h3.Cell{parent, child.Parent(2)}},
+		{name: "intersect", op: IntersectCells, a: a, b: b, want: []h3.Cell{fine[0], fine[len(fine)-1], child}},
+		{name: "difference", op: DifferenceCells, a: b, b: a, want: slices.DeleteFunc(child.Parent(2).Children(3), func(c h3.Cell) bool { return c == child })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.op(tt.a, tt.b)
+			slices.Sort(got)
+			want := slices.Clone(tt.want)
+			slices.Sort(want)
+			if slices.Compare(got, want) != 0 {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSetIndex(t *testing.T) {
+	parent := h3.Cell(0x812bbffffffffff)
+	child := parent.Children(2)[2]
+	a := New(15)
+	a.Insert(0, parent)
+	b := New(15)
+	b.Insert(0, child)
+
+	got := Difference(a, b)
+	if got.HasCell(child) {
+		t.Errorf("Difference() has cell %s", child)
+	}
+	if !got.HasCell(parent.Children(2)[0]) {
+		t.Errorf("Difference() doesn't have cell %s", parent.Children(2)[0])
+	}
+	if !Intersect(a, b).HasCell(child) {
+		t.Errorf("Intersect() doesn't have cell %s", child)
+	}
+	if !Union(a, b).HasCell(parent) {
+		t.Errorf("Union() doesn't have cell %s", parent)
+	}
+}
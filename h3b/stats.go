@@ -0,0 +1,104 @@
+package h3b
+
+import (
+	"fmt"
+
+	"github.com/RoaringBitmap/roaring/roaring64"
+)
+
+// Stats represents statistics of the index bitmaps.
+type Stats struct {
+	Res          int    `json:"res"`
+	BaseCells    int    `json:"base_cells"`
+	Items        uint64 `json:"items"`
+	MaxItemIndex uint64 `json:"max_item_index"`
+
+	// BaseCellCardinality count of items for each indexed base cell.
+	BaseCellCardinality map[int]uint64 `json:"base_cell_cardinality"`
+	// ResCardinality count of items for each resolution bitmap, the cell number 7 is count of "full" entries.
+	ResCardinality [15][8]uint64 `json:"res_cardinality"`
+	// ItemResHistogram count of items that have cells of each resolution.
+	ItemResHistogram [16]uint64 `json:"item_res_histogram"`
+
+	// Bitmaps count of all bitmaps the index consists of, bytes are their total sizes.
+	Bitmaps         int    `json:"bitmaps"`
+	InMemoryBytes   uint64 `json:"in_memory_bytes"`
+	SerializedBytes uint64 `json:"serialized_bytes"`
+}
+
+// Stats returns statistics of the index.
+func (i *Index) Stats() Stats {
+	s := Stats{
+		Res:                 int(i.res),
+		BaseCells:           int(i.baseCellsLen),
+		MaxItemIndex:        i.maxItemIndex,
+		BaseCellCardinality: map[int]uint64{},
+	}
+	s.addBitmap(i.baseCellsMask)
+	items := roaring64.New()
+	for bn, bm := range i.baseCellMap {
+		if bm == nil {
+			continue
+		}
+		s.addBitmap(bm)
+		s.BaseCellCardinality[bn] = bm.GetCardinality()
+		items.Or(bm)
+	}
+	s.Items = items.GetCardinality()
+
+	maxRes := roaring64.New()
+	for res := range i.resMaps {
+		for cn, bm := range i.resMaps[res] {
+			if bm == nil {
+				continue
+			}
+			s.addBitmap(bm)
+			s.ResCardinality[res][cn] = bm.GetCardinality()
+			if res == int(i.res)-1 && cn != 7 {
+				maxRes.Or(bm)
+			}
+		}
+		s.ItemResHistogram[res] = s.ResCardinality[res][7]
+	}
+	if i.res > 0 {
+		s.ItemResHistogram[i.res] = maxRes.GetCardinality()
+	}
+	return s
+}
+
+func (s *Stats) addBitmap(bm *roaring64.Bitmap) {
+	s.Bitmaps++
+	s.InMemoryBytes += bm.GetSizeInBytes()
+	s.SerializedBytes += bm.GetSerializedSizeInBytes()
+}
+
+// Metrics returns statistics as flat metrics, names of metrics contain labels in Prometheus format.
+func (s Stats) Metrics() map[string]float64 {
+	m := map[string]float64{
+		"h3b_res":              float64(s.Res),
+		"h3b_base_cells":       float64(s.BaseCells),
+		"h3b_items":            float64(s.Items),
+		"h3b_max_item_index":   float64(s.MaxItemIndex),
+		"h3b_bitmaps":          float64(s.Bitmaps),
+		"h3b_in_memory_bytes":  float64(s.InMemoryBytes),
+		"h3b_serialized_bytes": float64(s.SerializedBytes),
+	}
+	for bn, c := range s.BaseCellCardinality {
+		m[fmt.Sprintf(`h3b_base_cell_cardinality{base_cell="%d"}`, bn)] = float64(c)
+	}
+	for res := range s.ResCardinality {
+		for cn, c := range s.ResCardinality[res] {
+			if c == 0 {
+				continue
+			}
+			m[fmt.Sprintf(`h3b_res_cardinality{res="%d",cell_num="%d"}`, res, cn)] = float64(c)
+		}
+	}
+	for res, c := range s.ItemResHistogram {
+		if c == 0 {
+			continue
+		}
+		m[fmt.Sprintf(`h3b_item_res_histogram{res="%d"}`, res)] = float64(c)
+	}
+	return m
+}
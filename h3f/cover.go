@@ -0,0 +1,212 @@
+package h3f
+
+import (
+	"github.com/VGSML/geobin/orbf"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"github.com/uber/h3-go/v4"
+)
+
+// Coverer builds small set of cells of mixed resolutions that covers geometry.
+// It starts from the covering of MinRes and refines cells, that are not inside geometry,
+// to cells of the next resolution, the coarsest cells first, while covering has no more than MaxCells cells
+// and cells are coarser than MaxRes. If geometry can't be covered by MaxCells cells of MinRes,
+// returns compacted covering of MinRes.
+type Coverer struct {
+	MaxCells int
+	MinRes   int
+	MaxRes   int
+}
+
+// DefaultCoverer is a coverer used if parameters are not set.
+var DefaultCoverer = Coverer{
+	MaxCells: 8,
+	MinRes:   0,
+	MaxRes:   15,
+}
+
+// Cover returns cells that fully covers geometry.
+func (c Coverer) Cover(geom orb.Geometry) []h3.Cell {
+	c = c.normalize()
+	cells := CoverCells(geom, c.MinRes)
+	if len(cells) > c.MaxCells || len(cells) == 0 {
+		return Compact(cells)
+	}
+
+	cover := make(map[h3.Cell]bool, c.MaxCells) // cell -> cell can be refined
+	for _, cell := range cells {
+		cover[cell] = c.refinable(geom, cell)
+	}
+	for {
+		// the coarsest cell has the largest error
+		var cell h3.Cell
+		for cc, ok := range cover {
+			if ok && (cell == 0 || H3Res(cc) < H3Res(cell) || H3Res(cc) == H3Res(cell) && cc < cell) {
+				cell = cc
+			}
+		}
+		if cell == 0 {
+			break
+		}
+		refined := refineCell(geom, cell)
+		if len(refined) == 0 {
+			cover[cell] = false
+			continue
+		}
+		var children []h3.Cell
+		for _, child := range refined {
+			if _, ok := cover[child]; !ok {
+				children = append(children, child)
+			}
+		}
+		if len(cover)-1+len(children) > c.MaxCells {
+			cover[cell] = false
+			continue
+		}
+		delete(cover, cell)
+		for _, child := range children {
+			cover[child] = c.refinable(geom, child)
+		}
+	}
+
+	out := make([]h3.Cell, 0, len(cover))
+	for cell := range cover {
+		if !hasParentIn(cover, cell, c.MinRes) {
+			out = append(out, cell)
+		}
+	}
+	if len(out) < 2 {
+		return out
+	}
+	return Compact(out)
+}
+
+// refinable checks cell is coarser than MaxRes and is not inside geometry.
+func (c Coverer) refinable(geom orb.Geometry, cell h3.Cell) bool {
+	return H3Res(cell) < c.MaxRes && !orbf.Contains(geom, cellPolygon(cell))
+}
+
+// refineCell returns cells of the next resolution that cover common points of the cell and geometry.
+// Children of h3 cell don't cover the cell exactly, so neighbors of the children overlapped the cell are checked too.
+func refineCell(geom orb.Geometry, cell h3.Cell) []h3.Cell {
+	poly := cellPolygon(cell)
+	var out []h3.Cell
+	for _, child := range GridDisk(CenterChild(cell, H3Res(cell)+1), 2) {
+		childPoly := cellPolygon(child)
+		if !orbf.Intersects(childPoly, geom) {
+			continue
+		}
+		// overlap area is computed only for neighbors of the children that touch the cell
+		if H3Parent(child, H3Res(cell)) != cell &&
+			(!orbf.Intersects(poly, childPoly) || geo.Area(orbf.Intersection(poly, childPoly)) < geo.Area(childPoly)*1e-6) {
+			continue
+		}
+		out = append(out, child)
+	}
+	return out
+}
+
+// hasParentIn checks set contains parent of the cell with resolution from minRes.
+func hasParentIn(set map[h3.Cell]bool, cell h3.Cell, minRes int) bool {
+	for res := H3Res(cell) - 1; res >= minRes; res-- {
+		if _, ok := set[H3Parent(cell, res)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// cellPolygon returns polygon of the cell boundary.
+func cellPolygon(cell h3.Cell) orb.Polygon {
+	b := cell.Boundary()
+	ring := make(orb.Ring, 0, len(b)+1)
+	for _, ll := range b {
+		ring = append(ring, orb.Point{ll.Lng, ll.Lat})
+	}
+	return orb.Polygon{append(ring, ring[0])}
+}
+
+func (c Coverer) normalize() Coverer {
+	if c.MaxCells <= 0 {
+		c.MaxCells = DefaultCoverer.MaxCells
+	}
+	c.MinRes = min(max(c.MinRes, 0), 15)
+	if c.MaxRes <= 0 || c.MaxRes > 15 {
+		c.MaxRes = 15
+	}
+	c.MaxRes = max(c.MaxRes, c.MinRes)
+	return c
+}
+
+// CoverCells returns all cells of given resolution that have common points with geometry:
+// cells of points, cells along lines and boundaries, and cells inside polygons.
+// Geometry that crosses antimeridian is split to parts before.
+func CoverCells(geom orb.Geometry, res int) []h3.Cell {
+	set := map[h3.Cell]struct{}{}
+	coverCells(orbf.SplitAntimeridian(geom), res, set)
+	out := make([]h3.Cell, 0, len(set))
+	for cell := range set {
+		out = append(out, cell)
+	}
+	return out
+}
+
+func coverCells(geom orb.Geometry, res int, set map[h3.Cell]struct{}) {
+	switch g := geom.(type) {
+	case orb.Bound:
+		coverCells(g.ToPolygon(), res, set)
+	case orb.Point:
+		set[h3.NewLatLng(g.Lat(), g.Lon()).Cell(res)] = struct{}{}
+	case orb.MultiPoint:
+		for _, p := range g {
+			coverCells(p, res, set)
+		}
+	case orb.LineString:
+		addLineCells(g, res, set)
+	case orb.MultiLineString:
+		for _, l := range g {
+			addLineCells(l, res, set)
+		}
+	case orb.Ring:
+		addLineCells(orb.LineString(g), res, set)
+	case orb.Polygon:
+		if len(g) == 0 {
+			return
+		}
+		for _, r := range g {
+			addLineCells(orb.LineString(r), res, set)
+		}
+		p := h3.GeoPolygon{GeoLoop: pointsToLatLngArray(g[0])}
+		for _, r := range g[1:] {
+			p.Holes = append(p.Holes, pointsToLatLngArray(r))
+		}
+		for _, cell := range polygonCells(p, res) {
+			set[cell] = struct{}{}
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			coverCells(p, res, set)
+		}
+	case orb.Collection:
+		for _, g := range g {
+			coverCells(g, res, set)
+		}
+	}
+}
+
+// addLineCells adds to set cells crossed by the line.
+func addLineCells(line orb.LineString, res int, set map[h3.Cell]struct{}) {
+	for _, cell := range LineCells(line, res) {
+		set[cell] = struct{}{}
+	}
+}
+
+// polygonCells returns cells with centers inside polygon, or nil if h3 fails to fill polygon.
+func polygonCells(p h3.GeoPolygon, res int) (cells []h3.Cell) {
+	defer func() {
+		if recover() != nil {
+			cells = nil
+		}
+	}()
+	return p.Cells(res)
+}
@@ -0,0 +1,106 @@
+package h3f
+
+import (
+	"testing"
+
+	"github.com/VGSML/geobin/internal/fixture"
+	"github.com/paulmach/orb"
+	"github.com/uber/h3-go/v4"
+)
+
+func TestCoverer_Cover(t *testing.T) {
+	square := orb.Polygon{{{13.0, 52.0}, {14.0, 52.0}, {14.0, 53.0}, {13.0, 53.0}, {13.0, 52.0}}}
+	tests := []struct {
+		name    string
+		geom    orb.Geometry
+		coverer Coverer
+		points  []orb.Point
+	}{
+		{
+			name:    "square",
+			geom:    square,
+			coverer: Coverer{MaxCells: 32, MinRes: 3, MaxRes: 9},
+			points:  []orb.Point{{13, 52}, {14, 53}, {13.5, 52}, {13.5, 52.5}, {13.99, 52.01}},
+		},
+		{
+			name:    "line",
+			geom:    orb.LineString{{13, 52}, {14, 52.5}, {14.5, 52}},
+			coverer: Coverer{MaxCells: 16, MinRes: 2, MaxRes: 9},
+			points:  []orb.Point{{13, 52}, {13.5, 52.25}, {14, 52.5}, {14.25, 52.25}, {14.5, 52}},
+		},
+		{
+			name:    "point",
+			geom:    orb.Point{13.4, 52.5},
+			coverer: Coverer{MaxCells: 4, MaxRes: 10},
+			points:  []orb.Point{{13.4, 52.5}},
+		},
+		{
+			name:    "antimeridian",
+			geom:    fixture.Chukotka(),
+			coverer: Coverer{MaxCells: 64, MinRes: 1, MaxRes: 6},
+			points:  []orb.Point{{179.9, 66}, {-179.9, 66}, {170, 65}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := tt.coverer.Cover(tt.geom)
+			if len(cells) == 0 || len(cells) > tt.coverer.MaxCells {
+				t.Fatalf("Cover() returns %d cells, max %d", len(cells), tt.coverer.MaxCells)
+			}
+			for _, c := range cells {
+				if res := H3Res(c); res < tt.coverer.MinRes || res > tt.coverer.MaxRes {
+					t.Errorf("cell %v resolution %d out of range", c, res)
+				}
+			}
+			for _, p := range tt.points {
+				if !coverContains(cells, p, tt.coverer.MaxRes) {
+					t.Errorf("point %v is not covered", p)
+				}
+			}
+		})
+	}
+}
+
+func TestCoverer_CoverMixedResolutions(t *testing.T) {
+	square := orb.Polygon{{{13.0, 52.0}, {14.0, 52.0}, {14.0, 53.0}, {13.0, 53.0}, {13.0, 52.0}}}
+	coverer := Coverer{MaxCells: 64, MinRes: 3, MaxRes: 9}
+	cells := coverer.Cover(square)
+	resolutions := map[int]int{}
+	area := 0.
+	for _, c := range cells {
+		resolutions[H3Res(c)]++
+		area += h3.CellAreaKm2(c)
+	}
+	if len(resolutions) < 2 {
+		t.Errorf("Cover() returns cells of resolutions %v, want mixed resolutions", resolutions)
+	}
+
+	// the finest covering of single resolution with the same cells limit
+	var single []h3.Cell
+	for res := coverer.MinRes; res <= coverer.MaxRes; res++ {
+		cells := Compact(CoverCells(square, res))
+		if len(cells) > coverer.MaxCells {
+			break
+		}
+		single = cells
+	}
+	singleArea := 0.
+	for _, c := range single {
+		singleArea += h3.CellAreaKm2(c)
+	}
+	if area >= singleArea {
+		t.Errorf("Cover() area %.0f km2 is not less than area %.0f km2 of single resolution covering", area, singleArea)
+	}
+}
+
+// coverContains checks point is inside one of the cells or its cell of given resolution is a child of one of the cells,
+// compacted cells contain points of their children, that can be out of the parent cell boundary.
+func coverContains(cells []h3.Cell, p orb.Point, res int) bool {
+	ll := h3.NewLatLng(p.Lat(), p.Lon())
+	for _, c := range cells {
+		if ll.Cell(H3Res(c)) == c || IsParent(c, ll.Cell(res)) {
+			return true
+		}
+	}
+	return false
+}
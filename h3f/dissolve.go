@@ -0,0 +1,223 @@
+package h3f
+
+import (
+	"math"
+	"sort"
+
+	"github.com/VGSML/geobin/orbf"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+	"github.com/uber/h3-go/v4"
+)
+
+// CellsToMultiPolygon dissolves cells to multipolygon with holes.
+// Cells of mixed resolutions are dissolved as their children of the finest resolution,
+// only children near the boundary of the cells are expanded.
+// Duplicated and overlapped cells are allowed. Polygons that cross antimeridian are split.
+func CellsToMultiPolygon(cells []h3.Cell) orb.MultiPolygon {
+	set := newCellSet(cells)
+	if len(set.cells) == 0 {
+		return nil
+	}
+
+	// collect edges between cells of the set and cells out of the set,
+	// edges of h3 cells are directed counterclockwise
+	edges := map[vertexKey]boundaryEdge{}
+	for cell := range set.boundary() {
+		for _, e := range cell.DirectedEdges() {
+			if e == 0 {
+				continue // deleted direction of pentagon
+			}
+			if set.contains(e.Destination()) {
+				continue
+			}
+			b := e.Boundary()
+			if len(b) < 2 {
+				continue
+			}
+			pp := make([]orb.Point, 0, len(b)-1)
+			for _, v := range b[:len(b)-1] {
+				pp = append(pp, orb.Point{v.Lng, v.Lat})
+			}
+			edges[newVertexKey(b[0])] = boundaryEdge{
+				points: pp,
+				end:    newVertexKey(b[len(b)-1]),
+			}
+		}
+	}
+
+	// link edges to rings
+	var shells, holes []orb.Ring
+	for len(edges) != 0 {
+		var start vertexKey
+		for k := range edges {
+			start = k
+			break
+		}
+		var ring orb.Ring
+		for k := start; ; {
+			e, ok := edges[k]
+			if !ok {
+				break
+			}
+			delete(edges, k)
+			ring = append(ring, e.points...)
+			k = e.end
+		}
+		if len(ring) < 3 {
+			continue
+		}
+		ring = append(ring, ring[0])
+		u := unwrapRing(ring)
+		if planar.Area(u) < 0 {
+			holes = append(holes, u)
+			continue
+		}
+		shells = append(shells, u)
+	}
+
+	// larger shells first, so the order doesn't depend on iteration over the edges map
+	sort.Slice(shells, func(i, j int) bool {
+		return planar.Area(shells[i]) > planar.Area(shells[j])
+	})
+	mp := make(orb.MultiPolygon, 0, len(shells))
+	for _, s := range shells {
+		mp = append(mp, orb.Polygon{s})
+	}
+	for _, h := range holes {
+		// hole belongs to the smallest shell that contains it
+		n, area := -1, math.Inf(1)
+		for i, p := range mp {
+			if a := planar.Area(p[0]); a < area && ringContainsRing(p[0], h) {
+				n, area = i, a
+			}
+		}
+		if n != -1 {
+			mp[n] = append(mp[n], h)
+		}
+	}
+	for _, p := range mp {
+		for _, r := range p {
+			for i := range r {
+				r[i][0] = orbf.NormalizeLon(r[i][0])
+			}
+		}
+	}
+	if g, ok := orbf.SplitAntimeridian(mp).(orb.MultiPolygon); ok {
+		return g
+	}
+	return mp
+}
+
+// cellSet is a set of cells of mixed resolutions.
+type cellSet struct {
+	cells  map[h3.Cell]struct{}
+	minRes int
+	maxRes int
+}
+
+func newCellSet(cells []h3.Cell) cellSet {
+	s := cellSet{cells: make(map[h3.Cell]struct{}, len(cells)), minRes: 15}
+	for _, c := range cells {
+		s.cells[c] = struct{}{}
+		s.minRes = min(s.minRes, H3Res(c))
+		s.maxRes = max(s.maxRes, H3Res(c))
+	}
+	return s
+}
+
+// contains checks the set contains the cell or its parent.
+func (s cellSet) contains(cell h3.Cell) bool {
+	for res := H3Res(cell); res >= s.minRes; res-- {
+		if _, ok := s.cells[H3Parent(cell, res)]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// inside checks the set contains the cell and its neighbors,
+// so children of the cell of any resolution have no neighbors out of the set.
+func (s cellSet) inside(cell h3.Cell) bool {
+	for _, n := range GridDisk(cell, 1) {
+		if !s.contains(n) {
+			return false
+		}
+	}
+	return true
+}
+
+// boundary returns children of the set cells of the finest resolution, that can have neighbors out of the set.
+// Cells are expanded to children recursively, children inside the set are skipped.
+func (s cellSet) boundary() map[h3.Cell]struct{} {
+	out := map[h3.Cell]struct{}{}
+	var add func(cell h3.Cell)
+	add = func(cell h3.Cell) {
+		res := H3Res(cell)
+		if res == s.maxRes {
+			out[cell] = struct{}{}
+			return
+		}
+		for _, child := range Children(cell, res+1) {
+			if !s.inside(child) {
+				add(child)
+			}
+		}
+	}
+	for c := range s.cells {
+		if res := H3Res(c); res > s.minRes && s.contains(H3Parent(c, res-1)) {
+			continue // overlapped by the parent
+		}
+		if !s.inside(c) {
+			add(c)
+		}
+	}
+	return out
+}
+
+// vertexKey identifies cell vertex computed from different cells.
+type vertexKey [2]int64
+
+// vertexPrecision is a precision of vertex coordinates in degrees (~1 cm).
+const vertexPrecision = 1e7
+
+func newVertexKey(ll h3.LatLng) vertexKey {
+	lng := ll.Lng
+	if lng <= -180 {
+		lng += 360
+	}
+	return vertexKey{
+		int64(math.Round(ll.Lat * vertexPrecision)),
+		int64(math.Round(lng * vertexPrecision)),
+	}
+}
+
+type boundaryEdge struct {
+	points []orb.Point // edge points without last one
+	end    vertexKey
+}
+
+// unwrapRing returns ring with continuous longitudes.
+func unwrapRing(r orb.Ring) orb.Ring {
+	out := make(orb.Ring, len(r))
+	offset := 0.
+	for i, p := range r {
+		if i > 0 {
+			if d := p.Lon() - r[i-1].Lon(); d > 180 {
+				offset -= 360
+			} else if d < -180 {
+				offset += 360
+			}
+		}
+		out[i] = orb.Point{p.Lon() + offset, p.Lat()}
+	}
+	return out
+}
+
+// ringContainsRing checks that unwrapped hole is inside unwrapped shell.
+func ringContainsRing(shell, hole orb.Ring) bool {
+	p := hole[0]
+	// shift hole to the longitudes of the shell
+	p[0] += 360 * math.Round((shell.Bound().Center().Lon()-p.Lon())/360)
+	return planar.RingContains(shell, p)
+}
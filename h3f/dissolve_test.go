@@ -0,0 +1,167 @@
+package h3f
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"github.com/uber/h3-go/v4"
+)
+
+func TestCellsToMultiPolygon(t *testing.T) {
+	center := h3.NewLatLng(52.52, 13.405).Cell(7)
+	pentagon := h3.Cell(0x8009fffffffffff).Children(4)[0]
+	if !pentagon.IsPentagon() {
+		t.Fatalf("expected pentagon cell")
+	}
+	disk := center.GridDisk(2)
+	ring := make([]h3.Cell, 0, len(disk))
+	for _, c := range disk {
+		if c != center {
+			ring = append(ring, c)
+		}
+	}
+	parent := center.Parent(5)
+
+	tests := []struct {
+		name     string
+		cells    []h3.Cell
+		polygons int
+		holes    int
+		area     []h3.Cell // cells of the same area
+	}{
+		{
+			name:     "single cell",
+			cells:    []h3.Cell{center},
+			polygons: 1,
+		},
+		{
+			name:     "disk",
+			cells:    disk,
+			polygons: 1,
+		},
+		{
+			name:     "disk with hole",
+			cells:    ring,
+			polygons: 1,
+			holes:    1,
+		},
+		{
+			name:     "island in hole",
+			cells:    append([]h3.Cell{center}, center.GridDisk(3)[19:]...),
+			polygons: 2,
+			holes:    1,
+		},
+		{
+			name:     "mixed resolutions",
+			cells:    []h3.Cell{parent, h3.NewLatLng(48.1351, 11.5810).Cell(7), center},
+			polygons: 2,
+			area:     append(parent.Children(7), h3.NewLatLng(48.1351, 11.5810).Cell(7)),
+		},
+		{
+			name:     "coarse and fine cells",
+			cells:    []h3.Cell{center.Parent(3), h3.NewLatLng(48.1351, 11.5810).Cell(10)},
+			polygons: 2,
+			area:     []h3.Cell{center.Parent(3), h3.NewLatLng(48.1351, 11.5810).Cell(10)},
+		},
+		{
+			name:     "pentagon",
+			cells:    pentagon.GridDisk(1),
+			polygons: 1,
+		},
+		{
+			name:     "antimeridian",
+			cells:    h3.NewLatLng(65, 180).Cell(4).GridDisk(2),
+			polygons: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mp := CellsToMultiPolygon(tt.cells)
+			if len(mp) != tt.polygons {
+				t.Fatalf("expected %d polygons, got %d", tt.polygons, len(mp))
+			}
+			holes := 0
+			for _, p := range mp {
+				holes += len(p) - 1
+				for _, r := range p {
+					if !r.Closed() {
+						t.Errorf("ring is not closed")
+					}
+					b := r.Bound()
+					if b.Min.Lon() < -180 || b.Max.Lon() > 180 {
+						t.Errorf("ring is out of longitude range: %v", b)
+					}
+				}
+			}
+			if holes != tt.holes {
+				t.Errorf("expected %d holes, got %d", tt.holes, holes)
+			}
+			cells := tt.area
+			if cells == nil {
+				cells = uncompactCells(tt.cells)
+			}
+			want := 0.
+			for _, c := range cells {
+				want += h3.CellAreaM2(c)
+			}
+			if got := multiPolygonArea(mp); math.Abs(got-want)/want > 0.01 {
+				t.Errorf("expected area %v, got %v", want, got)
+			}
+		})
+	}
+	if mp := CellsToMultiPolygon(nil); mp != nil {
+		t.Errorf("expected nil for empty cells")
+	}
+}
+
+func TestCellsToMultiPolygonUncompacted(t *testing.T) {
+	center := h3.NewLatLng(52.52, 13.405).Cell(5)
+	pentagon := h3.Cell(0x8009fffffffffff).Children(2)[0]
+	for _, cells := range [][]h3.Cell{
+		// coarse cell with finer cells around it and inside its neighbor
+		append(append([]h3.Cell{center}, center.GridDisk(1)[1].Children(7)[:30]...), center.GridDisk(2)[9].Children(6)...),
+		// neighbors of pentagon inside its parent
+		append(pentagon.GridDisk(1)[1:], pentagon.Children(4)[3:]...),
+	} {
+		mp := CellsToMultiPolygon(cells)
+		want := CellsToMultiPolygon(uncompactCells(cells))
+		if len(mp) != len(want) {
+			t.Fatalf("expected %d polygons as for uncompacted cells, got %d", len(want), len(mp))
+		}
+		if got, want := multiPolygonArea(mp), multiPolygonArea(want); math.Abs(got-want)/want > 1e-9 {
+			t.Errorf("expected area %v as for uncompacted cells, got %v", want, got)
+		}
+		for n := range mp {
+			if len(mp[n]) != len(want[n]) || len(mp[n][0]) != len(want[n][0]) {
+				t.Errorf("polygon %d differs from polygon of uncompacted cells", n)
+			}
+		}
+	}
+}
+
+// uncompactCells returns unique cells uncompacted to the finest resolution of given cells.
+func uncompactCells(cells []h3.Cell) []h3.Cell {
+	res := 0
+	for _, c := range cells {
+		res = max(res, c.Resolution())
+	}
+	set := map[h3.Cell]struct{}{}
+	for _, c := range Uncompact(cells, res) {
+		set[c] = struct{}{}
+	}
+	out := make([]h3.Cell, 0, len(set))
+	for c := range set {
+		out = append(out, c)
+	}
+	return out
+}
+
+func multiPolygonArea(mp orb.MultiPolygon) float64 {
+	area := 0.
+	for _, p := range mp {
+		area += math.Abs(geo.Area(p))
+	}
+	return area
+}
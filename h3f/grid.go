@@ -0,0 +1,301 @@
+package h3f
+
+import (
+	"math/bits"
+
+	"github.com/uber/h3-go/v4"
+)
+
+// Neighbors of cells are found by traversal of the cell digits as h3NeighborRotations of the H3 library.
+
+const (
+	// invalidBaseCell marks the deleted K direction of pentagon base cells in baseCellNeighbors.
+	invalidBaseCell = 127
+
+	kAxesDigit  = 1
+	jAxesDigit  = 2
+	jkAxesDigit = 3
+	iAxesDigit  = 4
+	ikAxesDigit = 5
+	ijAxesDigit = 6
+)
+
+// newDigitII is the new digit when traversing along class II grids: current digit -> direction -> new digit.
+var newDigitII = [7][7]int{
+	{0, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit},
+	{kAxesDigit, iAxesDigit, jkAxesDigit, ijAxesDigit, ikAxesDigit, jAxesDigit, 0},
+	{jAxesDigit, jkAxesDigit, kAxesDigit, iAxesDigit, ijAxesDigit, 0, ikAxesDigit},
+	{jkAxesDigit, ijAxesDigit, iAxesDigit, ikAxesDigit, 0, kAxesDigit, jAxesDigit},
+	{iAxesDigit, ikAxesDigit, ijAxesDigit, 0, jAxesDigit, jkAxesDigit, kAxesDigit},
+	{ikAxesDigit, jAxesDigit, 0, kAxesDigit, jkAxesDigit, ijAxesDigit, iAxesDigit},
+	{ijAxesDigit, 0, ikAxesDigit, jAxesDigit, kAxesDigit, iAxesDigit, jkAxesDigit},
+}
+
+// newAdjustmentII is the direction of the move at the coarser resolution when traversing along class II grids.
+var newAdjustmentII = [7][7]int{
+	{0, 0, 0, 0, 0, 0, 0},
+	{0, kAxesDigit, 0, kAxesDigit, 0, ikAxesDigit, 0},
+	{0, 0, jAxesDigit, jkAxesDigit, 0, 0, jAxesDigit},
+	{0, kAxesDigit, jkAxesDigit, jkAxesDigit, 0, 0, 0},
+	{0, 0, 0, 0, iAxesDigit, iAxesDigit, ijAxesDigit},
+	{0, ikAxesDigit, 0, 0, iAxesDigit, ikAxesDigit, 0},
+	{0, 0, jAxesDigit, 0, ijAxesDigit, 0, ijAxesDigit},
+}
+
+// newDigitIII is the new digit when traversing along class III grids: current digit -> direction -> new digit.
+var newDigitIII = [7][7]int{
+	{0, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit},
+	{kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit, 0},
+	{jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit, 0, kAxesDigit},
+	{jkAxesDigit, iAxesDigit, ikAxesDigit, ijAxesDigit, 0, kAxesDigit, jAxesDigit},
+	{iAxesDigit, ikAxesDigit, ijAxesDigit, 0, kAxesDigit, jAxesDigit, jkAxesDigit},
+	{ikAxesDigit, ijAxesDigit, 0, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit},
+	{ijAxesDigit, 0, kAxesDigit, jAxesDigit, jkAxesDigit, iAxesDigit, ikAxesDigit},
+}
+
+// newAdjustmentIII is the direction of the move at the coarser resolution when traversing along class III grids.
+var newAdjustmentIII = [7][7]int{
+	{0, 0, 0, 0, 0, 0, 0},
+	{0, kAxesDigit, 0, jkAxesDigit, 0, kAxesDigit, 0},
+	{0, 0, jAxesDigit, jAxesDigit, 0, 0, ijAxesDigit},
+	{0, jkAxesDigit, jAxesDigit, jkAxesDigit, 0, 0, 0},
+	{0, 0, 0, 0, iAxesDigit, ikAxesDigit, iAxesDigit},
+	{0, kAxesDigit, 0, 0, ikAxesDigit, ikAxesDigit, 0},
+	{0, 0, ijAxesDigit, 0, iAxesDigit, 0, ijAxesDigit},
+}
+
+// ringDirections are directions of the sides of the ring, the ring starts from the cell in nextRingDirection.
+var ringDirections = [6]int{jAxesDigit, jkAxesDigit, kAxesDigit, ikAxesDigit, iAxesDigit, ijAxesDigit}
+
+const nextRingDirection = iAxesDigit
+
+// rotate60ccw and rotate60cw are digits rotated by 60 degrees.
+var (
+	rotate60ccw = [7]int{0, ikAxesDigit, jkAxesDigit, kAxesDigit, ijAxesDigit, iAxesDigit, jAxesDigit}
+	rotate60cw  = [7]int{0, jkAxesDigit, ijAxesDigit, jAxesDigit, ikAxesDigit, kAxesDigit, iAxesDigit}
+)
+
+// GridDisk returns cells within grid distance k of the valid cell as h3 GridDisk without cgo calls,
+// the cell is the first, cells are ordered by distance.
+func GridDisk(cell h3.Cell, k int) []h3.Cell {
+	out := make([]h3.Cell, 1, 1+3*k*(k+1))
+	out[0] = cell
+	if ring, ok := appendRings(out, cell, k); ok {
+		return ring
+	}
+	// walk around pentagons by breadth-first search,
+	// neighbors of cells at distance d are at distance d-1, d or d+1
+	prev, start := 0, 0
+	for d := 0; d < k; d++ {
+		end := len(out)
+		for _, c := range out[start:end] {
+			for dir := kAxesDigit; dir <= ijAxesDigit; dir++ {
+				n, ok := neighbor(c, dir)
+				if ok && !containsCell(out[prev:], n) {
+					out = append(out, n)
+				}
+			}
+		}
+		if end == len(out) {
+			break
+		}
+		prev, start = start, end
+	}
+	return out
+}
+
+// appendRings appends rings of cells around the cell up to distance k by walking along their sides,
+// returns false if a pentagon is found, then rings are distorted.
+func appendRings(out []h3.Cell, cell h3.Cell, k int) ([]h3.Cell, bool) {
+	if IsPentagon(cell) {
+		return out, false
+	}
+	rotations := 0
+	var ok bool
+	for ring := 1; ring <= k; ring++ {
+		if cell, rotations, ok = neighborRotations(cell, nextRingDirection, rotations); !ok || IsPentagon(cell) {
+			return out, false
+		}
+		for _, dir := range ringDirections {
+			for i := 0; i < ring; i++ {
+				if cell, rotations, ok = neighborRotations(cell, dir, rotations); !ok || IsPentagon(cell) {
+					return out, false
+				}
+				out = append(out, cell)
+			}
+		}
+	}
+	return out, true
+}
+
+// AreNeighbors checks cells are different neighbor cells of the same resolution.
+func AreNeighbors(a, b h3.Cell) bool {
+	if a == b || H3Res(a) != H3Res(b) {
+		return false
+	}
+	for dir := kAxesDigit; dir <= ijAxesDigit; dir++ {
+		if n, ok := neighbor(a, dir); ok && n == b {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCell(cells []h3.Cell, cell h3.Cell) bool {
+	for _, c := range cells {
+		if c == cell {
+			return true
+		}
+	}
+	return false
+}
+
+// neighbor returns the neighbor of the cell in the direction,
+// returns false for the deleted K direction of pentagon and for invalid cells.
+func neighbor(cell h3.Cell, dir int) (h3.Cell, bool) {
+	n, _, ok := neighborRotations(cell, dir, 0)
+	return n, ok
+}
+
+// neighborRotations returns the neighbor of the cell in the direction rotated by 60 degrees ccw rotations times,
+// and count of rotations to keep the direction in the coordinate system of the neighbor.
+func neighborRotations(cell h3.Cell, dir, rotations int) (h3.Cell, int, bool) {
+	origin := cell
+	oldBaseCell := BaseCellNum(cell)
+	if oldBaseCell >= numBaseCells || dir <= 0 || dir > ijAxesDigit {
+		return 0, rotations, false
+	}
+	rotations %= 6
+	for i := 0; i < rotations; i++ {
+		dir = rotate60ccw[dir]
+	}
+
+	// adjust digits from the finest resolution and the base cell if needed
+	newRotations := 0
+	for r := H3Res(cell); ; r-- {
+		if r == 0 {
+			newRotations = int(baseCellNeighbor60CCWRots[oldBaseCell][dir])
+			bn := baseCellNeighbors[oldBaseCell][dir]
+			if bn == invalidBaseCell {
+				// the edge borders the neighbor in IK direction
+				newRotations = int(baseCellNeighbor60CCWRots[oldBaseCell][ikAxesDigit])
+				bn = baseCellNeighbors[oldBaseCell][ikAxesDigit]
+				cell = rotateCell60ccw(cell)
+				rotations++
+			}
+			cell = setBaseCell(cell, int(bn))
+			break
+		}
+		oldDigit := CellIndexInRes(cell, r)
+		if oldDigit == 7 {
+			return 0, rotations, false
+		}
+		var nextDir int
+		if r%2 == 1 {
+			cell = setDigit(cell, r, newDigitII[oldDigit][dir])
+			nextDir = newAdjustmentII[oldDigit][dir]
+		} else {
+			cell = setDigit(cell, r, newDigitIII[oldDigit][dir])
+			nextDir = newAdjustmentIII[oldDigit][dir]
+		}
+		if nextDir == 0 {
+			break
+		}
+		dir = nextDir
+	}
+
+	newBaseCell := BaseCellNum(cell)
+	if !pentagonBaseCells[newBaseCell] {
+		for i := 0; i < newRotations; i++ {
+			cell = rotateCell60ccw(cell)
+		}
+		return cell, (rotations + newRotations) % 6, true
+	}
+	// rotate out of the deleted K subsequence of pentagon
+	adjusted := false
+	if leadingNonZeroDigit(cell) == kAxesDigit {
+		switch {
+		case oldBaseCell != newBaseCell:
+			faces := pentagonCwOffsetFaces[newBaseCell]
+			if face := baseCellHomeFaces[oldBaseCell]; faces[0] == face || faces[1] == face {
+				cell = rotateCell60cw(cell)
+			} else {
+				cell = rotateCell60ccw(cell)
+			}
+			adjusted = true
+		case leadingNonZeroDigit(origin) == jkAxesDigit:
+			cell = rotateCell60ccw(cell)
+			rotations++
+		case leadingNonZeroDigit(origin) == ikAxesDigit:
+			cell = rotateCell60cw(cell)
+			rotations += 5
+		default:
+			// the K direction is deleted from the pentagon center
+			return 0, rotations, false
+		}
+	}
+	for i := 0; i < newRotations; i++ {
+		cell = rotatePentagon60ccw(cell)
+	}
+	// account for different orientation of base cells
+	if oldBaseCell != newBaseCell {
+		if newBaseCell == 4 || newBaseCell == 117 {
+			// polar pentagons have all neighbors in I direction
+			if oldBaseCell != 118 && oldBaseCell != 8 && leadingNonZeroDigit(cell) != jkAxesDigit {
+				rotations++
+			}
+		} else if leadingNonZeroDigit(cell) == ikAxesDigit && !adjusted {
+			rotations++
+		}
+	}
+	return cell, (rotations + newRotations) % 6, true
+}
+
+// leadingNonZeroDigit returns the first non-zero digit of the cell, or 0 if all digits are zero.
+func leadingNonZeroDigit(cell h3.Cell) int {
+	digits := uint64(cell) & (1<<45 - 1) &^ (1<<(3*(15-H3Res(cell))) - 1)
+	if digits == 0 {
+		return 0
+	}
+	// digits of coarser resolutions are in higher bits
+	shift := (63 - bits.LeadingZeros64(digits)) / 3 * 3
+	return int(digits>>shift) & 7
+}
+
+func setDigit(cell h3.Cell, res, digit int) h3.Cell {
+	shift := 3 * (15 - res)
+	return cell&^(7<<shift) | h3.Cell(digit)<<shift
+}
+
+func setBaseCell(cell h3.Cell, baseCell int) h3.Cell {
+	return cell&^(127<<45) | h3.Cell(baseCell)<<45
+}
+
+func rotateCell60ccw(cell h3.Cell) h3.Cell {
+	for r := 1; r <= H3Res(cell); r++ {
+		cell = setDigit(cell, r, rotate60ccw[CellIndexInRes(cell, r)])
+	}
+	return cell
+}
+
+func rotateCell60cw(cell h3.Cell) h3.Cell {
+	for r := 1; r <= H3Res(cell); r++ {
+		cell = setDigit(cell, r, rotate60cw[CellIndexInRes(cell, r)])
+	}
+	return cell
+}
+
+// rotatePentagon60ccw rotates the cell about pentagon center, skipping the deleted K subsequence.
+func rotatePentagon60ccw(cell h3.Cell) h3.Cell {
+	found := false
+	for r := 1; r <= H3Res(cell); r++ {
+		cell = setDigit(cell, r, rotate60ccw[CellIndexInRes(cell, r)])
+		if !found && CellIndexInRes(cell, r) != 0 {
+			found = true
+			if leadingNonZeroDigit(cell) == kAxesDigit {
+				cell = rotateCell60ccw(cell)
+			}
+		}
+	}
+	return cell
+}
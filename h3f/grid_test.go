@@ -0,0 +1,37 @@
+package h3f
+
+import (
+	"testing"
+
+	"github.com/uber/h3-go/v4"
+	"golang.org/x/exp/slices"
+)
+
+func TestGridDiskParity(t *testing.T) {
+	cells := testCells()
+	// neighbors of pentagons and cells near base cell edges
+	for _, p := range h3.Pentagons(5) {
+		cells = append(cells, p.GridDisk(2)...)
+	}
+	for _, cell := range cells {
+		for k := 0; k <= 3; k++ {
+			got, want := GridDisk(cell, k), cell.GridDisk(k)
+			if got[0] != cell {
+				t.Errorf("GridDisk(%v, %d) first cell is %v", cell, k, got[0])
+			}
+			slices.Sort(got)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Fatalf("GridDisk(%v, %d) = %v, want %v", cell, k, got, want)
+			}
+		}
+		for _, c := range cell.GridDisk(2) {
+			if got, want := AreNeighbors(cell, c), cell.IsNeighbor(c); got != want {
+				t.Errorf("AreNeighbors(%v, %v) = %v, want %v", cell, c, got, want)
+			}
+		}
+	}
+	if AreNeighbors(cells[0], cells[0].Parent(0)) {
+		t.Errorf("cells of different resolutions are not neighbors")
+	}
+}
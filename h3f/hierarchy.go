@@ -0,0 +1,210 @@
+package h3f
+
+import (
+	"github.com/uber/h3-go/v4"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// cellMode is the mode of H3 cell index.
+	cellMode = 1
+	// numBaseCells is the count of H3 base cells.
+	numBaseCells = 122
+	// deletedDigit is the digit of the deleted subsequence of pentagons (K axis).
+	deletedDigit = 1
+)
+
+// pentagonBaseCells is the set of pentagon base cells.
+var pentagonBaseCells = [numBaseCells]bool{
+	4: true, 14: true, 24: true, 38: true, 49: true, 58: true,
+	63: true, 72: true, 83: true, 97: true, 107: true, 117: true,
+}
+
+// IsBaseCellPentagon checks the base cell is pentagon.
+func IsBaseCellPentagon(baseCell int) bool {
+	return baseCell >= 0 && baseCell < numBaseCells && pentagonBaseCells[baseCell]
+}
+
+// IsValid checks the cell is valid H3 cell index.
+func IsValid(cell h3.Cell) bool {
+	if cell>>63 != 0 || int(cell>>59)&15 != cellMode || int(cell>>56)&7 != 0 {
+		return false
+	}
+	bn := BaseCellNum(cell)
+	if bn >= numBaseCells {
+		return false
+	}
+	res := H3Res(cell)
+	pentagon := pentagonBaseCells[bn]
+	for r := 1; r <= 15; r++ {
+		digit := CellIndexInRes(cell, r)
+		if r > res {
+			if digit != 7 {
+				return false
+			}
+			continue
+		}
+		if digit == 7 {
+			return false
+		}
+		if pentagon && digit != 0 {
+			if digit == deletedDigit {
+				return false
+			}
+			pentagon = false
+		}
+	}
+	return true
+}
+
+// IsPentagon checks the cell is pentagon.
+func IsPentagon(cell h3.Cell) bool {
+	if !pentagonBaseCells[BaseCellNum(cell)%numBaseCells] {
+		return false
+	}
+	res := H3Res(cell)
+	// all digits up to resolution are zero
+	return cell&h3.Cell(1<<45-1)>>(3*(15-res)) == 0
+}
+
+// CenterChild returns center child of the cell at given resolution,
+// returns 0 if resolution is less than cell resolution or greater than 15.
+func CenterChild(cell h3.Cell, res int) h3.Cell {
+	cellRes := H3Res(cell)
+	if res < cellRes || res > 15 {
+		return 0
+	}
+	// set resolution and zero digits from cellRes+1 to res
+	mask := h3.Cell(1<<(3*(15-cellRes)) - 1 - (1<<(3*(15-res)) - 1))
+	return setRes(cell&^mask, res)
+}
+
+// ChildrenCount returns count of children of the cell at given resolution.
+func ChildrenCount(cell h3.Cell, res int) int {
+	cellRes := H3Res(cell)
+	if res < cellRes || res > 15 {
+		return 0
+	}
+	n := 1
+	for r := cellRes; r < res; r++ {
+		n *= 7
+	}
+	if IsPentagon(cell) {
+		// one center child and 5 hexagon branches of each resolution
+		return 1 + 5*(n-1)/6
+	}
+	return n
+}
+
+// Children returns children of the cell at given resolution in ascending order.
+func Children(cell h3.Cell, res int) []h3.Cell {
+	return AppendChildren(make([]h3.Cell, 0, ChildrenCount(cell, res)), cell, res)
+}
+
+// AppendChildren appends children of the cell at given resolution to dst in ascending order.
+func AppendChildren(dst []h3.Cell, cell h3.Cell, res int) []h3.Cell {
+	cellRes := H3Res(cell)
+	if res < cellRes || res > 15 {
+		return dst
+	}
+	child := CenterChild(cell, res)
+	if res == cellRes {
+		return append(dst, child)
+	}
+	pentagon := IsPentagon(cell)
+	for {
+		dst = append(dst, child)
+		// increment digits from the finest resolution
+		r := res
+		for ; r > cellRes; r-- {
+			shift := 3 * (15 - r)
+			digit := int(child>>shift) & 7
+			if digit < 6 {
+				child += 1 << shift
+				break
+			}
+			child &^= 7 << shift
+		}
+		if r == cellRes {
+			return dst
+		}
+		if pentagon && isDeletedSubsequence(child, cellRes, res) {
+			// skip deleted subsequence by incrementing its digit again
+			shift := 3 * (15 - firstNonZeroRes(child, cellRes, res))
+			child += 1 << shift
+		}
+	}
+}
+
+// isDeletedSubsequence checks the first non-zero digit of the child of pentagon is deleted digit.
+func isDeletedSubsequence(child h3.Cell, cellRes, res int) bool {
+	r := firstNonZeroRes(child, cellRes, res)
+	return r != 0 && CellIndexInRes(child, r) == deletedDigit
+}
+
+// firstNonZeroRes returns resolution of the first non-zero digit after cellRes, or 0 if all digits are zero.
+func firstNonZeroRes(child h3.Cell, cellRes, res int) int {
+	for r := cellRes + 1; r <= res; r++ {
+		if CellIndexInRes(child, r) != 0 {
+			return r
+		}
+	}
+	return 0
+}
+
+// setRes sets resolution of the cell index and fills unused digits.
+func setRes(cell h3.Cell, res int) h3.Cell {
+	cell = cell&^(15<<52) | h3.Cell(res)<<52
+	return cell | h3.Cell(1<<(3*(15-res))-1)
+}
+
+// Compact merges full sets of children into their parent recursively.
+// Cells can be of mixed resolutions, duplicates are removed, cells must not overlap.
+func Compact(cells []h3.Cell) []h3.Cell {
+	var byRes [16][]h3.Cell
+	maxRes := 0
+	for _, c := range cells {
+		res := H3Res(c)
+		byRes[res] = append(byRes[res], c)
+		maxRes = max(maxRes, res)
+	}
+	out := make([]h3.Cell, 0, len(cells))
+	for res := maxRes; res > 0; res-- {
+		level := byRes[res]
+		if len(level) == 0 {
+			continue
+		}
+		slices.Sort(level)
+		level = slices.Compact(level)
+		// siblings are consecutive in sorted cells
+		for start := 0; start < len(level); {
+			parent := H3Parent(level[start], res-1)
+			end := start + 1
+			for end < len(level) && H3Parent(level[end], res-1) == parent {
+				end++
+			}
+			if end-start == ChildrenCount(parent, res) {
+				byRes[res-1] = append(byRes[res-1], parent)
+			} else {
+				out = append(out, level[start:end]...)
+			}
+			start = end
+		}
+	}
+	slices.Sort(byRes[0])
+	return append(out, slices.Compact(byRes[0])...)
+}
+
+// Uncompact returns children at given resolution of the all cells.
+// Cells of finer resolution than res are skipped.
+func Uncompact(cells []h3.Cell, res int) []h3.Cell {
+	n := 0
+	for _, c := range cells {
+		n += ChildrenCount(c, res)
+	}
+	out := make([]h3.Cell, 0, n)
+	for _, c := range cells {
+		out = AppendChildren(out, c, res)
+	}
+	return out
+}
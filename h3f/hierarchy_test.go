@@ -0,0 +1,120 @@
+package h3f
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/uber/h3-go/v4"
+	"golang.org/x/exp/slices"
+)
+
+// testCells returns random cells of different resolutions and pentagons.
+func testCells() []h3.Cell {
+	rnd := rand.New(rand.NewSource(1))
+	var cells []h3.Cell
+	for i := 0; i < 200; i++ {
+		ll := h3.NewLatLng(rnd.Float64()*180-90, rnd.Float64()*360-180)
+		cells = append(cells, ll.Cell(rnd.Intn(14)))
+	}
+	for _, p := range h3.Pentagons(0) {
+		for res := 0; res < 4; res++ {
+			cells = append(cells, p.CenterChild(res))
+		}
+	}
+	return cells
+}
+
+func TestHierarchyParity(t *testing.T) {
+	for _, cell := range testCells() {
+		res := H3Res(cell)
+		if got, want := IsValid(cell), cell.IsValid(); got != want {
+			t.Errorf("IsValid(%v) = %v, want %v", cell, got, want)
+		}
+		if got, want := IsPentagon(cell), cell.IsPentagon(); got != want {
+			t.Errorf("IsPentagon(%v) = %v, want %v", cell, got, want)
+		}
+		for _, r := range []int{res, res + 1, res + 2} {
+			if r > 15 {
+				continue
+			}
+			if got, want := CenterChild(cell, r), cell.CenterChild(r); got != want {
+				t.Errorf("CenterChild(%v, %d) = %v, want %v", cell, r, got, want)
+			}
+			got, want := Children(cell, r), cell.Children(r)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("Children(%v, %d) = %v, want %v", cell, r, got, want)
+			}
+			if n := ChildrenCount(cell, r); n != len(want) {
+				t.Errorf("ChildrenCount(%v, %d) = %d, want %d", cell, r, n, len(want))
+			}
+		}
+		if res > 0 && CenterChild(cell, res-1) != 0 {
+			t.Errorf("CenterChild(%v, %d) should be 0 for coarser resolution", cell, res-1)
+		}
+	}
+}
+
+func TestIsValidParity(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	invalid := 0
+	for _, cell := range testCells() {
+		for i := 0; i < 20; i++ {
+			// flip random bit of the cell index
+			c := cell ^ h3.Cell(1)<<rnd.Intn(64)
+			got, want := IsValid(c), c.IsValid()
+			if got != want {
+				t.Errorf("IsValid(%x) = %v, want %v", uint64(c), got, want)
+			}
+			if !want {
+				invalid++
+			}
+		}
+	}
+	if invalid == 0 {
+		t.Errorf("expected invalid cells in test")
+	}
+	for _, p := range h3.Pentagons(2) {
+		// first non-zero digit of pentagon is deleted digit
+		c := CenterChild(p, 3) | h3.Cell(deletedDigit)<<(3*(15-3))
+		if IsValid(c) || c.IsValid() {
+			t.Errorf("cell %v in deleted subsequence should be invalid", c)
+		}
+	}
+}
+
+func TestCompactParity(t *testing.T) {
+	for _, cell := range testCells() {
+		res := H3Res(cell)
+		if res > 12 {
+			continue
+		}
+		// all children except one of the center child children
+		children := cell.Children(res + 2)
+		children = children[1:]
+		want := h3.CompactCells(children)
+		got := Compact(children)
+		slices.Sort(want)
+		slices.Sort(got)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Compact(children of %v) = %v, want %v", cell, got, want)
+		}
+		full := Compact(cell.Children(res + 2))
+		if len(full) != 1 || full[0] != cell {
+			t.Errorf("Compact(all children of %v) = %v", cell, full)
+		}
+
+		got = Uncompact(want, res+2)
+		uncompacted := h3.UncompactCells(want, res+2)
+		slices.Sort(got)
+		slices.Sort(uncompacted)
+		if !reflect.DeepEqual(got, uncompacted) {
+			t.Fatalf("Uncompact(%v) = %v, want %v", want, got, uncompacted)
+		}
+	}
+	mixed := append(Children(h3.Cell(0x8009fffffffffff), 2), Children(h3.Cell(0x8001fffffffffff), 1)...)
+	got := Compact(mixed)
+	if len(got) != 2 {
+		t.Errorf("expected compacting of mixed resolutions to base cells, got %v", got)
+	}
+}
@@ -0,0 +1,250 @@
+package h3f
+
+import (
+	"math"
+
+	"github.com/VGSML/geobin/orbf/sphere"
+	"github.com/paulmach/orb"
+	"github.com/uber/h3-go/v4"
+)
+
+const (
+	earthRadiusM = 6371008.8
+	// maxTraceDepth limits bisection of the arc between not neighboring cells.
+	maxTraceDepth = 48
+	// maxTraceStepEdges and maxTraceStepM limit the step between samples of the arc,
+	// grid line between samples deviates from the arc less than the cell size.
+	maxTraceStepEdges = 32
+	maxTraceStepM     = 50000
+)
+
+// LineCells returns cells crossed by the line, segments of the line are great-circle arcs.
+// Consecutive cells of the result are equal or neighbors, so the path has no gaps.
+func LineCells(line orb.LineString, res int) []h3.Cell {
+	return lineCells(pointsToLatLngArray(line), res)
+}
+
+// LineCellsTolerance returns cells crossed by the line and cells
+// that are closer to the line than tolerance in meters.
+func LineCellsTolerance(line orb.LineString, res int, tolerance float64) []h3.Cell {
+	path := traceLine(pointsToLatLngArray(line), res)
+	if tolerance <= 0 || len(line) == 0 {
+		return tracedCells(path)
+	}
+	if len(line) == 1 {
+		// cells near the point are checked by the segment with equal ends
+		line = orb.LineString{line[0], line[0]}
+	}
+	// cells centers are spaced by about √3 of edge length
+	k := int(math.Ceil(tolerance/(math.Sqrt(3)*h3.HexagonEdgeLengthAvgM(res)))) + 1
+	candidates := map[h3.Cell]map[int]struct{}{}
+	out := make([]h3.Cell, 0, len(path))
+	seen := map[h3.Cell]struct{}{}
+	for _, tc := range path {
+		if _, ok := seen[tc.cell]; !ok {
+			seen[tc.cell] = struct{}{}
+			out = append(out, tc.cell)
+		}
+		for _, c := range GridDisk(tc.cell, k) {
+			if candidates[c] == nil {
+				candidates[c] = map[int]struct{}{}
+			}
+			candidates[c][tc.seg] = struct{}{}
+		}
+	}
+	for c, segs := range candidates {
+		if _, ok := seen[c]; ok {
+			continue
+		}
+		boundary := c.Boundary()
+		for seg := range segs {
+			if cellSegmentDistance(boundary, line[seg], line[seg+1]) <= tolerance {
+				seen[c] = struct{}{}
+				out = append(out, c)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// tracedCell is a cell of the traced line and number of the segment that crosses it.
+type tracedCell struct {
+	cell h3.Cell
+	seg  int
+}
+
+// lineCells returns cells crossed by the line in order of the line direction.
+func lineCells(line []h3.LatLng, res int) []h3.Cell {
+	return tracedCells(traceLine(line, res))
+}
+
+func tracedCells(path []tracedCell) []h3.Cell {
+	out := make([]h3.Cell, 0, len(path))
+	for _, tc := range path {
+		out = append(out, tc.cell)
+	}
+	return out
+}
+
+// traceLine traces line segments along great-circle arcs.
+func traceLine(line []h3.LatLng, res int) []tracedCell {
+	if len(line) == 0 {
+		return nil
+	}
+	out := []tracedCell{{cell: line[0].Cell(res)}}
+	if len(line) == 1 {
+		return out
+	}
+	// sample arc with step of several cells, the grid line between cells of consecutive samples
+	// follows the arc, the arc is bisected only where the grid line can't be walked
+	edge := h3.HexagonEdgeLengthAvgM(res)
+	step := max(min(maxTraceStepEdges*edge, maxTraceStepM), edge/2) / earthRadiusM
+	for i := 1; i < len(line); i++ {
+		a, b := toVector(line[i-1]), toVector(line[i])
+		arc := arcInterpolator(a, b)
+		// even count of samples keeps the midpoint of the arc
+		n := 2 * int(math.Ceil(sphere.Angle(a, b)/step/2))
+		prevF, prev := 0., out[len(out)-1].cell
+		for k := 1; k <= n; k++ {
+			f := float64(k) / float64(n)
+			cell := line[i].Cell(res)
+			if k != n {
+				cell = arc(f).Cell(res)
+			}
+			out = traceArc(out, arc, res, i-1, prevF, prev, f, cell, 0)
+			prevF, prev = f, cell
+		}
+	}
+	return out
+}
+
+// traceArc appends cells of the arc between fractions f1 and f2 with cells c1 and c2,
+// c1 must be already appended. Cells between not neighbor cells are walked along the grid line,
+// the arc is bisected if the walk fails near pentagons.
+func traceArc(out []tracedCell, arc func(float64) h3.LatLng, res, seg int, f1 float64, c1 h3.Cell, f2 float64, c2 h3.Cell, depth int) []tracedCell {
+	if c1 == c2 {
+		return out
+	}
+	if AreNeighbors(c1, c2) {
+		return append(out, tracedCell{cell: c2, seg: seg})
+	}
+	if path, ok := gridLine(c1, c2); ok {
+		for _, c := range path {
+			out = append(out, tracedCell{cell: c, seg: seg})
+		}
+		return out
+	}
+	fm := (f1 + f2) / 2
+	if depth >= maxTraceDepth || fm <= f1 || fm >= f2 {
+		return append(out, tracedCell{cell: c2, seg: seg})
+	}
+	cm := arc(fm).Cell(res)
+	out = traceArc(out, arc, res, seg, f1, c1, fm, cm, depth+1)
+	return traceArc(out, arc, res, seg, fm, cm, f2, c2, depth+1)
+}
+
+// gridLine returns cells of the straight line on the grid from a (excluded) to b as h3 GridPath,
+// the line is walked by neighbors without cgo calls except local coordinates of the cells.
+// Returns false if the walk doesn't reach b, h3 coordinates are distorted near pentagons.
+func gridLine(a, b h3.Cell) ([]h3.Cell, bool) {
+	start, end := cube(h3.CellToLocalIJ(a, a)), cube(h3.CellToLocalIJ(a, b))
+	n := max(abs(end[0]-start[0]), abs(end[1]-start[1]), abs(end[2]-start[2]))
+	if n == 0 {
+		return nil, false
+	}
+	out := make([]h3.Cell, 0, n)
+	cell, rotations, prev := a, 0, start
+	for step := 1; step <= n; step++ {
+		t := float64(step) / float64(n)
+		next := cubeRound(
+			float64(start[0])+float64(end[0]-start[0])*t,
+			float64(start[1])+float64(end[1]-start[1])*t,
+			float64(start[2])+float64(end[2]-start[2])*t,
+		)
+		dir, ok := cubeDirection(prev, next)
+		if !ok {
+			return nil, false
+		}
+		if cell, rotations, ok = neighborRotations(cell, dir, rotations); !ok {
+			return nil, false
+		}
+		out = append(out, cell)
+		prev = next
+	}
+	return out, cell == b
+}
+
+// cube returns cube coordinates of the local ij coordinates.
+func cube(ij h3.CoordIJ) [3]int {
+	return [3]int{-ij.I, ij.J, ij.I - ij.J}
+}
+
+// cubeRound returns the nearest cube coordinates of the cell for the point.
+func cubeRound(i, j, k float64) [3]int {
+	ri, rj, rk := math.Round(i), math.Round(j), math.Round(k)
+	di, dj, dk := math.Abs(ri-i), math.Abs(rj-j), math.Abs(rk-k)
+	switch {
+	case di > dj && di > dk:
+		ri = -rj - rk
+	case dj > dk:
+		rj = -ri - rk
+	default:
+		rk = -ri - rj
+	}
+	return [3]int{int(ri), int(rj), int(rk)}
+}
+
+// cubeDirection returns digit of the direction between neighbor cube coordinates.
+// Unit vectors of ijk coordinates with components 0 and 1 are binary digits of the direction.
+func cubeDirection(from, to [3]int) (int, bool) {
+	// cube to ijk coordinates with k = 0
+	i, j := from[0]-to[0], to[1]-from[1]
+	m := min(i, j, 0)
+	i, j, k := i-m, j-m, -m
+	if i > 1 || j > 1 || k > 1 || i+j+k == 0 {
+		return 0, false
+	}
+	return i<<2 | j<<1 | k, true
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func toVector(ll h3.LatLng) sphere.Vector {
+	return sphere.FromPoint(orb.Point{ll.Lng, ll.Lat})
+}
+
+// arcInterpolator returns function that interpolates point on the great-circle arc from a to b.
+func arcInterpolator(a, b sphere.Vector) func(f float64) h3.LatLng {
+	arc := sphere.ArcInterpolator(a, b)
+	return func(f float64) h3.LatLng {
+		p := arc(f).Point()
+		return h3.LatLng{Lat: p.Lat(), Lng: p.Lon()}
+	}
+}
+
+// cellSegmentDistance returns distance in meters between the cell boundary and the line segment.
+// The distance between not crossing segments is reached at an end of one of them.
+func cellSegmentDistance(boundary h3.CellBoundary, a, b orb.Point) float64 {
+	dist := math.Inf(1)
+	for i, v := range boundary {
+		p := orb.Point{v.Lng, v.Lat}
+		dist = min(dist, pointSegmentDistance(p, a, b))
+		next := boundary[(i+1)%len(boundary)]
+		q := orb.Point{next.Lng, next.Lat}
+		dist = min(dist, pointSegmentDistance(a, p, q), pointSegmentDistance(b, p, q))
+	}
+	return dist
+}
+
+// pointSegmentDistance returns distance in meters between point and great-circle arc of the segment.
+func pointSegmentDistance(p, a, b orb.Point) float64 {
+	vp := sphere.FromPoint(p)
+	c, _ := sphere.ArcClosestPoint(sphere.FromPoint(a), sphere.FromPoint(b), vp)
+	return sphere.Angle(vp, c) * earthRadiusM
+}
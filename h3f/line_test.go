@@ -0,0 +1,161 @@
+package h3f
+
+import (
+	"testing"
+
+	"github.com/VGSML/geobin/internal/fixture"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"github.com/uber/h3-go/v4"
+	"golang.org/x/exp/slices"
+)
+
+func TestLineCells(t *testing.T) {
+	lines := append(fixture.LineString(), fixture.PacificLanes()...)
+	lines = append(lines,
+		// passes pentagon of base cell 4
+		orb.LineString{{0, 60}, {10.5364, 64.7000}, {25, 70}},
+		// passes pentagon of base cell 117
+		orb.LineString{{-60, -40}, {-33.9785, -49.8873}, {-10, -60}},
+		// long meridian segment across the pole
+		orb.LineString{{30, 80}, {-150, 80}},
+	)
+	for _, res := range []int{2, 4, 6} {
+		for n, line := range lines {
+			cells := LineCells(line, res)
+			if len(cells) == 0 {
+				t.Fatalf("res %d line %d: expected cells", res, n)
+			}
+			if first := h3.NewLatLng(line[0].Lat(), line[0].Lon()).Cell(res); cells[0] != first {
+				t.Errorf("res %d line %d: expected first cell %v, got %v", res, n, first, cells[0])
+			}
+			last := line[len(line)-1]
+			if c := h3.NewLatLng(last.Lat(), last.Lon()).Cell(res); cells[len(cells)-1] != c {
+				t.Errorf("res %d line %d: expected last cell %v, got %v", res, n, c, cells[len(cells)-1])
+			}
+			for i := 1; i < len(cells); i++ {
+				if cells[i-1] != cells[i] && !cells[i-1].IsNeighbor(cells[i]) {
+					t.Fatalf("res %d line %d: gap between %v and %v", res, n, cells[i-1], cells[i])
+				}
+			}
+			// great-circle midpoints of segments are covered
+			set := map[h3.Cell]struct{}{}
+			for _, c := range cells {
+				set[c] = struct{}{}
+			}
+			for i := 1; i < len(line); i++ {
+				mid := geo.Midpoint(line[i-1], line[i])
+				if _, ok := set[h3.NewLatLng(mid.Lat(), mid.Lon()).Cell(res)]; !ok {
+					t.Errorf("res %d line %d: great-circle midpoint %v of segment %d is not covered", res, n, mid, i)
+				}
+			}
+		}
+	}
+}
+
+func TestLineCellsTolerance(t *testing.T) {
+	line := fixture.LineString()[2]
+	res := 7
+	path := LineCells(line, res)
+	prev := 0
+	for _, tolerance := range []float64{0, 500, 2000, 5000} {
+		cells := LineCellsTolerance(line, res, tolerance)
+		set := map[h3.Cell]struct{}{}
+		for _, c := range cells {
+			set[c] = struct{}{}
+		}
+		if len(set) != len(cells) && tolerance > 0 {
+			t.Errorf("tolerance %v: expected unique cells", tolerance)
+		}
+		for _, c := range path {
+			if _, ok := set[c]; !ok {
+				t.Fatalf("tolerance %v: expected path cell %v", tolerance, c)
+			}
+		}
+		if tolerance > 0 && len(set) <= prev {
+			t.Errorf("tolerance %v: expected more cells than %d, got %d", tolerance, prev, len(set))
+		}
+		prev = len(set)
+		// centers of extra cells are not farther than tolerance and cell size
+		maxDist := tolerance + 2*h3.HexagonEdgeLengthAvgM(res)
+		for c := range set {
+			ll := c.LatLng()
+			p := orb.Point{ll.Lng, ll.Lat}
+			dist := maxDist + 1
+			for i := 1; i < len(line); i++ {
+				dist = min(dist, pointSegmentDistance(p, line[i-1], line[i]))
+			}
+			if dist > maxDist {
+				t.Errorf("tolerance %v: cell %v is too far from line: %v", tolerance, c, dist)
+			}
+		}
+	}
+}
+
+func TestLineCellsTolerancePoint(t *testing.T) {
+	p := orb.Point{13.4, 52.5}
+	res := 9
+	cells := LineCellsTolerance(orb.LineString{p}, res, 500)
+	if len(cells) < 7 || cells[0] != h3.NewLatLng(p.Lat(), p.Lon()).Cell(res) {
+		t.Errorf("expected cell of the point and cells around it, got %v", cells)
+	}
+	if cells := LineCellsTolerance(nil, res, 500); len(cells) != 0 {
+		t.Errorf("expected no cells for empty line, got %v", cells)
+	}
+}
+
+func TestTraceArcGap(t *testing.T) {
+	res := 7
+	a, b := h3.NewLatLng(52.5, 13.4), h3.NewLatLng(52.5, 13.6)
+	c1, c2 := a.Cell(res), b.Cell(res)
+	// arc jumps between cells, so the gap is filled after bisection limit
+	arc := func(f float64) h3.LatLng {
+		if f < 0.5 {
+			return a
+		}
+		return b
+	}
+	path := traceArc([]tracedCell{{cell: c1}}, arc, res, 0, 0, c1, 1, c2, 0)
+	if path[len(path)-1].cell != c2 {
+		t.Fatalf("expected path to the cell %v, got %v", c2, path)
+	}
+	for i := 1; i < len(path); i++ {
+		if !path[i-1].cell.IsNeighbor(path[i].cell) {
+			t.Errorf("cells %v and %v of the path are not neighbors", path[i-1].cell, path[i].cell)
+		}
+	}
+}
+
+func TestGridLineParity(t *testing.T) {
+	for _, cell := range testCells() {
+		for _, c := range cell.GridDisk(4) {
+			if c == cell || cell.IsNeighbor(c) {
+				continue
+			}
+			want, ok := h3GridPath(cell, c)
+			if !ok {
+				continue
+			}
+			got, ok := gridLine(cell, c)
+			if !ok || !slices.Equal(got, want[1:]) {
+				t.Errorf("gridLine(%v, %v) = %v, %v, want %v", cell, c, got, ok, want[1:])
+			}
+		}
+	}
+}
+
+// h3GridPath returns h3 grid path, returns false if h3 fails to build it.
+func h3GridPath(a, b h3.Cell) (cells []h3.Cell, ok bool) {
+	defer func() {
+		if recover() != nil {
+			cells, ok = nil, false
+		}
+	}()
+	cells = h3.GridPath(a, b)
+	for _, c := range cells {
+		if !c.IsValid() {
+			return nil, false
+		}
+	}
+	return cells, len(cells) != 0
+}
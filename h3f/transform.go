@@ -1,19 +1,39 @@
 package h3f
 
 import (
-	"github.com/VGSML/geo-index/geo"
+	"github.com/VGSML/geobin/orbf"
 	"github.com/paulmach/orb"
 	"github.com/uber/h3-go/v4"
+	"golang.org/x/exp/slices"
+)
+
+// Containment defines which cells are returned for polygons.
+type Containment int
+
+const (
+	// ContainmentOverlap returns cells that have common points with polygon:
+	// cells along the polygon boundary and cells with centers inside polygon.
+	ContainmentOverlap Containment = iota
+	// ContainmentCentroid returns cells with centers inside polygon.
+	ContainmentCentroid
+	// ContainmentFull returns cells that are fully contained in polygon.
+	ContainmentFull
 )
 
 // GeometryCells returns cells inside given geometry.
+// Polygons are covered by all overlapping cells.
+// Geometry that crosses antimeridian is split to parts before.
 func GeometryCells(geom orb.Geometry, res int, compact bool) []h3.Cell {
-	switch g := geom.(type) {
+	return GeometryCellsContainment(geom, res, compact, ContainmentOverlap)
+}
+
+// GeometryCellsContainment returns cells inside given geometry,
+// polygons are converted to cells using given containment mode.
+// Geometry that crosses antimeridian is split to parts before.
+func GeometryCellsContainment(geom orb.Geometry, res int, compact bool, mode Containment) []h3.Cell {
+	switch g := orbf.SplitAntimeridian(geom).(type) {
 	case orb.Bound:
-		poly := h3.GeoPolygon{
-			GeoLoop: pointsToLatLngArray(g.ToRing()),
-		}
-		return poly.Cells(res)
+		return GeometryCellsContainment(g.ToPolygon(), res, compact, mode)
 	case orb.Point:
 		return []h3.Cell{h3.NewLatLng(g.Lat(), g.Lon()).Cell(res)}
 	case orb.MultiPoint:
@@ -45,32 +65,22 @@ func GeometryCells(geom orb.Geometry, res int, compact bool) []h3.Cell {
 			res,
 		)
 	case orb.Polygon:
-		p := h3.GeoPolygon{}
-		for i, r := range g {
-			l := pointsToLatLngArray(r)
-			if i == 0 {
-				p.GeoLoop = l
-				continue
+		cells := polygonContainmentCells(g, res, mode)
+		if mode == ContainmentCentroid && compact {
+			for len(cells) == 0 && res < 14 {
+				res++
+				cells = polygonContainmentCells(g, res, mode)
 			}
-			p.Holes = append(p.Holes, l)
-		}
-		if !compact {
-			return p.Cells(res)
 		}
-		cells := p.Cells(res)
-		for len(cells) == 0 && res < 14 {
-			res++
-			cells = p.Cells(res)
-		}
-		if len(cells) < 100 {
+		if !compact || len(cells) < 100 {
 			return cells
 		}
-		return h3.CompactCells(cells)
+		return Compact(cells)
 	case orb.MultiPolygon:
 		var out []h3.Cell
 		for _, p := range g {
 			out = append(out,
-				GeometryCells(p, res, compact)...,
+				GeometryCellsContainment(p, res, compact, mode)...,
 			)
 		}
 		return out
@@ -78,7 +88,7 @@ func GeometryCells(geom orb.Geometry, res int, compact bool) []h3.Cell {
 		var out []h3.Cell
 		for _, g := range g {
 			out = append(out,
-				GeometryCells(g, res, compact)...,
+				GeometryCellsContainment(g, res, compact, mode)...,
 			)
 		}
 		return out
@@ -86,6 +96,60 @@ func GeometryCells(geom orb.Geometry, res int, compact bool) []h3.Cell {
 	return nil
 }
 
+// polygonContainmentCells returns unique cells of polygon for given containment mode.
+// Boundary cells are traced along the polygon rings by lineCells.
+func polygonContainmentCells(poly orb.Polygon, res int, mode Containment) []h3.Cell {
+	if len(poly) == 0 {
+		return nil
+	}
+	p := h3.GeoPolygon{}
+	for i, r := range poly {
+		l := pointsToLatLngArray(r)
+		if i == 0 {
+			p.GeoLoop = l
+			continue
+		}
+		p.Holes = append(p.Holes, l)
+	}
+	interior := polygonCells(p, res)
+	if mode == ContainmentCentroid {
+		return interior
+	}
+	boundary := map[h3.Cell]struct{}{}
+	for _, r := range poly {
+		for _, cell := range lineCells(pointsToLatLngArray(r), res) {
+			boundary[cell] = struct{}{}
+		}
+	}
+	out := make([]h3.Cell, 0, len(interior)+len(boundary))
+	if mode == ContainmentFull {
+		// boundary can cross only cells along the traced boundary and their neighbors,
+		// these cells are checked by their boundaries
+		near := make(map[h3.Cell]struct{}, len(boundary)*7)
+		for cell := range boundary {
+			for _, n := range GridDisk(cell, 1) {
+				near[n] = struct{}{}
+			}
+		}
+		for _, cell := range interior {
+			if _, ok := near[cell]; !ok || orbf.Contains(poly, cellPolygon(cell)) {
+				out = append(out, cell)
+			}
+		}
+		return out
+	}
+	for _, cell := range interior {
+		if _, ok := boundary[cell]; !ok {
+			out = append(out, cell)
+		}
+	}
+	for cell := range boundary {
+		out = append(out, cell)
+	}
+	slices.Sort(out)
+	return out
+}
+
 // GeometryPointCells returns cells corresponded for points of the given geometry.
 func GeometryPointCells(geom orb.Geometry, res int) []h3.Cell {
 	switch g := geom.(type) {
@@ -154,49 +218,3 @@ func pointsToCellsArray(pp []orb.Point, res int) []h3.Cell {
 	}
 	return l
 }
-
-// lineCells returns grid path for a long line.
-func lineCells(line []h3.LatLng, res int) []h3.Cell {
-	if len(line) == 0 {
-		return nil
-	}
-	var out []h3.Cell
-	first := line[0].Cell(res)
-	for i := 1; i < len(line); i++ {
-		next := line[i].Cell(res)
-		if next == first { // skip equal point
-			continue
-		}
-		if h3.GridDistance(first, next) == 0 {
-			// distance too much split distance using planar geometry
-			l := orb.LineString{
-				{line[i-1].Lng, line[i-1].Lat},
-				{line[i].Lng, line[i].Lat},
-			}
-			frac := 0.5
-			splitPoint := geo.LineInterpolatePoint(l, 0.5)
-			splitCell := h3.NewLatLng(splitPoint.Lat(), splitPoint.Lon()).Cell(res)
-			for frac > 0.001 {
-				if h3.GridDistance(first, splitCell) == 0 {
-					frac /= 2
-					splitPoint = geo.LineInterpolatePoint(l, frac)
-					splitCell = h3.NewLatLng(splitPoint.Lat(), splitPoint.Lon()).Cell(res)
-					continue
-				}
-				// add cells from split line
-				for ff := frac; ff < 1; ff += frac {
-					path := h3.GridPath(first, splitCell)
-					out = append(out, path[:len(path)-1]...)
-					first = splitCell
-					splitPoint = geo.LineInterpolatePoint(l, ff+frac)
-					splitCell = h3.NewLatLng(splitPoint.Lat(), splitPoint.Lon()).Cell(res)
-				}
-				break
-			}
-		}
-		path := h3.GridPath(first, next)
-		out = append(out, path[:len(path)-1]...)
-		first = next
-	}
-	return append(out, line[len(line)-1].Cell(res))
-}
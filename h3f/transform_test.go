@@ -0,0 +1,139 @@
+package h3f
+
+import (
+	"testing"
+
+	"github.com/VGSML/geobin/internal/fixture"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+	"github.com/uber/h3-go/v4"
+)
+
+func TestGeometryCellsAntimeridian(t *testing.T) {
+	tests := []struct {
+		name   string
+		geom   orb.Geometry
+		res    int
+		inside []orb.Point
+		// cell centers must not be between maxLon and minLon
+		minLon float64
+		maxLon float64
+	}{
+		{
+			name:   "chukotka",
+			geom:   fixture.Chukotka(),
+			res:    3,
+			inside: []orb.Point{{179.9, 66}, {-179.9, 66}, {170, 65}},
+			minLon: 155,
+			maxLon: -165,
+		},
+		{
+			name:   "pacific lane",
+			geom:   fixture.PacificLanes()[0],
+			res:    3,
+			inside: []orb.Point{{139.7, 35.45}, {-170, 50}, {-122.4194, 37.7749}},
+			minLon: 135,
+			maxLon: -125,
+		},
+		{
+			name:   "southern lane",
+			geom:   fixture.PacificLanes()[1],
+			res:    3,
+			inside: []orb.Point{{174.7762, -36.8485}, {-178.4419, -18.1416}},
+			minLon: 170,
+			maxLon: -160,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, cells := range map[string][]h3.Cell{
+				"GeometryCells": GeometryCells(tt.geom, tt.res, false),
+				"CoverCells":    CoverCells(tt.geom, tt.res),
+			} {
+				if len(cells) == 0 {
+					t.Fatalf("%s: expected cells", name)
+				}
+				set := map[h3.Cell]struct{}{}
+				for _, cell := range cells {
+					set[cell] = struct{}{}
+					c := cell.LatLng()
+					if c.Lng < tt.minLon-5 && c.Lng > tt.maxLon+5 {
+						t.Errorf("%s: cell %v center %v is on the other side of the Earth", name, cell, c)
+					}
+				}
+				for _, p := range tt.inside {
+					if _, ok := set[h3.NewLatLng(p.Lat(), p.Lon()).Cell(tt.res)]; !ok {
+						t.Errorf("%s: expected cell of point %v", name, p)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGeometryCellsContainment(t *testing.T) {
+	square := orb.Polygon{{{13.0, 52.0}, {14.0, 52.0}, {14.0, 53.0}, {13.0, 53.0}, {13.0, 52.0}}}
+	thin := orb.Polygon{{{13.0, 52.0}, {14.0, 52.0}, {14.0, 52.0005}, {13.0, 52.0005}, {13.0, 52.0}}}
+	res := 6
+
+	toSet := func(cells []h3.Cell) map[h3.Cell]struct{} {
+		set := map[h3.Cell]struct{}{}
+		for _, c := range cells {
+			set[c] = struct{}{}
+		}
+		return set
+	}
+	subset := func(a, b map[h3.Cell]struct{}) bool {
+		for c := range a {
+			if _, ok := b[c]; !ok {
+				return false
+			}
+		}
+		return true
+	}
+
+	full := toSet(GeometryCellsContainment(square, res, false, ContainmentFull))
+	centroid := toSet(GeometryCellsContainment(square, res, false, ContainmentCentroid))
+	overlap := toSet(GeometryCellsContainment(square, res, false, ContainmentOverlap))
+	if len(full) == 0 || len(full) >= len(centroid) || len(centroid) >= len(overlap) {
+		t.Fatalf("expected full < centroid < overlap, got %d, %d, %d", len(full), len(centroid), len(overlap))
+	}
+	if !subset(full, centroid) || !subset(centroid, overlap) {
+		t.Errorf("expected full ⊂ centroid ⊂ overlap")
+	}
+	for c := range full {
+		for _, v := range c.Boundary() {
+			if !planar.PolygonContains(square, orb.Point{v.Lng, v.Lat}) {
+				t.Fatalf("cell %v of full containment is not inside polygon", c)
+			}
+		}
+	}
+	// cells near the boundary are kept if they are inside polygon
+	for c := range centroid {
+		inside := true
+		for _, v := range c.Boundary() {
+			inside = inside && planar.PolygonContains(square, orb.Point{v.Lng, v.Lat})
+		}
+		if _, ok := full[c]; inside && !ok {
+			t.Errorf("cell %v inside polygon is not in full containment", c)
+		}
+	}
+	for _, p := range square[0] {
+		if _, ok := overlap[h3.NewLatLng(p.Lat(), p.Lon()).Cell(res)]; !ok {
+			t.Errorf("expected overlap cell of corner %v", p)
+		}
+	}
+	if len(overlap) != len(GeometryCells(square, res, false)) {
+		t.Errorf("expected overlap containment by default")
+	}
+
+	if cells := GeometryCellsContainment(thin, res, false, ContainmentCentroid); len(cells) != 0 {
+		t.Errorf("expected no centroid cells for thin polygon, got %d", len(cells))
+	}
+	if cells := GeometryCellsContainment(thin, res, false, ContainmentFull); len(cells) != 0 {
+		t.Errorf("expected no full cells for thin polygon, got %d", len(cells))
+	}
+	if cells := GeometryCellsContainment(thin, res, false, ContainmentOverlap); len(cells) < 10 {
+		t.Errorf("expected overlap cells along thin polygon, got %d", len(cells))
+	}
+}
@@ -0,0 +1,53 @@
+package fixture
+
+import "github.com/paulmach/orb"
+
+// PacificLanes returns shipping lanes that cross antimeridian.
+func PacificLanes() []orb.LineString {
+	return []orb.LineString{
+		{
+			{139.7000, 35.4500},  // Tokyo Bay, Japan
+			{160.0000, 42.0000},  // North Pacific
+			{179.5000, 48.0000},  // near antimeridian
+			{-170.0000, 50.0000}, // south of Aleutian Islands
+			{-145.0000, 47.0000}, // Gulf of Alaska
+			{-122.4194, 37.7749}, // San Francisco, CA
+		},
+		{
+			{174.7762, -36.8485},  // Auckland, New Zealand
+			{-178.4419, -18.1416}, // Suva, Fiji
+			{-157.8583, 21.3069},  // Honolulu, HI
+		},
+	}
+}
+
+// Chukotka returns rough polygon of Chukotka that crosses antimeridian.
+func Chukotka() orb.Polygon {
+	return orb.Polygon{
+		{
+			{160.0, 62.0},
+			{175.0, 62.0},
+			{-175.0, 64.0},
+			{-169.0, 66.0},
+			{-172.0, 68.0},
+			{-180.0, 70.0},
+			{170.0, 70.0},
+			{160.0, 68.0},
+			{160.0, 62.0},
+		},
+	}
+}
+
+// ArcticCap returns polygon that encircles the North pole.
+func ArcticCap() orb.Polygon {
+	return orb.Polygon{
+		{
+			{-180.0, 80.0},
+			{-90.0, 80.0},
+			{0.0, 80.0},
+			{90.0, 80.0},
+			{180.0, 80.0},
+			{-180.0, 80.0},
+		},
+	}
+}
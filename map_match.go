@@ -0,0 +1,433 @@
+package geobin
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/VGSML/geobin/orbf"
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"github.com/paulmach/orb/project"
+)
+
+// maxRouteFactor limits the route between candidates of consecutive trace points
+// by the distance between points and search radius.
+const maxRouteFactor = 3
+
+// TracePoint is a timestamped GPS point of the trace in WGS84.
+type TracePoint struct {
+	Point orb.Point
+	Time  time.Time
+}
+
+// MatchOptions configures the map matcher.
+type MatchOptions struct {
+	// SearchRadius is the distance in meters to find candidate roads of the trace point, default 50.
+	SearchRadius float64
+	// Sigma is the standard deviation in meters of GPS error, default 10.
+	Sigma float64
+	// Beta is the scale in meters of the difference between route and great-circle distances
+	// of consecutive points, default 10.
+	Beta float64
+	// MaxCandidates limits candidates of the trace point by the nearest roads, default 8.
+	MaxCandidates int
+	// MaxSpeed in meters per second excludes routes that are too long for the time between points,
+	// zero means unlimited.
+	MaxSpeed float64
+}
+
+// MatchedPoint is the trace point matched to the road.
+type MatchedPoint struct {
+	// Road is the index item of the road, -1 if the point is not matched.
+	Road int
+	// Fraction is the location of the point on the road as orbf.LineLocatePoint.
+	Fraction float64
+	// Offset is the signed distance in meters from the road to the trace point,
+	// positive on the right of the road direction.
+	Offset float64
+	// Point is the matched point on the road.
+	Point orb.Point
+}
+
+// MatchResult is the result of the trace matching.
+type MatchResult struct {
+	Points []MatchedPoint
+	// Path is the route along roads between matched points, the path is broken
+	// where consecutive points can't be connected.
+	Path orb.MultiLineString
+}
+
+// Matcher matches GPS traces to the road network of the index by hidden Markov model.
+// Roads are LineString items of the index, roads are connected by their end points.
+type Matcher struct {
+	index *Index
+	opts  MatchOptions
+	roads map[int]*matchRoad
+	nodes map[orb.Point][]int
+}
+
+type matchRoad struct {
+	line orb.LineString
+	// measured has fractions of the line length as measures
+	measured   planar.MeasuredLine
+	length     float64
+	start, end orb.Point
+}
+
+// NewMatcher creates map matcher for roads of the index, road topology is built from end points of LineString items.
+// Returns ErrNoGeometry if the index items don't store geometry.
+func NewMatcher(ctx context.Context, index *Index, opts MatchOptions) (*Matcher, error) {
+	if opts.SearchRadius <= 0 {
+		opts.SearchRadius = 50
+	}
+	if opts.Sigma <= 0 {
+		opts.Sigma = 10
+	}
+	if opts.Beta <= 0 {
+		opts.Beta = 10
+	}
+	if opts.MaxCandidates <= 0 {
+		opts.MaxCandidates = 8
+	}
+	m := &Matcher{
+		index: index,
+		opts:  opts,
+		roads: map[int]*matchRoad{},
+		nodes: map[orb.Point][]int{},
+	}
+	for idx, item := range index.items {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if _, ok := item.(Geometry); !ok {
+			return nil, ErrNoGeometry
+		}
+		line, ok := index.itemGeometryWGS84(idx).(orb.LineString)
+		if !ok || len(line) < 2 {
+			continue
+		}
+		r := &matchRoad{
+			line:     line,
+			measured: orbf.NewMeasuredLine(line, 0, 1),
+			length:   orbf.LineLength(line),
+			start:    nodeKey(line[0]),
+			end:      nodeKey(line[len(line)-1]),
+		}
+		m.roads[idx] = r
+		m.nodes[r.start] = append(m.nodes[r.start], idx)
+		if r.end != r.start {
+			m.nodes[r.end] = append(m.nodes[r.end], idx)
+		}
+	}
+	return m, nil
+}
+
+// nodeKey returns the end point of the road rounded to about 1cm, so nearly equal end points are connected.
+func nodeKey(p orb.Point) orb.Point {
+	return orb.Point{math.Round(p[0]*1e7) / 1e7, math.Round(p[1]*1e7) / 1e7}
+}
+
+// matchCandidate is the hidden state of the trace point.
+type matchCandidate struct {
+	road   int
+	frac   float64
+	offset float64
+	// along is the distance in meters from the road start
+	along float64
+	score float64
+	prev  int
+}
+
+// Match matches trace points to roads by Viterbi algorithm.
+// Emission probability of the candidate is Gaussian by the distance to the road,
+// transition probability is exponential by the difference between route distance along roads
+// and great-circle distance of consecutive points. Points without candidates are not matched
+// and break the path as well as points that can't be reached from the previous ones.
+func (m *Matcher) Match(ctx context.Context, trace []TracePoint) (MatchResult, error) {
+	res := MatchResult{Points: make([]MatchedPoint, len(trace))}
+	steps := make([][]matchCandidate, len(trace))
+	for t, tp := range trace {
+		if err := ctx.Err(); err != nil {
+			return MatchResult{}, err
+		}
+		steps[t] = m.candidates(ctx, tp.Point)
+		for j := range steps[t] {
+			steps[t][j].prev = -1
+		}
+		if t == 0 || len(steps[t-1]) == 0 {
+			continue
+		}
+		gc := geo.DistanceHaversine(trace[t-1].Point, tp.Point)
+		limit := maxRouteFactor * (gc + 2*m.opts.SearchRadius)
+		dt := tp.Time.Sub(trace[t-1].Time).Seconds()
+		emissions := make([]float64, len(steps[t]))
+		for j, c := range steps[t] {
+			emissions[j] = c.score
+			steps[t][j].score = math.Inf(-1)
+		}
+		for i, from := range steps[t-1] {
+			dist := m.routeDistances(from, limit)
+			for j, to := range steps[t] {
+				route := m.routeDistance(from, to, dist)
+				if math.IsInf(route, 1) || m.opts.MaxSpeed > 0 && dt > 0 && route/dt > m.opts.MaxSpeed {
+					continue
+				}
+				score := from.score + emissions[j] - math.Abs(route-gc)/m.opts.Beta
+				if score > to.score {
+					steps[t][j].score, steps[t][j].prev = score, i
+				}
+			}
+		}
+		broken := true
+		for _, c := range steps[t] {
+			if c.prev >= 0 {
+				broken = false
+			}
+		}
+		if broken {
+			// the chain is broken, the point starts new chain
+			for j := range steps[t] {
+				steps[t][j].score = emissions[j]
+			}
+			continue
+		}
+		// candidates that can't be reached are excluded
+		for j := range steps[t] {
+			if steps[t][j].prev < 0 {
+				steps[t][j].score = math.Inf(-1)
+			}
+		}
+	}
+
+	// backtracking from the end of each chain
+	chosen := make([]int, len(trace))
+	for t := len(trace) - 1; t >= 0; t-- {
+		if len(steps[t]) == 0 {
+			chosen[t] = -1
+			continue
+		}
+		if t+1 < len(trace) && chosen[t+1] >= 0 && steps[t+1][chosen[t+1]].prev >= 0 {
+			chosen[t] = steps[t+1][chosen[t+1]].prev
+			continue
+		}
+		best := 0
+		for j, c := range steps[t] {
+			if c.score > steps[t][best].score {
+				best = j
+			}
+		}
+		chosen[t] = best
+	}
+
+	var path orb.LineString
+	for t := range trace {
+		if chosen[t] < 0 {
+			res.Points[t] = MatchedPoint{Road: -1}
+			res.Path = appendPath(res.Path, path)
+			path = nil
+			continue
+		}
+		c := steps[t][chosen[t]]
+		// the point is located as ends of the path parts by orbf.LineSubstring
+		p, _ := orbf.LocateAlong(m.roads[c.road].measured, c.frac, 0)
+		res.Points[t] = MatchedPoint{Road: c.road, Fraction: c.frac, Offset: c.offset, Point: p}
+		if c.prev < 0 {
+			res.Path = appendPath(res.Path, path)
+			path = orb.LineString{res.Points[t].Point}
+			continue
+		}
+		from := steps[t-1][c.prev]
+		gc := geo.DistanceHaversine(trace[t-1].Point, trace[t].Point)
+		path = append(path, m.routePath(from, c, maxRouteFactor*(gc+2*m.opts.SearchRadius))...)
+	}
+	res.Path = appendPath(res.Path, path)
+	return res, nil
+}
+
+// candidates returns the nearest roads within search radius of the point with emission scores.
+func (m *Matcher) candidates(ctx context.Context, p orb.Point) []matchCandidate {
+	in := orb.Geometry(p)
+	if m.index.proj == Mercator {
+		in = project.Point(p, project.WGS84.ToMercator)
+	}
+	var out []matchCandidate
+	for _, idx := range m.index.WithinDistance(ctx, in, m.opts.SearchRadius) {
+		r, ok := m.roads[idx]
+		if !ok {
+			continue
+		}
+		frac, offset := orbf.LineLocatePoint(r.line, p)
+		if math.Abs(offset) > m.opts.SearchRadius {
+			continue
+		}
+		d := offset / m.opts.Sigma
+		out = append(out, matchCandidate{
+			road:   idx,
+			frac:   frac,
+			offset: offset,
+			along:  frac * r.length,
+			score:  -0.5 * d * d,
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if math.Abs(out[i].offset) != math.Abs(out[j].offset) {
+			return math.Abs(out[i].offset) < math.Abs(out[j].offset)
+		}
+		return out[i].road < out[j].road
+	})
+	if len(out) > m.opts.MaxCandidates {
+		out = out[:m.opts.MaxCandidates]
+	}
+	return out
+}
+
+// routeStep is the road network node reached by the shortest route.
+type routeStep struct {
+	dist float64
+	// road that leads to the node, -1 for the nodes of the start road
+	road int
+	prev orb.Point
+}
+
+// routeDistances returns shortest routes from the candidate to the road network nodes not farther than limit.
+func (m *Matcher) routeDistances(from matchCandidate, limit float64) map[orb.Point]routeStep {
+	r := m.roads[from.road]
+	dist := map[orb.Point]routeStep{}
+	h := &nodeHeap{}
+	relax := func(node orb.Point, step routeStep) {
+		if step.dist > limit {
+			return
+		}
+		if cur, ok := dist[node]; ok && cur.dist <= step.dist {
+			return
+		}
+		dist[node] = step
+		heap.Push(h, nodeDist{node: node, dist: step.dist})
+	}
+	relax(r.start, routeStep{dist: from.along, road: -1})
+	relax(r.end, routeStep{dist: r.length - from.along, road: -1})
+	for h.Len() > 0 {
+		nd := heap.Pop(h).(nodeDist)
+		if nd.dist > dist[nd.node].dist {
+			continue
+		}
+		for _, idx := range m.nodes[nd.node] {
+			road := m.roads[idx]
+			next := road.end
+			if next == nd.node {
+				next = road.start
+			}
+			relax(next, routeStep{dist: nd.dist + road.length, road: idx, prev: nd.node})
+		}
+	}
+	return dist
+}
+
+// routeDistance returns the length of the shortest route between candidates,
+// dist are shortest routes from the first candidate.
+func (m *Matcher) routeDistance(from, to matchCandidate, dist map[orb.Point]routeStep) float64 {
+	route := math.Inf(1)
+	if from.road == to.road {
+		route = math.Abs(to.along - from.along)
+	}
+	r := m.roads[to.road]
+	if s, ok := dist[r.start]; ok {
+		route = min(route, s.dist+to.along)
+	}
+	if s, ok := dist[r.end]; ok {
+		route = min(route, s.dist+r.length-to.along)
+	}
+	return route
+}
+
+// routePath returns points of the shortest route between candidates without the first point.
+func (m *Matcher) routePath(from, to matchCandidate, limit float64) orb.LineString {
+	dist := m.routeDistances(from, limit)
+	route := m.routeDistance(from, to, dist)
+	r := m.roads[to.road]
+	var last orb.LineString
+	var node orb.Point
+	switch {
+	case from.road == to.road && math.Abs(to.along-from.along) == route:
+		return withoutFirst(orbf.LineSubstring(r.line, from.frac, to.frac))
+	case reachedAt(dist, r.start, route-to.along):
+		node, last = r.start, orbf.LineSubstring(r.line, 0, to.frac)
+	default:
+		node, last = r.end, orbf.LineSubstring(r.line, 1, to.frac)
+	}
+	// roads of the route from the last node back to the start road
+	var parts []orb.LineString
+	for {
+		step := dist[node]
+		if step.road < 0 {
+			break
+		}
+		road := m.roads[step.road]
+		if road.start == node {
+			parts = append(parts, orbf.LineSubstring(road.line, 1, 0))
+		} else {
+			parts = append(parts, road.line)
+		}
+		node = step.prev
+	}
+	start := m.roads[from.road]
+	first := orbf.LineSubstring(start.line, from.frac, 0)
+	if node == start.end && (node != start.start || !reachedAt(dist, node, from.along)) {
+		first = orbf.LineSubstring(start.line, from.frac, 1)
+	}
+	out := withoutFirst(first)
+	for n := len(parts) - 1; n >= 0; n-- {
+		out = append(out, withoutFirst(parts[n])...)
+	}
+	return append(out, withoutFirst(last)...)
+}
+
+// withoutFirst returns points of the line without the first one, nil for the empty line.
+func withoutFirst(line orb.LineString) orb.LineString {
+	if len(line) == 0 {
+		return nil
+	}
+	return line[1:]
+}
+
+// reachedAt checks the route to the node has the distance.
+func reachedAt(dist map[orb.Point]routeStep, node orb.Point, d float64) bool {
+	s, ok := dist[node]
+	return ok && s.dist == d
+}
+
+// appendPath appends the path with at least two points to the multiline.
+func appendPath(ml orb.MultiLineString, path orb.LineString) orb.MultiLineString {
+	out := path[:0:0]
+	for _, p := range path {
+		if len(out) == 0 || out[len(out)-1] != p {
+			out = append(out, p)
+		}
+	}
+	if len(out) < 2 {
+		return ml
+	}
+	return append(ml, out)
+}
+
+type nodeDist struct {
+	node orb.Point
+	dist float64
+}
+
+type nodeHeap []nodeDist
+
+func (h nodeHeap) Len() int           { return len(h) }
+func (h nodeHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+func (h nodeHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *nodeHeap) Push(x any)        { *h = append(*h, x.(nodeDist)) }
+func (h *nodeHeap) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
@@ -0,0 +1,197 @@
+package geobin
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/VGSML/geobin/orbf"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+// matchRoads returns road network: roads 0 and 1 go east and road 2 goes north from their junction,
+// road 3 isn't connected to others.
+func matchRoads() []orb.LineString {
+	return []orb.LineString{
+		{{10, 50}, {10.002, 50}},
+		{{10.002, 50}, {10.004, 50}},
+		{{10.002, 50}, {10.002, 50.002}},
+		{{10.010, 50}, {10.012, 50}},
+	}
+}
+
+func newMatchIndex(t *testing.T, proj Projection) *Index {
+	t.Helper()
+	options := []IndexOptions{WithMaxResolution(9)}
+	if proj == Mercator {
+		options = append(options, WithMercatorProjection())
+	}
+	index := NewIndex(options...)
+	for idx, road := range matchRoads() {
+		geom := orb.Geometry(road)
+		if proj == Mercator {
+			geom = project.Geometry(orb.Clone(road), project.WGS84.ToMercator)
+		}
+		index.Insert(idx, geom)
+	}
+	return index
+}
+
+func matchTrace(points ...orb.Point) []TracePoint {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := make([]TracePoint, len(points))
+	for n, p := range points {
+		out[n] = TracePoint{Point: p, Time: start.Add(time.Duration(n) * 10 * time.Second)}
+	}
+	return out
+}
+
+func matchedRoads(res MatchResult) []int {
+	out := make([]int, len(res.Points))
+	for n, p := range res.Points {
+		out[n] = p.Road
+	}
+	return out
+}
+
+func TestMatcher_Match(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  MatchOptions
+		trace []TracePoint
+		roads []int
+		parts int
+	}{
+		{
+			name:  "along roads",
+			trace: matchTrace(orb.Point{10.0005, 50.0001}, orb.Point{10.0015, 50.0001}, orb.Point{10.0025, 49.9999}, orb.Point{10.0035, 50.0001}),
+			roads: []int{0, 0, 1, 1},
+			parts: 1,
+		},
+		{
+			name:  "turn to the junction road",
+			trace: matchTrace(orb.Point{10.0005, 50.0001}, orb.Point{10.0015, 50.0001}, orb.Point{10.0021, 50.0008}, orb.Point{10.0019, 50.0016}),
+			roads: []int{0, 0, 2, 2},
+			parts: 1,
+		},
+		{
+			// the nearest road of the third point is 1, the next point on the road 2 changes the choice
+			name:  "backtracking",
+			opts:  MatchOptions{Sigma: 30},
+			trace: matchTrace(orb.Point{10.001, 50}, orb.Point{10.0025, 50.0002}, orb.Point{10.00205, 50.0012}),
+			roads: []int{0, 2, 2},
+			parts: 1,
+		},
+		{
+			name:  "without backtracking",
+			opts:  MatchOptions{Sigma: 30},
+			trace: matchTrace(orb.Point{10.001, 50}, orb.Point{10.0025, 50.0002}),
+			roads: []int{0, 1},
+			parts: 1,
+		},
+		{
+			name:  "point without roads breaks the path",
+			trace: matchTrace(orb.Point{10.0005, 50.0001}, orb.Point{10.0015, 50.0001}, orb.Point{10.0015, 50.01}, orb.Point{10.0025, 50.0001}, orb.Point{10.0035, 50.0001}),
+			roads: []int{0, 0, -1, 1, 1},
+			parts: 2,
+		},
+		{
+			name:  "not connected roads break the path",
+			trace: matchTrace(orb.Point{10.0005, 50.0001}, orb.Point{10.0015, 50.0001}, orb.Point{10.0105, 50.0001}, orb.Point{10.0115, 50.0001}),
+			roads: []int{0, 0, 3, 3},
+			parts: 2,
+		},
+		{
+			// points are about 70m and 10 seconds apart
+			name:  "under max speed",
+			opts:  MatchOptions{MaxSpeed: 10, SearchRadius: 15},
+			trace: matchTrace(orb.Point{10.0005, 50.0001}, orb.Point{10.0015, 50.0001}, orb.Point{10.0025, 50.0001}),
+			roads: []int{0, 0, 1},
+			parts: 1,
+		},
+		{
+			name:  "over max speed",
+			opts:  MatchOptions{MaxSpeed: 3, SearchRadius: 15},
+			trace: matchTrace(orb.Point{10.0005, 50.0001}, orb.Point{10.0015, 50.0001}, orb.Point{10.0025, 50.0001}),
+			roads: []int{0, 0, 1},
+			parts: 0,
+		},
+	}
+	for _, proj := range []Projection{WGS84, Mercator} {
+		index := newMatchIndex(t, proj)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m, err := NewMatcher(context.Background(), index, tt.opts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				res, err := m.Match(context.Background(), tt.trace)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := matchedRoads(res); !slices.Equal(got, tt.roads) {
+					t.Errorf("projection %v: matched roads %v, want %v", proj, got, tt.roads)
+				}
+				if len(res.Path) != tt.parts {
+					t.Errorf("projection %v: path has %d parts, want %d: %v", proj, len(res.Path), tt.parts, res.Path)
+				}
+				roads := matchRoads()
+				for n, p := range res.Points {
+					if p.Road < 0 {
+						continue
+					}
+					if d := orbf.Distance(p.Point, roads[p.Road]); d > 1e-3 {
+						t.Errorf("projection %v: point %d is %v meters from the road", proj, n, d)
+					}
+				}
+				for _, part := range res.Path {
+					for _, p := range part {
+						if d := orbf.Distance(p, orb.MultiLineString(roads)); d > 1e-3 {
+							t.Errorf("projection %v: path point %v is %v meters from roads", proj, p, d)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestNewMatcher_NoGeometry(t *testing.T) {
+	index := NewIndex(WithIndexedItems(false), WithMaxResolution(9))
+	for idx, road := range matchRoads() {
+		index.Insert(idx, road)
+	}
+	if _, err := NewMatcher(context.Background(), index, MatchOptions{}); err != ErrNoGeometry {
+		t.Errorf("NewMatcher() error = %v, want %v", err, ErrNoGeometry)
+	}
+}
+
+func TestIndex_WithinDistance(t *testing.T) {
+	// the point is about 22m from road 1, 36m from road 2 and 42m from road 0
+	p := orb.Point{10.0025, 50.0002}
+	tests := []struct {
+		meters float64
+		want   []int
+	}{
+		{meters: 10},
+		{meters: 30, want: []int{1}},
+		{meters: 40, want: []int{1, 2}},
+		{meters: 50, want: []int{0, 1, 2}},
+	}
+	for _, proj := range []Projection{WGS84, Mercator} {
+		index := newMatchIndex(t, proj)
+		in := orb.Geometry(p)
+		if proj == Mercator {
+			in = project.Point(p, project.WGS84.ToMercator)
+		}
+		for _, tt := range tests {
+			got := index.WithinDistance(context.Background(), in, tt.meters)
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("projection %v: WithinDistance(%v) got %v, want %v", proj, tt.meters, got, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package orbf
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+// maxMercatorLat is the maximal latitude of Web Mercator projection.
+const maxMercatorLat = 85.05112878
+
+// NormalizeLon returns longitude in range [-180, 180].
+func NormalizeLon(lon float64) float64 {
+	if lon >= -180 && lon <= 180 {
+		return lon
+	}
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
+
+// CrossesAntimeridian checks geometry has segments that cross ±180° longitude,
+// segment crosses antimeridian if its longitude difference greater than 180°.
+func CrossesAntimeridian(geom orb.Geometry) bool {
+	switch g := geom.(type) {
+	case orb.LineString:
+		return pointsCrossAntimeridian(g)
+	case orb.Ring:
+		return pointsCrossAntimeridian(g)
+	case orb.MultiLineString:
+		for _, l := range g {
+			if pointsCrossAntimeridian(l) {
+				return true
+			}
+		}
+	case orb.Polygon:
+		for _, r := range g {
+			if pointsCrossAntimeridian(r) {
+				return true
+			}
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			if CrossesAntimeridian(p) {
+				return true
+			}
+		}
+	case orb.Collection:
+		for _, g := range g {
+			if CrossesAntimeridian(g) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func pointsCrossAntimeridian(pp []orb.Point) bool {
+	for i := 1; i < len(pp); i++ {
+		if math.Abs(pp[i].Lon()-pp[i-1].Lon()) > 180 {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPolar checks geometry has points out of the Web Mercator latitude range.
+func IsPolar(geom orb.Geometry) bool {
+	b := geom.Bound()
+	return b.Max.Lat() > maxMercatorLat || b.Min.Lat() < -maxMercatorLat
+}
+
+// SplitAntimeridian splits geometry to parts at antimeridian and normalizes longitudes.
+// Lines are returned as MultiLineString, polygons as MultiPolygon.
+// Rings that encircle pole are closed through the pole.
+// Geometry that doesn't cross antimeridian is returned as is.
+func SplitAntimeridian(geom orb.Geometry) orb.Geometry {
+	if !CrossesAntimeridian(geom) {
+		return geom
+	}
+	switch g := geom.(type) {
+	case orb.LineString:
+		return splitLine(g)
+	case orb.Ring:
+		return splitLine(orb.LineString(g))
+	case orb.MultiLineString:
+		var out orb.MultiLineString
+		for _, l := range g {
+			out = append(out, splitLine(l)...)
+		}
+		return out
+	case orb.Polygon:
+		return splitPolygon(g)
+	case orb.MultiPolygon:
+		var out orb.MultiPolygon
+		for _, p := range g {
+			out = append(out, splitPolygon(p)...)
+		}
+		return out
+	case orb.Collection:
+		out := make(orb.Collection, 0, len(g))
+		for _, g := range g {
+			out = append(out, SplitAntimeridian(g))
+		}
+		return out
+	}
+	return geom
+}
+
+// unwrap returns points with continuous longitudes, difference between consecutive points is not greater than 180°.
+func unwrap(pp []orb.Point) []orb.Point {
+	out := make([]orb.Point, len(pp))
+	offset := 0.
+	for i, p := range pp {
+		if i > 0 {
+			d := p.Lon() - pp[i-1].Lon()
+			if d > 180 {
+				offset -= 360
+			}
+			if d < -180 {
+				offset += 360
+			}
+		}
+		out[i] = orb.Point{p.Lon() + offset, p.Lat()}
+	}
+	return out
+}
+
+// window returns number of 360° longitude window of x.
+func window(x float64) float64 {
+	return math.Floor((x + 180) / 360)
+}
+
+// splitLine splits line at antimeridian.
+func splitLine(line orb.LineString) orb.MultiLineString {
+	u := unwrap(line)
+	if len(u) == 0 {
+		return nil
+	}
+	shift := window(u[0].Lon()) * 360
+	cur := orb.LineString{{u[0].Lon() - shift, u[0].Lat()}}
+	var out orb.MultiLineString
+	for i := 1; i < len(u); i++ {
+		p, q := u[i-1], u[i]
+		// at most one boundary between points, because difference of longitudes is not greater than 180°
+		boundary := shift + 180
+		if q.Lon() < p.Lon() {
+			boundary = shift - 180
+		}
+		if (p.Lon()-boundary)*(q.Lon()-boundary) < 0 {
+			lat := crossLat(p, q, boundary)
+			cur = append(cur, orb.Point{boundary - shift, lat})
+			out = append(out, cur)
+			if q.Lon() > p.Lon() {
+				shift += 360
+			} else {
+				shift -= 360
+			}
+			cur = orb.LineString{{boundary - shift, lat}}
+		}
+		cur = append(cur, orb.Point{q.Lon() - shift, q.Lat()})
+	}
+	return append(out, cur)
+}
+
+// crossLat returns latitude of segment p-q at longitude lon. Segments in the Web Mercator
+// latitude range are straight in Web Mercator, so the split doesn't bend them.
+func crossLat(p, q orb.Point, lon float64) float64 {
+	t := (lon - p.Lon()) / (q.Lon() - p.Lon())
+	if math.Abs(p.Lat()) > maxMercatorLat || math.Abs(q.Lat()) > maxMercatorLat {
+		return p.Lat() + (q.Lat()-p.Lat())*t
+	}
+	y1 := project.WGS84.ToMercator(p).Y()
+	y2 := project.WGS84.ToMercator(q).Y()
+	return project.Mercator.ToWGS84(orb.Point{0, y1 + (y2-y1)*t}).Lat()
+}
+
+// splitPolygon splits polygon at antimeridian.
+// Polygon that encircles pole is split also at 0° meridian,
+// so no part spans more than 180° of longitude.
+func splitPolygon(poly orb.Polygon) orb.MultiPolygon {
+	if len(poly) == 0 {
+		return nil
+	}
+	rings := make([]orb.Ring, 0, len(poly))
+	step := 360.
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for n, r := range poly {
+		u := orb.Ring(unwrap(r))
+		if len(u) == 0 {
+			continue
+		}
+		if n > 0 && len(rings) != 0 {
+			// shift hole to the longitudes of the shell
+			shift := 360 * math.Round((rings[0].Bound().Center().Lon()-u[0].Lon())/360)
+			for i := range u {
+				u[i][0] += shift
+			}
+		}
+		// ring encircles the pole, close it through the pole
+		if d := u[len(u)-1].Lon() - u[0].Lon(); math.Abs(d) > 180 && n == 0 {
+			pole := 90.
+			if r.Bound().Center().Lat() < 0 {
+				pole = -90
+			}
+			u = append(u,
+				orb.Point{u[len(u)-1].Lon(), pole},
+				orb.Point{u[0].Lon(), pole},
+				u[0],
+			)
+			step = 180
+		}
+		for _, p := range u {
+			minX = min(minX, p.Lon())
+			maxX = max(maxX, p.Lon())
+		}
+		rings = append(rings, u)
+	}
+	var out orb.MultiPolygon
+	for lo := math.Floor((minX+180)/step)*step - 180; lo < maxX; lo += step {
+		shift := window(lo+step/2) * 360
+		var part orb.Polygon
+		for n, r := range rings {
+			clipped := clipRingX(r, lo, lo+step)
+			if len(clipped) < 4 {
+				if n == 0 {
+					break
+				}
+				continue
+			}
+			for i := range clipped {
+				clipped[i][0] -= shift
+			}
+			part = append(part, clipped)
+		}
+		if len(part) != 0 {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// clipRingX clips ring by vertical lines x = minX and x = maxX.
+func clipRingX(r orb.Ring, minX, maxX float64) orb.Ring {
+	r = clipRingHalfPlane(r, minX, 1)
+	return clipRingHalfPlane(r, maxX, -1)
+}
+
+// clipRingHalfPlane clips ring by half plane (x - boundary) * side >= 0 using Sutherland–Hodgman algorithm.
+func clipRingHalfPlane(r orb.Ring, boundary, side float64) orb.Ring {
+	if len(r) == 0 {
+		return nil
+	}
+	inside := func(p orb.Point) bool {
+		return (p.Lon()-boundary)*side >= 0
+	}
+	cross := func(p, q orb.Point) orb.Point {
+		return orb.Point{boundary, crossLat(p, q, boundary)}
+	}
+	var out orb.Ring
+	pp := r
+	if r.Closed() {
+		pp = r[:len(r)-1]
+	}
+	for i, q := range pp {
+		p := pp[(i+len(pp)-1)%len(pp)]
+		switch {
+		case inside(q):
+			if !inside(p) {
+				out = append(out, cross(p, q))
+			}
+			out = append(out, q)
+		case inside(p):
+			out = append(out, cross(p, q))
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return append(out, out[0])
+}
@@ -0,0 +1,247 @@
+package orbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/VGSML/geobin/internal/fixture"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestSplitAntimeridian(t *testing.T) {
+	lanes := fixture.PacificLanes()
+	for i, l := range lanes {
+		if !CrossesAntimeridian(l) {
+			t.Fatalf("lane %d: expected crossing antimeridian", i)
+		}
+		ml, ok := SplitAntimeridian(l).(orb.MultiLineString)
+		if !ok || len(ml) != 2 {
+			t.Fatalf("lane %d: expected 2 parts, got %v", i, ml)
+		}
+		if CrossesAntimeridian(ml) {
+			t.Errorf("lane %d: parts cross antimeridian", i)
+		}
+		end, start := ml[0][len(ml[0])-1], ml[1][0]
+		if math.Abs(end.Lon()) != 180 || math.Abs(start.Lon()) != 180 || end.Lat() != start.Lat() {
+			t.Errorf("lane %d: parts should be joined at antimeridian, got %v and %v", i, end, start)
+		}
+		if len(ml[0])+len(ml[1]) != len(l)+2 {
+			t.Errorf("lane %d: unexpected number of points", i)
+		}
+	}
+
+	chukotka := fixture.Chukotka()
+	mp, ok := SplitAntimeridian(chukotka).(orb.MultiPolygon)
+	if !ok || len(mp) != 2 {
+		t.Fatalf("chukotka: expected 2 parts, got %v", mp)
+	}
+	unwrapped := orb.Ring(unwrap(chukotka[0]))
+	a, b := planar.Area(projectToMercator(mp)), math.Abs(planar.Area(projectToMercator(unwrapped)))
+	if math.Abs(a-b) > 1e-9*b {
+		t.Errorf("chukotka: expected area %v, got %v", b, a)
+	}
+	for _, p := range mp {
+		b := p.Bound()
+		if b.Min.Lon() < -180 || b.Max.Lon() > 180 {
+			t.Errorf("chukotka: part out of range: %v", b)
+		}
+	}
+
+	arctic := SplitAntimeridian(fixture.ArcticCap())
+	mp, ok = arctic.(orb.MultiPolygon)
+	if !ok || len(mp) != 2 {
+		t.Fatalf("arctic: expected 2 parts, got %v", arctic)
+	}
+	for _, p := range mp {
+		b := p.Bound()
+		if b.Max.Lat() != 90 || b.Max.Lon()-b.Min.Lon() > 180 {
+			t.Errorf("arctic: part should reach the pole and span no more than 180°: %v", b)
+		}
+	}
+
+	if g := SplitAntimeridian(orb.LineString{{170, 0}, {175, 1}}); !CrossesAntimeridian(g) {
+		if _, ok := g.(orb.LineString); !ok {
+			t.Errorf("not crossing line should be returned as is, got %T", g)
+		}
+	}
+}
+
+func TestPredicatesAntimeridian(t *testing.T) {
+	chukotka := fixture.Chukotka()
+	arctic := fixture.ArcticCap()
+	lanes := fixture.PacificLanes()
+
+	tests := []struct {
+		name       string
+		a, b       orb.Geometry
+		intersects bool
+		contains   bool
+	}{
+		{
+			name:       "point west of antimeridian",
+			a:          chukotka,
+			b:          orb.Point{179.9, 66},
+			intersects: true,
+			contains:   true,
+		},
+		{
+			name:       "point east of antimeridian",
+			a:          chukotka,
+			b:          orb.Point{-179.9, 66},
+			intersects: true,
+			contains:   true,
+		},
+		{
+			name: "point at the other side of the Earth",
+			a:    chukotka,
+			b:    orb.Point{0, 66},
+		},
+		{
+			name:       "line across antimeridian",
+			a:          chukotka,
+			b:          orb.LineString{{170, 65}, {-175, 65}},
+			intersects: true,
+			contains:   true,
+		},
+		{
+			name:       "line out of polygon across antimeridian",
+			a:          chukotka,
+			b:          orb.LineString{{175, 55}, {-175, 55}},
+			intersects: false,
+		},
+		{
+			name: "lane south of polygon",
+			a:    chukotka,
+			b:    lanes[0],
+		},
+		{
+			name:       "lane crosses line at antimeridian",
+			a:          lanes[0],
+			b:          orb.LineString{{179, 45}, {-179, 52}},
+			intersects: true,
+		},
+		{
+			name:       "polar point",
+			a:          arctic,
+			b:          orb.Point{45, 89.9},
+			intersects: true,
+			contains:   true,
+		},
+		{
+			name:       "polar line",
+			a:          arctic,
+			b:          orb.LineString{{-170, 85.5}, {170, 86}},
+			intersects: true,
+			contains:   true,
+		},
+		{
+			name: "point south of polar cap",
+			a:    arctic,
+			b:    orb.Point{0, 70},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Intersects(tt.a, tt.b); got != tt.intersects {
+				t.Errorf("Intersects() = %v, want %v", got, tt.intersects)
+			}
+			if got := Contains(tt.a, tt.b); got != tt.contains {
+				t.Errorf("Contains() = %v, want %v", got, tt.contains)
+			}
+		})
+	}
+}
+
+func TestRelateAntimeridian(t *testing.T) {
+	chukotka := fixture.Chukotka()
+	if got := Relate(chukotka, orb.Point{-179.9, 66}).String(); got != "0F2FF1FF2" {
+		t.Errorf("Relate(polygon, point) = %v", got)
+	}
+	if !Crosses(orb.LineString{{170, 60}, {-175, 65}}, chukotka) {
+		t.Errorf("expected line crosses polygon across antimeridian")
+	}
+	if !Within(orb.LineString{{170, 65}, {-175, 65}}, chukotka) {
+		t.Errorf("expected line within polygon across antimeridian")
+	}
+	if !Disjoint(chukotka, orb.Point{0, 66}) {
+		t.Errorf("expected point at the other side of the Earth is disjoint")
+	}
+	if !Touches(fixture.ArcticCap(), orb.LineString{{10, 70}, {10, 80}}) {
+		t.Errorf("expected line touches polar polygon")
+	}
+
+	crossing := orb.Polygon{{{170, 60}, {-170, 60}, {-170, 70}, {170, 70}, {170, 60}}}
+	tests := []struct {
+		name     string
+		geom     orb.Geometry
+		expected string
+	}{
+		{name: "point at antimeridian", geom: orb.Point{180, 65}, expected: "0FFFFF212"},
+		{name: "point at negative antimeridian", geom: orb.Point{-180, 65}, expected: "0FFFFF212"},
+		{name: "line across antimeridian", geom: orb.LineString{{175, 65}, {-175, 65}}, expected: "1FF0FF212"},
+		{name: "line along antimeridian", geom: orb.LineString{{180, 62}, {180, 68}}, expected: "1FF0FF212"},
+		{name: "inner polygon", geom: orb.Polygon{{{175, 62}, {-175, 62}, {-175, 68}, {175, 68}, {175, 62}}}, expected: "2FF1FF212"},
+		{name: "polygon west of antimeridian", geom: orb.Polygon{{{-180, 62}, {-175, 62}, {-175, 68}, {-180, 68}, {-180, 62}}}, expected: "2FF1FF212"},
+		{name: "point at the other side", geom: orb.Point{0, 65}, expected: "FF0FFF212"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Relate(tt.geom, crossing).String(); got != tt.expected {
+				t.Errorf("Relate() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+	east := orb.Polygon{{{175, 0}, {180, 0}, {180, 10}, {175, 10}, {175, 0}}}
+	west := orb.Polygon{{{-180, 5}, {-175, 5}, {-175, 15}, {-180, 15}, {-180, 5}}}
+	if !Touches(east, west) {
+		t.Errorf("expected polygons touch at antimeridian")
+	}
+}
+
+func TestEdgeTouchAntimeridian(t *testing.T) {
+	crossing := orb.Polygon{{{170, 60}, {-170, 60}, {-170, 70}, {170, 70}, {170, 60}}}
+	tests := []struct {
+		name     string
+		geom     orb.Geometry
+		expected bool
+	}{
+		{name: "crossing polygon below", geom: orb.Polygon{{{175, 50}, {-175, 50}, {-175, 60}, {175, 60}, {175, 50}}}, expected: true},
+		{name: "polygon west of antimeridian below", geom: orb.Polygon{{{-179, 50}, {-175, 50}, {-175, 60}, {-179, 60}, {-179, 50}}}, expected: true},
+		{name: "overlapped polygon at antimeridian", geom: orb.Polygon{{{-180, 62}, {-175, 62}, {-175, 65}, {-180, 65}, {-180, 62}}}, expected: false},
+		{name: "polygon at the other side", geom: orb.Polygon{{{0, 50}, {5, 50}, {5, 60}, {0, 60}, {0, 50}}}, expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EdgeTouch(crossing, tt.geom); got != tt.expected {
+				t.Errorf("EdgeTouch() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+	east := orb.Polygon{{{175, 0}, {180, 0}, {180, 10}, {175, 10}, {175, 0}}}
+	west := orb.Polygon{{{-180, 5}, {-175, 5}, {-175, 15}, {-180, 15}, {-180, 5}}}
+	if !EdgeTouch(east, west) {
+		t.Errorf("expected polygons touch by antimeridian edge")
+	}
+}
+
+func TestOverlayAntimeridian(t *testing.T) {
+	chukotka := fixture.Chukotka()
+	box := orb.Polygon{{{178, 63}, {-178, 63}, {-178, 67}, {178, 67}, {178, 63}}}
+	got := Intersection(chukotka, box)
+	if len(got) != 2 {
+		t.Fatalf("expected polygon split at antimeridian, got %v", got)
+	}
+	for _, p := range got {
+		if !Covers(chukotka, p) {
+			t.Errorf("intersection part %v is not covered by polygon", p)
+		}
+	}
+	if !Contains(got, orb.Point{-179.5, 65}) || !Contains(got, orb.Point{179.5, 65}) {
+		t.Errorf("intersection should contain points on both sides of antimeridian")
+	}
+	lines := LineIntersection(fixture.PacificLanes()[1], orb.Polygon{{{170, -40}, {-170, -40}, {-170, -10}, {170, -10}, {170, -40}}})
+	if len(lines) != 2 {
+		t.Errorf("expected line split at antimeridian, got %v", lines)
+	}
+}
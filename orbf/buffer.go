@@ -0,0 +1,103 @@
+package orbf
+
+import (
+	"math"
+
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+// Buffer returns area within distance in meters of WGS84 geometry.
+// Geometry is buffered in azimuthal equidistant projection centered at the geometry,
+// so distance is accurate for geometries up to a few thousand kilometers at any latitude.
+// Result is split at antimeridian.
+func Buffer(geom orb.Geometry, meters float64, opts planar.BufferOptions) orb.MultiPolygon {
+	center, ok := sphereCenter(geom)
+	if !ok {
+		return nil
+	}
+	proj := newLocalProjection(center)
+	buf := planar.Buffer(project.Geometry(orb.Clone(geom), proj.toLocal), meters, opts)
+	if len(buf) == 0 {
+		return nil
+	}
+	buf = project.MultiPolygon(buf, proj.toWGS84)
+	if mp, ok := SplitAntimeridian(buf).(orb.MultiPolygon); ok {
+		return mp
+	}
+	return buf
+}
+
+// localProjection is a spherical azimuthal equidistant projection, distances and
+// directions from the center are true, distortion grows slowly with distance from the center.
+type localProjection struct {
+	lon0, sinLat0, cosLat0 float64
+}
+
+func newLocalProjection(center orb.Point) *localProjection {
+	lat0 := deg2rad(center.Lat())
+	return &localProjection{
+		lon0:    deg2rad(center.Lon()),
+		sinLat0: math.Sin(lat0),
+		cosLat0: math.Cos(lat0),
+	}
+}
+
+func (p *localProjection) toLocal(point orb.Point) orb.Point {
+	lat, dLon := deg2rad(point.Lat()), deg2rad(point.Lon())-p.lon0
+	sinLat, cosLat := math.Sin(lat), math.Cos(lat)
+	cosC := p.sinLat0*sinLat + p.cosLat0*cosLat*math.Cos(dLon)
+	c := math.Acos(max(-1, min(1, cosC)))
+	k := 1.
+	if c > 1e-12 {
+		k = c / math.Sin(c)
+	}
+	return orb.Point{
+		orb.EarthRadius * k * cosLat * math.Sin(dLon),
+		orb.EarthRadius * k * (p.cosLat0*sinLat - p.sinLat0*cosLat*math.Cos(dLon)),
+	}
+}
+
+func (p *localProjection) toWGS84(point orb.Point) orb.Point {
+	rho := math.Hypot(point[0], point[1])
+	if rho == 0 {
+		return orb.Point{NormalizeLon(rad2deg(p.lon0)), rad2deg(math.Asin(p.sinLat0))}
+	}
+	c := rho / orb.EarthRadius
+	sinC, cosC := math.Sin(c), math.Cos(c)
+	lat := math.Asin(max(-1, min(1, cosC*p.sinLat0+point[1]*sinC*p.cosLat0/rho)))
+	lon := p.lon0 + math.Atan2(point[0]*sinC, rho*p.cosLat0*cosC-point[1]*p.sinLat0*sinC)
+	return orb.Point{NormalizeLon(rad2deg(lon)), rad2deg(lat)}
+}
+
+// sphereCenter returns direction of the mean of geometry points on the unit sphere,
+// unlike the center of bound it doesn't depend on crossing of antimeridian.
+func sphereCenter(geom orb.Geometry) (orb.Point, bool) {
+	var x, y, z float64
+	n := 0
+	project.Geometry(orb.Clone(geom), func(p orb.Point) orb.Point {
+		lat, lon := deg2rad(p.Lat()), deg2rad(p.Lon())
+		x += math.Cos(lat) * math.Cos(lon)
+		y += math.Cos(lat) * math.Sin(lon)
+		z += math.Sin(lat)
+		n++
+		return p
+	})
+	if n == 0 {
+		return orb.Point{}, false
+	}
+	if math.Hypot(x, y) < 1e-12 {
+		// symmetric geometry, for example ring around the pole
+		return orb.Point{0, math.Copysign(90, z)}, true
+	}
+	return orb.Point{rad2deg(math.Atan2(y, x)), rad2deg(math.Atan2(z, math.Hypot(x, y)))}, true
+}
+
+func deg2rad(d float64) float64 {
+	return d * math.Pi / 180
+}
+
+func rad2deg(r float64) float64 {
+	return r * 180 / math.Pi
+}
@@ -0,0 +1,55 @@
+package orbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+func TestBuffer(t *testing.T) {
+	opts := planar.BufferOptions{QuadrantSegments: 16}
+	for _, center := range []orb.Point{{37.62, 55.75}, {10, 80}, {-70, -60}} {
+		got := Buffer(center, 1000, opts)
+		if len(got) != 1 {
+			t.Fatalf("%v: expected one polygon, got %d", center, len(got))
+		}
+		for _, p := range got[0][0] {
+			if d := geo.Distance(center, p); math.Abs(d-1000) > 1 {
+				t.Errorf("%v: expected vertex at 1000m, got %v", center, d)
+			}
+		}
+		if area := geo.Area(got); math.Abs(area-math.Pi*1e6)/(math.Pi*1e6) > 0.01 {
+			t.Errorf("%v: expected area of circle, got %v", center, area)
+		}
+	}
+
+	line := orb.LineString{{37.5, 55.7}, {37.6, 55.72}, {37.65, 55.8}}
+	buf := Buffer(line, 500, planar.BufferOptions{})
+	for i := 1; i < len(line); i++ {
+		mid := geo.Midpoint(line[i-1], line[i])
+		bearing := geo.Bearing(line[i-1], line[i])
+		for _, side := range []float64{-90, 90} {
+			if !Contains(buf, geo.PointAtBearingAndDistance(mid, bearing+side, 490)) {
+				t.Errorf("segment %d: point at 490m should be within buffer", i)
+			}
+			if Contains(buf, geo.PointAtBearingAndDistance(mid, bearing+side, 510)) {
+				t.Errorf("segment %d: point at 510m should be out of buffer", i)
+			}
+		}
+	}
+
+	shrunk := Buffer(Buffer(line, 500, planar.BufferOptions{}), -250, planar.BufferOptions{})
+	if len(shrunk) != 1 || !Contains(shrunk, line[1]) {
+		t.Errorf("negative buffer should keep the line vertex, got %v", shrunk)
+	}
+
+	if got := Buffer(orb.Point{179.995, 0}, 1000, opts); len(got) != 2 {
+		t.Errorf("expected buffer split at antimeridian, got %d polygons", len(got))
+	}
+	if got := Buffer(orb.Point{0, 89.995}, 2000, opts); !Contains(got, orb.Point{120, 89.99}) {
+		t.Errorf("buffer around pole should contain points on the other side of pole")
+	}
+}
@@ -0,0 +1,127 @@
+package orbf
+
+import (
+	"math"
+
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/VGSML/geobin/orbf/sphere"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+// Distance returns minimal distance in meters between WGS84 geometries, 0 if geometries intersect.
+// Distance to empty geometry is +Inf.
+func Distance(a, b orb.Geometry) float64 {
+	d, _, _ := ClosestPoints(a, b)
+	return d
+}
+
+// ClosestPoints returns minimal distance in meters between WGS84 geometries and the closest points of a and b.
+// Edges are great-circle arcs, intersection of geometries is checked as in Intersects,
+// for intersecting geometries distance is 0 and both points are the same common point.
+func ClosestPoints(a, b orb.Geometry) (float64, orb.Point, orb.Point) {
+	sa, sb := arcSegments(a), arcSegments(b)
+	if len(sa) == 0 || len(sb) == 0 {
+		return math.Inf(1), orb.Point{}, orb.Point{}
+	}
+	if geom1, geom2, report := PrepareOverlay(a, b); geom1 != nil && geom2 != nil {
+		if d, p, _ := planar.ClosestPoints(geom1, geom2); d == 0 {
+			if report.ProjectedA {
+				p = project.Point(p, project.Mercator.ToWGS84)
+			}
+			return 0, p, p
+		}
+	}
+	best := math.Inf(1)
+	var pa, pb orb.Point
+	for _, s1 := range sa {
+		for _, s2 := range sb {
+			// not crossing arcs have the closest point at one of the ends
+			for i, e := range s1.v {
+				p, pp := s2.closestPoint(e)
+				if d := sphere.Angle(e, p); d < best {
+					best, pa, pb = d, s1.p[i], pp
+				}
+			}
+			for i, e := range s2.v {
+				p, pp := s1.closestPoint(e)
+				if d := sphere.Angle(e, p); d < best {
+					best, pa, pb = d, pp, s2.p[i]
+				}
+			}
+		}
+	}
+	return best * orb.EarthRadius, pa, pb
+}
+
+// arcSegment is a great-circle arc between points, ends are kept also as unit vectors.
+type arcSegment struct {
+	p [2]orb.Point
+	v [2]sphere.Vector
+}
+
+func newArcSegment(a, b orb.Point) arcSegment {
+	return arcSegment{
+		p: [2]orb.Point{a, b},
+		v: [2]sphere.Vector{sphere.FromPoint(a), sphere.FromPoint(b)},
+	}
+}
+
+// closestPoint returns the point of arc closest to p as unit vector and as WGS84 point.
+func (s arcSegment) closestPoint(p sphere.Vector) (sphere.Vector, orb.Point) {
+	switch c, end := sphere.ArcClosestPoint(s.v[0], s.v[1], p); end {
+	case 0, 1:
+		return c, s.p[end]
+	default:
+		return c, c.Point()
+	}
+}
+
+// arcSegments returns edges of geometry as great-circle arcs,
+// points are returned as edges with equal ends.
+func arcSegments(geom orb.Geometry) []arcSegment {
+	var out []arcSegment
+	addPoints := func(pp []orb.Point) {
+		if len(pp) == 1 {
+			out = append(out, newArcSegment(pp[0], pp[0]))
+		}
+		for i := 1; i < len(pp); i++ {
+			out = append(out, newArcSegment(pp[i-1], pp[i]))
+		}
+	}
+	var add func(geom orb.Geometry)
+	add = func(geom orb.Geometry) {
+		switch g := geom.(type) {
+		case orb.Point:
+			addPoints([]orb.Point{g})
+		case orb.MultiPoint:
+			for _, p := range g {
+				addPoints([]orb.Point{p})
+			}
+		case orb.LineString:
+			addPoints(g)
+		case orb.MultiLineString:
+			for _, l := range g {
+				addPoints(l)
+			}
+		case orb.Ring:
+			addPoints(g)
+		case orb.Polygon:
+			for _, r := range g {
+				addPoints(r)
+			}
+		case orb.MultiPolygon:
+			for _, p := range g {
+				add(p)
+			}
+		case orb.Bound:
+			add(g.ToPolygon())
+		case orb.Collection:
+			for _, g := range g {
+				add(g)
+			}
+		}
+	}
+	add(geom)
+	return out
+}
@@ -0,0 +1,45 @@
+package orbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+func TestClosestPoints(t *testing.T) {
+	degree := orb.EarthRadius * math.Pi / 180
+	square := orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+
+	tests := []struct {
+		name   string
+		a, b   orb.Geometry
+		dist   float64
+		pa, pb orb.Point
+	}{
+		{name: "points", a: orb.Point{37.62, 55.75}, b: orb.Point{30.31, 59.94}, dist: geo.DistanceHaversine(orb.Point{37.62, 55.75}, orb.Point{30.31, 59.94}), pa: orb.Point{37.62, 55.75}, pb: orb.Point{30.31, 59.94}},
+		{name: "point and equator", a: orb.Point{0.5, 1}, b: orb.LineString{{0, 0}, {1, 0}}, dist: degree, pa: orb.Point{0.5, 1}, pb: orb.Point{0.5, 0}},
+		{name: "antimeridian", a: orb.Point{179.9, 0}, b: orb.Point{-179.9, 0}, dist: 0.2 * degree, pa: orb.Point{179.9, 0}, pb: orb.Point{-179.9, 0}},
+		{name: "point in polygon", a: square, b: orb.Point{0.5, 0.5}, pa: orb.Point{0.5, 0.5}, pb: orb.Point{0.5, 0.5}},
+		{name: "polygons", a: square, b: orb.Polygon{{{3, 0}, {4, 0}, {4, 1}, {3, 0}}}, dist: 2 * degree, pa: orb.Point{1, 0}, pb: orb.Point{3, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dist, pa, pb := ClosestPoints(tt.a, tt.b)
+			if math.Abs(dist-tt.dist) > 1e-6 {
+				t.Errorf("expected distance %v, got %v", tt.dist, dist)
+			}
+			if planarDistance(pa, tt.pa) > 1e-9 || planarDistance(pb, tt.pb) > 1e-9 {
+				t.Errorf("expected closest points %v %v, got %v %v", tt.pa, tt.pb, pa, pb)
+			}
+		})
+	}
+	if d := Distance(square, orb.LineString{{0.5, 0.5}, {5, 5}}); d != 0 {
+		t.Errorf("expected zero distance of intersecting geometries, got %v", d)
+	}
+}
+
+func planarDistance(a, b orb.Point) float64 {
+	return math.Hypot(a[0]-b[0], a[1]-b[1])
+}
@@ -0,0 +1,698 @@
+package orbf
+
+import "math"
+
+// The geodesic calculations on the WGS84 ellipsoid follow
+// C. F. F. Karney, Algorithms for geodesics, J. Geodesy 87, 43–55 (2013),
+// the implementation is a port of the inverse problem and polygon area of GeographicLib
+// with series of the sixth order, so the errors are about 15 nm for distances and 0.1 m² for areas.
+
+const (
+	// wgs84A is the equatorial radius of WGS84 ellipsoid in meters.
+	wgs84A = 6378137
+	// wgs84F is the flattening of WGS84 ellipsoid.
+	wgs84F = 1 / 298.257223563
+
+	geodesicOrder = 6
+	nC3x          = geodesicOrder * (geodesicOrder - 1) / 2
+	nC4x          = geodesicOrder * (geodesicOrder + 1) / 2
+	maxit1        = 20
+	maxit2        = maxit1 + 53 + 10
+)
+
+var (
+	tiny    = math.Sqrt(0x1p-1022)
+	tol0    = math.Nextafter(1, 2) - 1
+	tol1    = 200 * tol0
+	tol2    = math.Sqrt(tol0)
+	tolb    = tol0
+	xthresh = 1000 * tol2
+
+	wgs84 = newGeodesic(wgs84A, wgs84F)
+)
+
+// geodesic is an ellipsoid of revolution with precomputed series coefficients.
+type geodesic struct {
+	a, f, f1, e2, ep2, n, b, c2, etol2 float64
+
+	a3x [geodesicOrder]float64
+	c3x [nC3x]float64
+	c4x [nC4x]float64
+}
+
+func newGeodesic(a, f float64) *geodesic {
+	g := &geodesic{a: a, f: f}
+	g.f1 = 1 - f
+	g.e2 = f * (2 - f)
+	g.ep2 = g.e2 / (g.f1 * g.f1)
+	g.n = f / (2 - f)
+	g.b = a * g.f1
+	g.c2 = (a*a + g.b*g.b*math.Atanh(math.Sqrt(g.e2))/math.Sqrt(g.e2)) / 2
+	g.etol2 = 0.1 * tol2 / math.Sqrt(max(0.001, math.Abs(f))*min(1, 1-f/2)/2)
+	g.initA3()
+	g.initC3()
+	g.initC4()
+	return g
+}
+
+// inverse solves the inverse geodesic problem and returns distance in meters between points,
+// cosine and sine of azimuths at the points and area in square meters between the geodesic and the equator.
+func (g *geodesic) inverse(lat1, lon1, lat2, lon2 float64) (s12, salp1, calp1, salp2, calp2, S12 float64) {
+	var m12x, s12x, sig12, omg12 float64
+	somg12, comg12 := 2., 0.
+	var ca [geodesicOrder + 1]float64
+
+	lon12, lon12s := angDiff(lon1, lon2)
+	lonsign := 1.
+	if math.Signbit(lon12) {
+		lonsign = -1
+	}
+	lon12 *= lonsign
+	lon12s *= lonsign
+	lam12 := lon12 * math.Pi / 180
+	slam12, clam12 := sincosde(lon12, lon12s)
+	lon12s = (180 - lon12) - lon12s
+
+	lat1, lat2 = angRound(latFix(lat1)), angRound(latFix(lat2))
+	swapp := 1.
+	if math.Abs(lat1) < math.Abs(lat2) || math.IsNaN(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+	latsign := -1.
+	if math.Signbit(lat1) {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+
+	sbet1, cbet1 := sincosd(lat1)
+	sbet1 *= g.f1
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = max(tiny, cbet1)
+	sbet2, cbet2 := sincosd(lat2)
+	sbet2 *= g.f1
+	sbet2, cbet2 = norm2(sbet2, cbet2)
+	cbet2 = max(tiny, cbet2)
+
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + g.ep2*sbet1*sbet1)
+	dn2 := math.Sqrt(1 + g.ep2*sbet2*sbet2)
+
+	meridian := lat1 == -90 || slam12 == 0
+	if meridian {
+		// endpoints are on a single full meridian, so the geodesic might lie on a meridian
+		calp1, salp1 = clam12, slam12
+		calp2, salp2 = 1, 0
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+		sig12 = math.Atan2(max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _ = g.lengths(g.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, ca[:])
+		if sig12 < 1 || m12x >= 0 {
+			if sig12 < 3*tiny || (sig12 < tol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= g.b
+			s12x *= g.b
+		} else {
+			// prolate and too close to anti-podal
+			meridian = false
+		}
+	}
+
+	switch {
+	case !meridian && sbet1 == 0 && (g.f <= 0 || lon12s >= g.f*180):
+		// geodesic runs along equator
+		calp1, calp2, salp1, salp2 = 0, 0, 1, 1
+		s12x = g.a * lam12
+		sig12 = lam12 / g.f1
+		omg12 = sig12
+		m12x = g.b * math.Sin(sig12)
+	case !meridian:
+		var dnm float64
+		sig12, salp1, calp1, salp2, calp2, dnm = g.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12, ca[:])
+		if sig12 >= 0 {
+			// short lines
+			s12x = sig12 * g.b * dnm
+			m12x = dnm * dnm * g.b * math.Sin(sig12/dnm)
+			omg12 = lam12 / (g.f1 * dnm)
+			break
+		}
+		// Newton's method on alp1 with bracketing of the root
+		var ssig1, csig1, ssig2, csig2, eps, domg12 float64
+		salp1a, calp1a, salp1b, calp1b := tiny, 1., tiny, -1.
+		tripn, tripb := false, false
+		for numit := 0; ; numit++ {
+			var v, dv float64
+			v, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dv = g.lambda12(
+				sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12, numit < maxit1, ca[:])
+			tol := tol0
+			if tripn {
+				tol *= 8
+			}
+			if tripb || !(math.Abs(v) >= tol) || numit == maxit2 {
+				break
+			}
+			if v > 0 && (numit > maxit1 || calp1/salp1 > calp1b/salp1b) {
+				salp1b, calp1b = salp1, calp1
+			} else if v < 0 && (numit > maxit1 || calp1/salp1 < calp1a/salp1a) {
+				salp1a, calp1a = salp1, calp1
+			}
+			if numit < maxit1 && dv > 0 {
+				dalp1 := -v / dv
+				if math.Abs(dalp1) < math.Pi {
+					sdalp1, cdalp1 := math.Sincos(dalp1)
+					if nsalp1 := salp1*cdalp1 + calp1*sdalp1; nsalp1 > 0 {
+						calp1 = calp1*cdalp1 - salp1*sdalp1
+						salp1 = nsalp1
+						salp1, calp1 = norm2(salp1, calp1)
+						tripn = math.Abs(v) <= 16*tol0
+						continue
+					}
+				}
+			}
+			// the midpoint of the bracket is the next estimate
+			salp1, calp1 = norm2((salp1a+salp1b)/2, (calp1a+calp1b)/2)
+			tripn = false
+			tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < tolb || math.Abs(salp1-salp1b)+(calp1-calp1b) < tolb
+		}
+		s12x, m12x, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, ca[:])
+		m12x *= g.b
+		s12x *= g.b
+		sdomg12, cdomg12 := math.Sincos(domg12)
+		somg12 = slam12*cdomg12 - clam12*sdomg12
+		comg12 = clam12*cdomg12 + slam12*sdomg12
+	}
+	s12 = 0 + s12x
+
+	// area between the geodesic and the equator
+	salp0, calp0 := salp1*cbet1, math.Hypot(calp1, salp1*sbet1)
+	if calp0 != 0 && salp0 != 0 {
+		ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+		ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+		k2 := calp0 * calp0 * g.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		a4 := g.a * g.a * calp0 * salp0 * g.e2
+		g.c4f(eps, ca[:])
+		b41 := sinCosSeries(false, ssig1, csig1, ca[:geodesicOrder])
+		b42 := sinCosSeries(false, ssig2, csig2, ca[:geodesicOrder])
+		S12 = a4 * (b42 - b41)
+	}
+	if !meridian && somg12 == 2 {
+		somg12, comg12 = math.Sincos(omg12)
+	}
+	var alp12 float64
+	if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+		domg12, dbet1, dbet2 := 1+comg12, 1+cbet1, 1+cbet2
+		alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+	} else {
+		salp12 := salp2*calp1 - calp2*salp1
+		calp12 := calp2*calp1 + salp2*salp1
+		if salp12 == 0 && calp12 < 0 {
+			salp12 = tiny * calp1
+			calp12 = -1
+		}
+		alp12 = math.Atan2(salp12, calp12)
+	}
+	S12 += g.c2 * alp12
+	S12 *= swapp * lonsign * latsign
+	S12 += 0
+
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+	}
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+	return s12, salp1, calp1, salp2, calp2, S12
+}
+
+// lengths returns distance and reduced length divided by b and coefficient of secular term of reduced length.
+func (g *geodesic) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2 float64, ca []float64) (s12b, m12b, m0 float64) {
+	var cb [geodesicOrder + 1]float64
+	a1 := a1m1f(eps)
+	c1f(eps, ca)
+	a2 := a2m1f(eps)
+	c2f(eps, cb[:])
+	m0 = a1 - a2
+	a1, a2 = 1+a1, 1+a2
+	b1 := sinCosSeries(true, ssig2, csig2, ca) - sinCosSeries(true, ssig1, csig1, ca)
+	s12b = a1 * (sig12 + b1)
+	b2 := sinCosSeries(true, ssig2, csig2, cb[:]) - sinCosSeries(true, ssig1, csig1, cb[:])
+	j12 := m0*sig12 + (a1*b1 - a2*b2)
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*j12
+	return s12b, m12b, m0
+}
+
+// inverseStart returns a starting point for Newton's method in salp1 and calp1 and negative sig12,
+// if Newton's method doesn't need to be used, it returns also salp2, calp2 and sig12.
+func (g *geodesic) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12 float64, ca []float64) (
+	sig12, salp1, calp1, salp2, calp2, dnm float64,
+) {
+	sig12 = -1
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sincos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	switch {
+	case shortline && ssig12 < g.etol2:
+		// really short lines
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*somg12*somg12/(1+comg12)
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		sig12 = math.Atan2(ssig12, csig12)
+	case math.Abs(g.n) > 0.1 || csig12 >= 0 || ssig12 >= 6*math.Abs(g.n)*math.Pi*cbet1*cbet1:
+		// zeroth order spherical approximation is OK
+	default:
+		// scale lam12 and bet2 to x, y coordinate system where antipodal point
+		// is at origin and singular point is at y = 0, x = -1
+		lam12x := math.Atan2(-slam12, -clam12)
+		k2 := sbet1 * sbet1 * g.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := g.f * cbet1 * g.a3f(eps) * math.Pi
+		betscale := lamscale * cbet1
+		x := lam12x / lamscale
+		y := sbet12a / betscale
+		if y > -tol1 && x > -1-xthresh {
+			// strip near cut
+			salp1 = min(1, -x)
+			calp1 = -math.Sqrt(1 - salp1*salp1)
+		} else {
+			k := astroid(x, y)
+			omg12a := lamscale * (-x * k / (1 + k))
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+	return sig12, salp1, calp1, salp2, calp2, dnm
+}
+
+// lambda12 returns difference of longitudes of the geodesic with azimuth alp1 and lam12,
+// the derivative of the difference by alp1 is calculated if diffp is set.
+func (g *geodesic) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64, diffp bool, ca []float64) (
+	lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12 float64,
+) {
+	if sbet1 == 0 && calp1 == 0 {
+		// break degeneracy of equatorial line
+		calp1 = -tiny
+	}
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1)
+
+	ssig1 = sbet1
+	somg1 := salp0 * sbet1
+	csig1 = calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1)
+
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	} else {
+		salp2 = salp1
+	}
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		d := (sbet1 - sbet2) * (sbet1 + sbet2)
+		if cbet1 < -sbet1 {
+			d = (cbet2 - cbet1) * (cbet1 + cbet2)
+		}
+		calp2 = math.Sqrt(calp1*cbet1*calp1*cbet1+d) / cbet2
+	} else {
+		calp2 = math.Abs(calp1)
+	}
+	ssig2 = sbet2
+	somg2 := salp0 * sbet2
+	csig2 = calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm2(ssig2, csig2)
+
+	sig12 = math.Atan2(max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+	somg12 := max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+	k2 := calp0 * calp0 * g.ep2
+	eps = k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	g.c3f(eps, ca)
+	b312 := sinCosSeries(true, ssig2, csig2, ca[:geodesicOrder]) - sinCosSeries(true, ssig1, csig1, ca[:geodesicOrder])
+	domg12 = -g.f * g.a3f(eps) * salp0 * (sig12 + b312)
+	lam12 = eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			dlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, dlam12, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, ca)
+			dlam12 *= g.f1 / (calp2 * cbet2)
+		}
+	}
+	return lam12, salp2, calp2, sig12, ssig1, csig1, ssig2, csig2, eps, domg12, dlam12
+}
+
+func (g *geodesic) a3f(eps float64) float64 {
+	return polyval(g.a3x[:], eps)
+}
+
+// c3f sets c[1] through c[geodesicOrder-1].
+func (g *geodesic) c3f(eps float64, c []float64) {
+	mult := 1.
+	o := 0
+	for l := 1; l < geodesicOrder; l++ {
+		m := geodesicOrder - l - 1
+		mult *= eps
+		c[l] = mult * polyval(g.c3x[o:o+m+1], eps)
+		o += m + 1
+	}
+}
+
+// c4f sets c[0] through c[geodesicOrder-1].
+func (g *geodesic) c4f(eps float64, c []float64) {
+	mult := 1.
+	o := 0
+	for l := 0; l < geodesicOrder; l++ {
+		m := geodesicOrder - l - 1
+		c[l] = mult * polyval(g.c4x[o:o+m+1], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+func (g *geodesic) initA3() {
+	coeff := []float64{
+		-3, 128,
+		-2, -3, 64,
+		-1, -3, -1, 16,
+		3, -1, -2, 8,
+		1, -1, 2,
+		1, 1,
+	}
+	o, k := 0, 0
+	for j := geodesicOrder - 1; j >= 0; j-- {
+		m := min(geodesicOrder-j-1, j)
+		g.a3x[k] = polyval(coeff[o:o+m+1], g.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+func (g *geodesic) initC3() {
+	coeff := []float64{
+		3, 128,
+		2, 5, 128,
+		-1, 3, 3, 64,
+		-1, 0, 1, 8,
+		-1, 1, 4,
+		5, 256,
+		1, 3, 128,
+		-3, -2, 3, 64,
+		1, -3, 2, 32,
+		7, 512,
+		-10, 9, 384,
+		5, -9, 5, 192,
+		7, 512,
+		-14, 7, 512,
+		21, 2560,
+	}
+	o, k := 0, 0
+	for l := 1; l < geodesicOrder; l++ {
+		for j := geodesicOrder - 1; j >= l; j-- {
+			m := min(geodesicOrder-j-1, j)
+			g.c3x[k] = polyval(coeff[o:o+m+1], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func (g *geodesic) initC4() {
+	coeff := []float64{
+		97, 15015,
+		1088, 156, 45045,
+		-224, -4784, 1573, 45045,
+		-10656, 14144, -4576, -858, 45045,
+		64, 624, -4576, 6864, -3003, 15015,
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		1, 9009,
+		-2944, 468, 135135,
+		5792, 1040, -1287, 135135,
+		5952, -11648, 9152, -2574, 135135,
+		-64, -624, 4576, -6864, 3003, 135135,
+		8, 10725,
+		1856, -936, 225225,
+		-8448, 4992, -1144, 225225,
+		-1440, 4160, -4576, 1716, 225225,
+		-136, 63063,
+		1024, -208, 105105,
+		3584, -3328, 1144, 315315,
+		-128, 135135,
+		-2560, 832, 405405,
+		128, 99099,
+	}
+	o, k := 0, 0
+	for l := 0; l < geodesicOrder; l++ {
+		for j := geodesicOrder - 1; j >= l; j-- {
+			m := geodesicOrder - j - 1
+			g.c4x[k] = polyval(coeff[o:o+m+1], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+func a1m1f(eps float64) float64 {
+	t := polyval([]float64{1, 4, 64, 0}, eps*eps) / 256
+	return (t + eps) / (1 - eps)
+}
+
+// c1f sets c[1] through c[geodesicOrder].
+func c1f(eps float64, c []float64) {
+	coeff := []float64{
+		-1, 6, -16, 32,
+		-9, 64, -128, 2048,
+		9, -16, 768,
+		3, -5, 512,
+		-7, 1280,
+		-7, 2048,
+	}
+	seriesCoefficients(coeff, eps, c)
+}
+
+func a2m1f(eps float64) float64 {
+	t := polyval([]float64{-11, -28, -192, 0}, eps*eps) / 256
+	return (t - eps) / (1 + eps)
+}
+
+// c2f sets c[1] through c[geodesicOrder].
+func c2f(eps float64, c []float64) {
+	coeff := []float64{
+		1, 2, 16, 32,
+		35, 64, 384, 2048,
+		15, 80, 768,
+		7, 35, 512,
+		63, 1280,
+		77, 2048,
+	}
+	seriesCoefficients(coeff, eps, c)
+}
+
+// seriesCoefficients evaluates coefficients c[l] = eps^l * P_l(eps^2) of C1 and C2 series.
+func seriesCoefficients(coeff []float64, eps float64, c []float64) {
+	eps2, d := eps*eps, eps
+	o := 0
+	for l := 1; l <= geodesicOrder; l++ {
+		m := (geodesicOrder - l) / 2
+		c[l] = d * polyval(coeff[o:o+m+1], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// sinCosSeries evaluates sum(c[i] * sin(2*i*x), i, 1, n) if sinp is set,
+// else sum(c[i] * cos((2*i+1)*x), i, 0, n-1) using Clenshaw summation,
+// where n = len(c) - 1 for sine series and len(c) for cosine series.
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64) float64 {
+	k := len(c)
+	n := k
+	if sinp {
+		n--
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	for n /= 2; n > 0; n-- {
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0
+	}
+	return cosx * (y0 - y1)
+}
+
+// astroid solves k^4+2*k^3-(x^2+y^2-1)*k^2-2*y^2*k-y^2 = 0 for positive root k.
+func astroid(x, y float64) float64 {
+	p, q := x*x, y*y
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		return 0
+	}
+	s := p * q / 4
+	r2 := r * r
+	r3 := r * r2
+	disc := s * (s + 2*r3)
+	u := r
+	if disc >= 0 {
+		t3 := s + r3
+		if t3 < 0 {
+			t3 -= math.Sqrt(disc)
+		} else {
+			t3 += math.Sqrt(disc)
+		}
+		t := math.Cbrt(t3)
+		u += t
+		if t != 0 {
+			u += r2 / t
+		}
+	} else {
+		ang := math.Atan2(math.Sqrt(-disc), -(s + r3))
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(u*u + q)
+	uv := u + v
+	if u < 0 {
+		uv = q / (v - u)
+	}
+	w := (uv - q) / (2 * v)
+	return uv / (math.Sqrt(uv+w*w) + w)
+}
+
+// polyval evaluates polynomial with coefficients p from the highest degree.
+func polyval(p []float64, x float64) float64 {
+	y := 0.
+	for _, c := range p {
+		y = y*x + c
+	}
+	return y
+}
+
+// sumx returns sum of u and v and its round-off error.
+func sumx(u, v float64) (float64, float64) {
+	s := u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	if s == 0 {
+		return s, s
+	}
+	return s, -(up + vpp)
+}
+
+// angDiff returns exact difference lon2 - lon1 reduced to [-180, 180] and its round-off error.
+func angDiff(x, y float64) (float64, float64) {
+	d, t := sumx(math.Remainder(-x, 360), math.Remainder(y, 360))
+	d, t = sumx(math.Remainder(d, 360), t)
+	if d == 0 || math.Abs(d) == 180 {
+		if t == 0 {
+			d = math.Copysign(d, y-x)
+		} else {
+			d = math.Copysign(d, -t)
+		}
+	}
+	return d, t
+}
+
+// angRound rounds tiny angles, so small values become zero.
+func angRound(x float64) float64 {
+	const z = 1. / 16
+	y := math.Abs(x)
+	if w := z - y; w > 0 {
+		y = z - w
+	}
+	return math.Copysign(y, x)
+}
+
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+// sincosd returns sine and cosine of angle in degrees exactly for multiples of 90°.
+func sincosd(x float64) (float64, float64) {
+	return sincosde(x, 0)
+}
+
+// sincosde returns sine and cosine of x + t in degrees, t is a small correction.
+func sincosde(x, t float64) (float64, float64) {
+	r := math.Remainder(x, 90)
+	q := int(math.Round((x - r) / 90))
+	r = angRound(r+t) * math.Pi / 180
+	s, c := math.Sincos(r)
+	var sinx, cosx float64
+	switch q & 3 {
+	case 0:
+		sinx, cosx = s, c
+	case 1:
+		sinx, cosx = c, -s
+	case 2:
+		sinx, cosx = -s, -c
+	default:
+		sinx, cosx = -c, s
+	}
+	cosx += 0
+	if sinx == 0 {
+		sinx = math.Copysign(sinx, x)
+	}
+	return sinx, cosx
+}
+
+func norm2(sinx, cosx float64) (float64, float64) {
+	r := math.Hypot(sinx, cosx)
+	return sinx / r, cosx / r
+}
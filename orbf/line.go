@@ -63,7 +63,7 @@ func LineLocatePoint(line orb.LineString, point orb.Point) (float64, float64) {
 		}
 	}
 
-	return LineLength(append(line[0:index], find)) / lineLen, minDist
+	return LineLength(append(line.Clone()[0:index], find)) / lineLen, minDist
 }
 
 // IsPointOnTheLine returns true if point located on the line.
@@ -0,0 +1,140 @@
+package orbf
+
+import (
+	"math"
+
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"golang.org/x/exp/slices"
+)
+
+// NewMeasuredLine returns WGS84 line with measures from the start measure to the end measure
+// proportional to the length along the line as LineLength. Use from = 0 and to = LineLength(line)
+// for chainage in meters.
+func NewMeasuredLine(line orb.LineString, from, to float64) planar.MeasuredLine {
+	return planar.NewMeasuredLineDistance(line, from, to, geo.DistanceHaversine)
+}
+
+// LocateAlong returns the point of WGS84 line at the measure shifted perpendicular to the line by offset in meters,
+// positive offset places the point on the right of the line direction as LineLocatePoint distance.
+// Returns false if the measure is out of the line measures.
+func LocateAlong(ml planar.MeasuredLine, m, offset float64) (orb.Point, bool) {
+	i, t, ok := ml.Locate(m)
+	if !ok {
+		return orb.Point{}, false
+	}
+	if len(ml.Line) == 1 {
+		return ml.Line[0], true
+	}
+	line := unwrap(ml.Line)
+	a, b := line[i], line[i+1]
+	if offset == 0 {
+		if t == 1 {
+			return ml.Line[i+1], true
+		}
+		// interpolated from the original point, so points of meridian segments keep longitude
+		return orb.Point{NormalizeLon(ml.Line[i][0] + (b[0]-a[0])*t), a[1] + (b[1]-a[1])*t}, true
+	}
+	p := orb.Point{a[0] + (b[0]-a[0])*t, a[1] + (b[1]-a[1])*t}
+	// direction of the nearest segment of not equal points
+	for d := 1; a == b && d < len(line); d++ {
+		for _, k := range [2]int{i + d, i - d} {
+			if a == b && k >= 0 && k+1 < len(line) && line[k] != line[k+1] {
+				a, b = line[k], line[k+1]
+			}
+		}
+	}
+	if a != b {
+		bearing := geo.Bearing(p, b)
+		if p == b {
+			bearing = geo.Bearing(b, a) + 180
+		}
+		p = geo.PointAtBearingAndDistance(p, bearing+90, offset)
+	}
+	return orb.Point{NormalizeLon(p[0]), p[1]}, true
+}
+
+// LocateBetween returns the part of WGS84 line between measures, the range is clipped to the line measures.
+// Returns false if the range and the line measures don't intersect.
+func LocateBetween(ml planar.MeasuredLine, from, to float64) (planar.MeasuredLine, bool) {
+	line := unwrap(ml.Line)
+	original := make(map[orb.Point]orb.Point, len(line))
+	for i, p := range line {
+		original[p] = ml.Line[i]
+	}
+	sub, ok := planar.LocateBetween(planar.MeasuredLine{Line: line, M: ml.M}, from, to)
+	if !ok {
+		return sub, false
+	}
+	for i, p := range sub.Line[1 : len(sub.Line)-1] {
+		sub.Line[i+1] = original[p]
+	}
+	sub.Line[0], _ = LocateAlong(ml, sub.M[0], 0)
+	sub.Line[len(sub.Line)-1], _ = LocateAlong(ml, sub.M[len(sub.M)-1], 0)
+	return sub, true
+}
+
+// LineLocateMeasure returns the measure of WGS84 line point closest to the point
+// and signed distance in meters between the point and the line as LineLocatePoint.
+func LineLocateMeasure(ml planar.MeasuredLine, point orb.Point) (float64, float64) {
+	if !ml.Valid() {
+		return 0, 0
+	}
+	if len(ml.Line) == 1 {
+		return ml.M[0], geo.DistanceHaversine(ml.Line[0], point)
+	}
+	line := unwrap(ml.Line)
+	m, minDist := 0., math.MaxFloat64
+	for i := 1; i < len(line); i++ {
+		a, b := line[i-1], line[i]
+		// point in the longitude window of the segment
+		p := orb.Point{point[0] + 360*math.Round((a[0]-point[0])/360), point[1]}
+		cp, dist := closestPoint(a, b, p)
+		if math.Abs(dist) < math.Abs(minDist) {
+			minDist = dist
+			m = ml.M[i-1]
+			if l := geo.DistanceHaversine(a, b); l > 0 {
+				m += (ml.M[i] - ml.M[i-1]) * min(geo.DistanceHaversine(a, cp)/l, 1)
+			}
+		}
+	}
+	return m, minDist
+}
+
+// LineSubstring returns the part of WGS84 line between fractions of the line length as LineLength,
+// fractions are clipped to [0, 1]. If fromFrac is greater than toFrac the part is reversed.
+func LineSubstring(line orb.LineString, fromFrac, toFrac float64) orb.LineString {
+	sub, ok := LocateBetween(NewMeasuredLine(line, 0, 1), max(0, min(fromFrac, 1)), max(0, min(toFrac, 1)))
+	if !ok {
+		return nil
+	}
+	if fromFrac > toFrac {
+		sub.Line.Reverse()
+	}
+	return sub.Line
+}
+
+// SplitLineAtPoints splits WGS84 line at the line points closest to the points.
+// Parts of zero length are not returned.
+func SplitLineAtPoints(line orb.LineString, points []orb.Point) orb.MultiLineString {
+	ml := NewMeasuredLine(line, 0, LineLength(line))
+	if !ml.Valid() {
+		return nil
+	}
+	measures := []float64{ml.M[0], ml.M[len(ml.M)-1]}
+	for _, p := range points {
+		m, _ := LineLocateMeasure(ml, p)
+		measures = append(measures, m)
+	}
+	slices.Sort(measures)
+	measures = slices.Compact(measures)
+	var out orb.MultiLineString
+	for i := 1; i < len(measures); i++ {
+		part, ok := LocateBetween(ml, measures[i-1], measures[i])
+		if ok && (len(part.Line) > 2 || part.Line[0] != part.Line[1]) {
+			out = append(out, part.Line)
+		}
+	}
+	return out
+}
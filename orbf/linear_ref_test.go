@@ -0,0 +1,73 @@
+package orbf
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+func TestLineSubstring(t *testing.T) {
+	line := orb.LineString{{179.9, 0}, {-179.9, 0}}
+	got := LineSubstring(line, 0.25, 0.75)
+	if len(got) != 2 || math.Abs(got[0].Lon()-179.95) > 1e-9 || math.Abs(got[1].Lon()+179.95) > 1e-9 {
+		t.Errorf("expected substring across antimeridian, got %v", got)
+	}
+	if got := LineSubstring(line, 1, 0); !reflect.DeepEqual(got, orb.LineString{{-179.9, 0}, {179.9, 0}}) {
+		t.Errorf("expected reversed line, got %v", got)
+	}
+}
+
+func TestSplitLineAtPoints(t *testing.T) {
+	line := orb.LineString{{179.9, 0}, {-179.9, 0}, {-179.9, 1}}
+	got := SplitLineAtPoints(line, []orb.Point{{-180, 0.001}, {-179.899, 0.5}})
+	if len(got) != 3 {
+		t.Fatalf("expected 3 parts, got %v", got)
+	}
+	if got[0][0] != line[0] || got[2][len(got[2])-1] != line[2] {
+		t.Errorf("expected original ends, got %v", got)
+	}
+	if p := got[0][1]; math.Abs(math.Abs(p.Lon())-180) > 1e-9 || p.Lat() != 0 {
+		t.Errorf("expected split at antimeridian, got %v", p)
+	}
+	if p := got[2][0]; p.Lon() != -179.9 || math.Abs(p.Lat()-0.5) > 1e-6 {
+		t.Errorf("expected split on meridian segment, got %v", p)
+	}
+}
+
+func TestMeasuredLine(t *testing.T) {
+	line := orb.LineString{{179.9, 0}, {-179.9, 0}}
+	length := LineLength(line)
+	ml := NewMeasuredLine(line, 0, length)
+
+	p, ok := LocateAlong(ml, length/2, 100)
+	if !ok || math.Abs(math.Abs(p.Lon())-180) > 1e-9 {
+		t.Fatalf("expected point at antimeridian, got %v %v", p, ok)
+	}
+	// the right side of the eastward line is south
+	if d := geo.DistanceHaversine(p, orb.Point{180, 0}); p.Lat() >= 0 || math.Abs(d-100) > 1e-6 {
+		t.Errorf("expected point 100m south, got %v at %v", p, d)
+	}
+	m, d := LineLocateMeasure(ml, p)
+	if math.Abs(m-length/2) > 1e-3 || math.Abs(d-100) > 0.01 {
+		t.Errorf("expected located at %v 100, got %v %v", length/2, m, d)
+	}
+
+	part, ok := LocateBetween(ml, length/4, 2*length)
+	if !ok || len(part.Line) != 2 || part.Line[1] != line[1] || part.M[1] != length {
+		t.Errorf("unexpected part %v %v", part, ok)
+	}
+	if _, ok := LocateAlong(ml, -1, 0); ok {
+		t.Errorf("expected measure out of the line")
+	}
+}
+
+func TestLineLocatePointKeepsLine(t *testing.T) {
+	line := orb.LineString{{0, 0}, {1, 0}, {2, 0}}
+	frac, _ := LineLocatePoint(line, orb.Point{0.5, 0.1})
+	if math.Abs(frac-0.25) > 1e-6 || line[1] != (orb.Point{1, 0}) {
+		t.Errorf("expected 0.25 and unchanged line, got %v %v", frac, line)
+	}
+}
@@ -0,0 +1,183 @@
+package orbf
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// GeodesicDistance returns the length in meters of the shortest geodesic between points on the WGS84 ellipsoid.
+func GeodesicDistance(p1, p2 orb.Point) float64 {
+	s12, _, _, _, _, _ := wgs84.inverse(p1.Lat(), p1.Lon(), p2.Lat(), p2.Lon())
+	return s12
+}
+
+// Length returns the length in meters of lines on the WGS84 ellipsoid,
+// polygons are measured by their perimeter, points have zero length.
+func Length(geom orb.Geometry) float64 {
+	switch g := geom.(type) {
+	case orb.LineString:
+		return pointsLength(g)
+	case orb.MultiLineString:
+		length := 0.
+		for _, l := range g {
+			length += pointsLength(l)
+		}
+		return length
+	case orb.Ring, orb.Polygon, orb.MultiPolygon, orb.Bound:
+		return Perimeter(g)
+	case orb.Collection:
+		length := 0.
+		for _, g := range g {
+			length += Length(g)
+		}
+		return length
+	}
+	return 0
+}
+
+// Perimeter returns the length in meters of boundaries of polygons and rings on the WGS84 ellipsoid
+// including boundaries of holes. Not closed rings are closed.
+func Perimeter(geom orb.Geometry) float64 {
+	switch g := geom.(type) {
+	case orb.Ring:
+		_, perimeter := ringArea(g)
+		return perimeter
+	case orb.Polygon:
+		perimeter := 0.
+		for _, r := range g {
+			perimeter += Perimeter(r)
+		}
+		return perimeter
+	case orb.MultiPolygon:
+		perimeter := 0.
+		for _, p := range g {
+			perimeter += Perimeter(p)
+		}
+		return perimeter
+	case orb.Bound:
+		return Perimeter(g.ToPolygon())
+	case orb.Collection:
+		perimeter := 0.
+		for _, g := range g {
+			perimeter += Perimeter(g)
+		}
+		return perimeter
+	}
+	return 0
+}
+
+// Area returns the area in square meters of polygons on the WGS84 ellipsoid,
+// areas of holes are subtracted, orientation of rings is ignored.
+// Edges of polygons are geodesics.
+func Area(geom orb.Geometry) float64 {
+	switch g := geom.(type) {
+	case orb.Ring:
+		return math.Abs(SignedArea(g))
+	case orb.Polygon:
+		if len(g) == 0 {
+			return 0
+		}
+		area := Area(g[0])
+		for _, h := range g[1:] {
+			area -= Area(h)
+		}
+		return max(0, area)
+	case orb.MultiPolygon:
+		area := 0.
+		for _, p := range g {
+			area += Area(p)
+		}
+		return area
+	case orb.Bound:
+		return Area(g.ToPolygon())
+	case orb.Collection:
+		area := 0.
+		for _, g := range g {
+			area += Area(g)
+		}
+		return area
+	}
+	return 0
+}
+
+// SignedArea returns the area in square meters of the ring on the WGS84 ellipsoid,
+// the area is positive for counterclockwise rings and negative for clockwise ones.
+// The ring encloses the smaller of two parts of the ellipsoid surface.
+func SignedArea(r orb.Ring) float64 {
+	area, _ := ringArea(r)
+	return area
+}
+
+// ringArea returns signed area and perimeter of the ring.
+func ringArea(r orb.Ring) (float64, float64) {
+	if len(r) < 2 {
+		return 0, 0
+	}
+	var area, areaErr, perimeter float64
+	crossings := 0
+	add := func(p1, p2 orb.Point) {
+		s12, _, _, _, _, S12 := wgs84.inverse(p1.Lat(), p1.Lon(), p2.Lat(), p2.Lon())
+		perimeter += s12
+		// compensated sum, the terms are much greater than the result
+		var e float64
+		area, e = sumx(area, S12)
+		areaErr += e
+		crossings += transit(p1.Lon(), p2.Lon())
+	}
+	for i := 1; i < len(r); i++ {
+		add(r[i-1], r[i])
+	}
+	if !r.Closed() {
+		add(r[len(r)-1], r[0])
+	}
+	area += areaErr
+
+	// area is accumulated in the clockwise sense, reduce it to (-area0/2, area0/2]
+	area0 := 4 * math.Pi * wgs84.c2
+	area = math.Remainder(area, area0)
+	if crossings&1 != 0 {
+		if area < 0 {
+			area += area0 / 2
+		} else {
+			area -= area0 / 2
+		}
+	}
+	area = -area
+	if area > area0/2 {
+		area -= area0
+	} else if area <= -area0/2 {
+		area += area0
+	}
+	return 0 + area, perimeter
+}
+
+// transit returns 1 or -1 if the edge crosses prime meridian in east or west direction, else 0.
+func transit(lon1, lon2 float64) int {
+	lon12, _ := angDiff(lon1, lon2)
+	lon1, lon2 = angNormalize(lon1), angNormalize(lon2)
+	switch {
+	case lon12 > 0 && ((lon1 < 0 && lon2 >= 0) || (lon1 > 0 && lon2 == 0)):
+		return 1
+	case lon12 < 0 && lon1 >= 0 && lon2 < 0:
+		return -1
+	}
+	return 0
+}
+
+// angNormalize returns angle in degrees reduced to (-180, 180].
+func angNormalize(x float64) float64 {
+	y := math.Remainder(x, 360)
+	if math.Abs(y) == 180 {
+		return math.Copysign(180, x)
+	}
+	return y
+}
+
+func pointsLength(pp []orb.Point) float64 {
+	length := 0.
+	for i := 1; i < len(pp); i++ {
+		length += GeodesicDistance(pp[i-1], pp[i])
+	}
+	return length
+}
@@ -0,0 +1,102 @@
+package orbf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestGeodesicDistance(t *testing.T) {
+	tests := []struct {
+		name   string
+		p1, p2 orb.Point
+		dist   float64
+	}{
+		{name: "wellington salamanca", p1: orb.Point{174.81, -41.32}, p2: orb.Point{-5.5, 40.96}, dist: 19959679.267353816},
+		{name: "equator degree", p1: orb.Point{10, 0}, p2: orb.Point{11, 0}, dist: 111319.49079327357},
+		{name: "meridian quadrant", p1: orb.Point{30, 0}, p2: orb.Point{30, 90}, dist: 10001965.729311794},
+		{name: "antimeridian", p1: orb.Point{179.5, 0}, p2: orb.Point{-179.5, 0}, dist: 111319.49079327357},
+		{name: "same point", p1: orb.Point{37.62, 55.75}, p2: orb.Point{37.62, 55.75}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := GeodesicDistance(tt.p1, tt.p2); math.Abs(d-tt.dist) > 1e-6 {
+				t.Errorf("expected distance %v, got %v", tt.dist, d)
+			}
+			if d := GeodesicDistance(tt.p2, tt.p1); math.Abs(d-tt.dist) > 1e-6 {
+				t.Errorf("expected symmetric distance %v, got %v", tt.dist, d)
+			}
+		})
+	}
+}
+
+func TestArea(t *testing.T) {
+	octant := orb.Ring{{0, 0}, {90, 0}, {0, 90}, {0, 0}}
+	polar := orb.Ring{{0, 89}, {90, 89}, {180, 89}, {270, 89}}
+	square := orb.Ring{{37, 55}, {38, 55}, {38, 56}, {37, 56}, {37, 55}}
+	hole := orb.Ring{{37.2, 55.2}, {37.2, 55.8}, {37.8, 55.8}, {37.8, 55.2}, {37.2, 55.2}}
+	area0 := 4 * math.Pi * wgs84.c2
+
+	tests := []struct {
+		name      string
+		geom      orb.Geometry
+		area      float64
+		perimeter float64
+		tolerance float64
+	}{
+		{name: "octant", geom: octant, area: area0 / 8, perimeter: 30022685.63001821, tolerance: 1},
+		{name: "polar square", geom: polar, area: 24952305678, perimeter: 631819.8745, tolerance: 1},
+		{name: "clockwise polar square", geom: orb.Ring{{270, 89}, {180, 89}, {90, 89}, {0, 89}}, area: 24952305678, perimeter: 631819.8745, tolerance: 1},
+		{
+			name:      "polygon with hole",
+			geom:      orb.Polygon{square, hole},
+			area:      Area(square) - Area(hole),
+			perimeter: Perimeter(square) + Perimeter(hole),
+			tolerance: 1e-3,
+		},
+		{
+			name:      "multipolygon",
+			geom:      orb.MultiPolygon{{square}, {octant}},
+			area:      Area(square) + area0/8,
+			perimeter: Perimeter(square) + 30022685.63001821,
+			tolerance: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if a := Area(tt.geom); math.Abs(a-tt.area) > tt.tolerance {
+				t.Errorf("expected area %v, got %v", tt.area, a)
+			}
+			if p := Perimeter(tt.geom); math.Abs(p-tt.perimeter) > 1e-3 {
+				t.Errorf("expected perimeter %v, got %v", tt.perimeter, p)
+			}
+		})
+	}
+
+	if a := SignedArea(square); a <= 0 {
+		t.Errorf("expected positive area of counterclockwise ring, got %v", a)
+	}
+	if a := SignedArea(hole); a >= 0 {
+		t.Errorf("expected negative area of clockwise ring, got %v", a)
+	}
+	// cell of one degree at 55° has area about cos(55°) of the cell at equator
+	equator := Area(orb.Ring{{37, -0.5}, {38, -0.5}, {38, 0.5}, {37, 0.5}, {37, -0.5}})
+	if r := Area(square) / equator; math.Abs(r-math.Cos(55.5*math.Pi/180)) > 0.01 {
+		t.Errorf("unexpected ratio of areas %v", r)
+	}
+}
+
+func TestLength(t *testing.T) {
+	line := orb.LineString{{10, 0}, {11, 0}, {12, 0}}
+	if l := Length(line); math.Abs(l-2*111319.49079327357) > 1e-6 {
+		t.Errorf("unexpected length of line %v", l)
+	}
+	if l := Length(orb.MultiLineString{line, line}); math.Abs(l-4*111319.49079327357) > 1e-6 {
+		t.Errorf("unexpected length of multiline %v", l)
+	}
+	square := orb.Polygon{{{37, 55}, {38, 55}, {38, 56}, {37, 56}, {37, 55}}}
+	if l := Length(square); l != Perimeter(square) {
+		t.Errorf("expected length of polygon equal to perimeter, got %v", l)
+	}
+}
@@ -7,29 +7,29 @@ import (
 )
 
 func Intersects(a, b orb.Geometry) bool {
-	geom1 := projectToMercator(a)
-	if geom1 == nil {
-		return false
-	}
-	geom2 := projectToMercator(b)
-	if geom1 == nil {
+	geom1, geom2 := projectPair(a, b)
+	if geom1 == nil || geom2 == nil {
 		return false
 	}
 	return planar.Intersects(geom1, geom2)
 }
 
 func Contains(a, b orb.Geometry) bool {
-	geom1 := projectToMercator(a)
-	if geom1 == nil {
-		return false
-	}
-	geom2 := projectToMercator(b)
-	if geom1 == nil {
+	geom1, geom2 := projectPair(a, b)
+	if geom1 == nil || geom2 == nil {
 		return false
 	}
 	return planar.Contains(geom1, geom2)
 }
 
+// projectPair prepares two WGS84 geometries to planar predicates.
+// Geometries that cross antimeridian are split, geometries out of
+// the Web Mercator latitude range are compared in plain longitude/latitude.
+func projectPair(a, b orb.Geometry) (orb.Geometry, orb.Geometry) {
+	geom1, geom2, _ := PrepareOverlay(a, b)
+	return geom1, geom2
+}
+
 func projectToMercator(geom orb.Geometry) orb.Geometry {
 	switch g := geom.(type) {
 	case orb.Bound, orb.Point:
@@ -52,3 +52,90 @@ func projectToMercator(geom orb.Geometry) orb.Geometry {
 		return nil
 	}
 }
+
+// Intersection returns area common for WGS84 polygons of a and b.
+func Intersection(a, b orb.Geometry) orb.MultiPolygon {
+	return overlayPolygons(a, b, planar.Intersection)
+}
+
+// Union returns area covered by WGS84 polygons of a or b.
+func Union(a, b orb.Geometry) orb.MultiPolygon {
+	return overlayPolygons(a, b, planar.Union)
+}
+
+// Difference returns area of WGS84 polygons of a that is not covered by polygons of b.
+func Difference(a, b orb.Geometry) orb.MultiPolygon {
+	return overlayPolygons(a, b, planar.Difference)
+}
+
+// SymDifference returns area covered by WGS84 polygons of only one of a and b.
+func SymDifference(a, b orb.Geometry) orb.MultiPolygon {
+	return overlayPolygons(a, b, planar.SymDifference)
+}
+
+// LineIntersection returns parts of WGS84 lines of a that are inside or on the boundary of polygons of b.
+func LineIntersection(a, b orb.Geometry) orb.MultiLineString {
+	return overlayLines(a, b, planar.LineIntersection)
+}
+
+// LineDifference returns parts of WGS84 lines of a that are outside of polygons of b.
+func LineDifference(a, b orb.Geometry) orb.MultiLineString {
+	return overlayLines(a, b, planar.LineDifference)
+}
+
+func overlayPolygons(a, b orb.Geometry, op func(a, b orb.Geometry) orb.MultiPolygon) orb.MultiPolygon {
+	geom1, geom2, report := PrepareOverlay(a, b)
+	if geom1 == nil || geom2 == nil {
+		return nil
+	}
+	out := op(geom1, geom2)
+	if report.ProjectedA {
+		return project.MultiPolygon(out, project.Mercator.ToWGS84)
+	}
+	return out
+}
+
+func overlayLines(a, b orb.Geometry, op func(a, b orb.Geometry) orb.MultiLineString) orb.MultiLineString {
+	geom1, geom2, report := PrepareOverlay(a, b)
+	if geom1 == nil || geom2 == nil {
+		return nil
+	}
+	out := op(geom1, geom2)
+	if report.ProjectedA {
+		return project.MultiLineString(out, project.Mercator.ToWGS84)
+	}
+	return out
+}
+
+// OverlayReport reports how PrepareOverlay changed WGS84 geometries a and b.
+type OverlayReport struct {
+	// SplitA, SplitB report the geometry was split at antimeridian.
+	SplitA, SplitB bool
+	// ProjectedA, ProjectedB report the geometry was projected to Mercator.
+	ProjectedA, ProjectedB bool
+}
+
+// PrepareOverlay prepares WGS84 geometries to planar operations as projectPair and reports what was done.
+// Geometries are projected to Mercator both or none.
+func PrepareOverlay(a, b orb.Geometry) (orb.Geometry, orb.Geometry, OverlayReport) {
+	var report OverlayReport
+	a, report.SplitA = splitOverlay(a)
+	b, report.SplitB = splitOverlay(b)
+	if a == nil || b == nil {
+		return nil, nil, report
+	}
+	if IsPolar(a) || IsPolar(b) {
+		return a, b, report
+	}
+	a, b = projectToMercator(a), projectToMercator(b)
+	report.ProjectedA, report.ProjectedB = a != nil, b != nil
+	return a, b, report
+}
+
+// splitOverlay splits geometry at antimeridian and reports it was changed.
+func splitOverlay(geom orb.Geometry) (orb.Geometry, bool) {
+	if !CrossesAntimeridian(geom) {
+		return geom, false
+	}
+	return SplitAntimeridian(geom), true
+}
@@ -0,0 +1,258 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// CapStyle is a style of buffer ends of lines and points.
+type CapStyle int
+
+const (
+	// CapRound ends buffer with a half circle.
+	CapRound CapStyle = iota
+	// CapFlat ends buffer at the end of line, points are not buffered.
+	CapFlat
+	// CapSquare ends buffer with a half square extended by distance beyond the end of line.
+	CapSquare
+)
+
+// JoinStyle is a style of buffer corners at line vertices.
+type JoinStyle int
+
+const (
+	// JoinRound joins offset segments with an arc.
+	JoinRound JoinStyle = iota
+	// JoinMitre extends offset segments to their intersection.
+	JoinMitre
+	// JoinBevel joins ends of offset segments with a straight segment.
+	JoinBevel
+)
+
+const (
+	defaultQuadrantSegments = 8
+	defaultMitreLimit       = 5
+)
+
+// BufferOptions are options of Buffer, zero value is round caps and joins.
+type BufferOptions struct {
+	Cap  CapStyle
+	Join JoinStyle
+	// QuadrantSegments is a number of segments approximating a quarter of circle, default is 8.
+	QuadrantSegments int
+	// MitreLimit is a maximal ratio of mitre length to buffer distance,
+	// longer mitres are beveled, default is 5.
+	MitreLimit float64
+}
+
+// Buffer returns area within distance of geometry.
+// Negative distance shrinks polygons, points and lines with not positive distance have empty buffer.
+func Buffer(geom orb.Geometry, distance float64, opts BufferOptions) orb.MultiPolygon {
+	if opts.QuadrantSegments <= 0 {
+		opts.QuadrantSegments = defaultQuadrantSegments
+	}
+	if opts.MitreLimit <= 0 {
+		opts.MitreLimit = defaultMitreLimit
+	}
+	polygons, parts := areaGeometry(geom).polygons, newRelateGeometry(geom)
+	if distance == 0 || math.IsNaN(distance) {
+		return cloneMultiPolygon(polygons)
+	}
+	b := &bufferBuilder{opts: opts, d: math.Abs(distance)}
+	for _, p := range polygons {
+		for _, r := range p {
+			b.addRing(r)
+		}
+	}
+	if distance < 0 {
+		if len(polygons) == 0 {
+			return nil
+		}
+		return Difference(orb.MultiPolygon(polygons), UnaryUnion(orb.MultiPolygon(b.parts)))
+	}
+	for _, p := range parts.points {
+		b.addPoint(p)
+	}
+	for _, l := range parts.lines {
+		b.addLine(l)
+	}
+	b.parts = append(b.parts, polygons...)
+	return UnaryUnion(orb.MultiPolygon(b.parts))
+}
+
+// bufferBuilder collects simple polygons which union is a buffer:
+// rectangles along segments, joins at vertices and caps at the ends of lines.
+type bufferBuilder struct {
+	opts  BufferOptions
+	d     float64
+	parts []orb.Polygon
+}
+
+// addPoint adds buffer of a single point.
+func (b *bufferBuilder) addPoint(p orb.Point) {
+	switch b.opts.Cap {
+	case CapRound:
+		ring := b.arc(p, 0, 2*math.Pi)
+		b.parts = append(b.parts, orb.Polygon{append(ring, ring[0])})
+	case CapSquare:
+		b.parts = append(b.parts, orb.Bound{
+			Min: orb.Point{p[0] - b.d, p[1] - b.d},
+			Max: orb.Point{p[0] + b.d, p[1] + b.d},
+		}.ToPolygon())
+	}
+}
+
+// addLine adds buffer of the line with caps at the ends, closed line is buffered as a ring.
+func (b *bufferBuilder) addLine(l orb.LineString) {
+	pp := dedupPoints(l)
+	if len(pp) > 3 && pp[0] == pp[len(pp)-1] {
+		b.addRing(orb.Ring(pp))
+		return
+	}
+	if len(pp) == 1 {
+		b.addPoint(pp[0])
+		return
+	}
+	b.addSegments(pp)
+	for i := 2; i < len(pp); i++ {
+		b.addJoin(pp[i-2], pp[i-1], pp[i])
+	}
+	b.addCap(pp[1], pp[0])
+	b.addCap(pp[len(pp)-2], pp[len(pp)-1])
+}
+
+// addRing adds buffer of the closed ring, buffer has joins at all vertices.
+func (b *bufferBuilder) addRing(r orb.Ring) {
+	pp := dedupPoints(orb.LineString(r))
+	if len(pp) > 1 && pp[0] == pp[len(pp)-1] {
+		pp = pp[:len(pp)-1]
+	}
+	if len(pp) < 2 {
+		if len(pp) == 1 {
+			b.addPoint(pp[0])
+		}
+		return
+	}
+	pp = append(pp, pp[0], pp[1])
+	b.addSegments(pp[:len(pp)-1])
+	for i := 2; i < len(pp); i++ {
+		b.addJoin(pp[i-2], pp[i-1], pp[i])
+	}
+}
+
+// addSegments adds rectangles with width of two distances along the segments.
+func (b *bufferBuilder) addSegments(pp []orb.Point) {
+	for i := 1; i < len(pp); i++ {
+		p, q := pp[i-1], pp[i]
+		n := b.normal(p, q)
+		b.parts = append(b.parts, orb.Polygon{{
+			{p[0] + n[0], p[1] + n[1]},
+			{p[0] - n[0], p[1] - n[1]},
+			{q[0] - n[0], q[1] - n[1]},
+			{q[0] + n[0], q[1] + n[1]},
+			{p[0] + n[0], p[1] + n[1]},
+		}})
+	}
+}
+
+// addJoin adds join at vertex v between segments (p, v) and (v, q),
+// the join fills gap between rectangles on the outer side of the turn.
+func (b *bufferBuilder) addJoin(p, v, q orb.Point) {
+	n1, n2 := b.normal(p, v), b.normal(v, q)
+	d1 := orb.Point{v[0] - p[0], v[1] - p[1]}
+	d2 := orb.Point{q[0] - v[0], q[1] - v[1]}
+	turn := math.Atan2(d1[0]*d2[1]-d1[1]*d2[0], d1[0]*d2[0]+d1[1]*d2[1])
+	if math.Abs(turn) < 1e-12 {
+		return
+	}
+	if turn > 0 {
+		// left turn, the gap is on the right side
+		n1, n2 = orb.Point{-n1[0], -n1[1]}, orb.Point{-n2[0], -n2[1]}
+	}
+	a := orb.Point{v[0] + n1[0], v[1] + n1[1]}
+	c := orb.Point{v[0] + n2[0], v[1] + n2[1]}
+	switch b.opts.Join {
+	case JoinRound:
+		b.addFan(v, a, c, turn)
+		return
+	case JoinMitre:
+		// mitre vertex is on the bisector of normals
+		cos := (n1[0]*n2[0] + n1[1]*n2[1]) / (b.d * b.d)
+		if 1+cos > 1e-12 && math.Sqrt(2/(1+cos)) <= b.opts.MitreLimit {
+			k := 1 / (1 + cos)
+			m := orb.Point{v[0] + (n1[0]+n2[0])*k, v[1] + (n1[1]+n2[1])*k}
+			b.parts = append(b.parts, orb.Polygon{{v, a, m, c, v}})
+			return
+		}
+	}
+	if math.Abs(math.Abs(turn)-math.Pi) < 1e-12 {
+		// line turns back, bevel is degenerated
+		return
+	}
+	b.parts = append(b.parts, orb.Polygon{{v, a, c, v}})
+}
+
+// addCap adds cap at the end e of the segment (p, e).
+func (b *bufferBuilder) addCap(p, e orb.Point) {
+	n := b.normal(p, e)
+	switch b.opts.Cap {
+	case CapRound:
+		// from the right side to the left side around the end
+		b.addFan(e, orb.Point{e[0] - n[0], e[1] - n[1]}, orb.Point{e[0] + n[0], e[1] + n[1]}, math.Pi)
+	case CapSquare:
+		dx, dy := n[1], -n[0]
+		b.parts = append(b.parts, orb.Polygon{{
+			{e[0] - n[0], e[1] - n[1]},
+			{e[0] - n[0] + dx, e[1] - n[1] + dy},
+			{e[0] + n[0] + dx, e[1] + n[1] + dy},
+			{e[0] + n[0], e[1] + n[1]},
+			{e[0] - n[0], e[1] - n[1]},
+		}})
+	}
+}
+
+// addFan adds circle sector with center c from point from to point to by sweep angle,
+// the ends of arc are exactly the given points, so the sector shares edges with segment rectangles.
+func (b *bufferBuilder) addFan(c, from, to orb.Point, sweep float64) {
+	arc := b.arc(c, math.Atan2(from[1]-c[1], from[0]-c[0]), sweep)
+	arc[0], arc[len(arc)-1] = from, to
+	ring := orb.Ring{c}
+	ring = append(ring, arc...)
+	b.parts = append(b.parts, orb.Polygon{append(ring, c)})
+}
+
+// arc returns points of arc with center c from start angle to start+sweep, both ends are included.
+// Full circle doesn't include end point.
+func (b *bufferBuilder) arc(c orb.Point, start, sweep float64) []orb.Point {
+	n := int(math.Ceil(math.Abs(sweep) / (math.Pi / 2 / float64(b.opts.QuadrantSegments))))
+	n = max(n, 1)
+	full := math.Abs(sweep) >= 2*math.Pi
+	pp := make([]orb.Point, 0, n+1)
+	for i := 0; i <= n; i++ {
+		if full && i == n {
+			break
+		}
+		a := start + sweep*float64(i)/float64(n)
+		pp = append(pp, orb.Point{c[0] + b.d*math.Cos(a), c[1] + b.d*math.Sin(a)})
+	}
+	return pp
+}
+
+// normal returns left normal of segment (p, q) with length of buffer distance.
+func (b *bufferBuilder) normal(p, q orb.Point) orb.Point {
+	dx, dy := q[0]-p[0], q[1]-p[1]
+	l := math.Hypot(dx, dy)
+	return orb.Point{-dy / l * b.d, dx / l * b.d}
+}
+
+// dedupPoints returns points without consecutive duplicates.
+func dedupPoints(pp []orb.Point) []orb.Point {
+	out := make([]orb.Point, 0, len(pp))
+	for _, p := range pp {
+		if len(out) == 0 || out[len(out)-1] != p {
+			out = append(out, p)
+		}
+	}
+	return out
+}
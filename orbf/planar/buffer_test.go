@@ -0,0 +1,123 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestBuffer(t *testing.T) {
+	// area of a circle approximated by 32 segments
+	circle := 16 * math.Sin(2*math.Pi/32)
+	line := orb.LineString{{0, 0}, {10, 0}}
+	corner := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+	square := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	holed := orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{3, 3}, {7, 3}, {7, 7}, {3, 7}, {3, 3}},
+	}
+
+	tests := []struct {
+		name     string
+		geom     orb.Geometry
+		distance float64
+		opts     BufferOptions
+		area     float64
+		polygons int
+		holes    int
+	}{
+		{name: "point round", geom: orb.Point{1, 1}, distance: 1, area: circle, polygons: 1},
+		{name: "point square", geom: orb.Point{1, 1}, distance: 1, opts: BufferOptions{Cap: CapSquare}, area: 4, polygons: 1},
+		{name: "point flat", geom: orb.Point{1, 1}, distance: 1, opts: BufferOptions{Cap: CapFlat}},
+		{name: "line round", geom: line, distance: 1, area: 20 + circle, polygons: 1},
+		{name: "line flat", geom: line, distance: 1, opts: BufferOptions{Cap: CapFlat}, area: 20, polygons: 1},
+		{name: "line square", geom: line, distance: 1, opts: BufferOptions{Cap: CapSquare}, area: 24, polygons: 1},
+		{name: "line negative", geom: line, distance: -1},
+		{name: "corner round join", geom: corner, distance: 1, opts: BufferOptions{Cap: CapFlat}, area: 39 + circle/4, polygons: 1},
+		{name: "corner mitre join", geom: corner, distance: 1, opts: BufferOptions{Cap: CapFlat, Join: JoinMitre}, area: 40, polygons: 1},
+		{name: "corner bevel join", geom: corner, distance: 1, opts: BufferOptions{Cap: CapFlat, Join: JoinBevel}, area: 39.5, polygons: 1},
+		{
+			name:     "corner mitre limit",
+			geom:     corner,
+			distance: 1,
+			opts:     BufferOptions{Cap: CapFlat, Join: JoinMitre, MitreLimit: 1.2},
+			area:     39.5,
+			polygons: 1,
+		},
+		{
+			name:     "closed line",
+			geom:     orb.LineString(square[0]),
+			distance: 1,
+			opts:     BufferOptions{Join: JoinMitre},
+			area:     144 - 64,
+			polygons: 1,
+			holes:    1,
+		},
+		{name: "polygon round", geom: square, distance: 1, area: 140 + circle, polygons: 1},
+		{name: "polygon mitre", geom: square, distance: 1, opts: BufferOptions{Join: JoinMitre}, area: 144, polygons: 1},
+		{name: "polygon zero", geom: square, area: 100, polygons: 1},
+		{name: "polygon negative", geom: square, distance: -1, area: 64, polygons: 1},
+		{name: "polygon collapsed", geom: square, distance: -5},
+		{name: "hole grows", geom: holed, distance: -1, area: 64 - 32 - circle, polygons: 1, holes: 1},
+		{name: "hole shrinks", geom: holed, distance: 1, opts: BufferOptions{Join: JoinMitre}, area: 144 - 4, polygons: 1, holes: 1},
+		{name: "hole closed", geom: holed, distance: 2, opts: BufferOptions{Join: JoinMitre}, area: 196, polygons: 1},
+		{
+			name:     "separated points",
+			geom:     orb.MultiPoint{{0, 0}, {5, 0}},
+			distance: 1,
+			opts:     BufferOptions{Cap: CapSquare},
+			area:     8,
+			polygons: 2,
+		},
+		{
+			name:     "overlapped points",
+			geom:     orb.MultiPoint{{0, 0}, {1, 0}},
+			distance: 1,
+			opts:     BufferOptions{Cap: CapSquare},
+			area:     6,
+			polygons: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Buffer(tt.geom, tt.distance, tt.opts)
+			if area := planar.Area(got); math.Abs(area-tt.area) > 1e-9 {
+				t.Errorf("expected area %v, got %v", tt.area, area)
+			}
+			if len(got) != tt.polygons {
+				t.Fatalf("expected %d polygons, got %d: %v", tt.polygons, len(got), got)
+			}
+			holes := 0
+			for _, p := range got {
+				holes += len(p) - 1
+			}
+			if holes != tt.holes {
+				t.Errorf("expected %d holes, got %d", tt.holes, holes)
+			}
+		})
+	}
+}
+
+func TestBufferWithinDistance(t *testing.T) {
+	line := orb.LineString{{0, 0}, {10, 0}, {10, 10}, {20, 5}}
+	buf := Buffer(line, 2, BufferOptions{})
+	for _, tt := range []struct {
+		p      orb.Point
+		within bool
+	}{
+		{p: orb.Point{5, 1.9}, within: true},
+		{p: orb.Point{5, -1.9}, within: true},
+		{p: orb.Point{-1.9, 0}, within: true},
+		{p: orb.Point{11.4, -1.4}, within: true},
+		{p: orb.Point{5, 2.1}},
+		{p: orb.Point{-2.1, 0}},
+		{p: orb.Point{11.5, -1.5}},
+		{p: orb.Point{15, 2}},
+	} {
+		if got := Covers(buf, tt.p); got != tt.within {
+			t.Errorf("point %v: expected within %v, got %v", tt.p, tt.within, got)
+		}
+	}
+}
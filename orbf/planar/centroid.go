@@ -0,0 +1,323 @@
+package planar
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// Centroid returns centroid of given geometry as orb.Point or nil for empty geometry.
+// Centroid depends on the highest dimension of geometry parts that have non-zero measure:
+// polygons are weighted by area, lines by length and points are averaged,
+// so for collections parts of lower dimension are ignored. Polygons of zero area are
+// weighted as lines by their boundary and lines of zero length as points.
+// Centroid of concave polygon can be outside of it, see PointOnSurface.
+func Centroid(geom orb.Geometry) orb.Geometry {
+	var c centroidSum
+	c.add(geom)
+	if p, ok := c.centroid(); ok {
+		return p
+	}
+	return nil
+}
+
+// centroidSum accumulates weighted coordinates of geometry parts by dimension.
+type centroidSum struct {
+	area, areaX, areaY   float64
+	length, lineX, lineY float64
+	points               int
+	pointX, pointY       float64
+}
+
+func (c *centroidSum) centroid() (orb.Point, bool) {
+	switch {
+	case c.area > 0:
+		return orb.Point{c.areaX / c.area, c.areaY / c.area}, true
+	case c.length > 0:
+		return orb.Point{c.lineX / c.length, c.lineY / c.length}, true
+	case c.points > 0:
+		return orb.Point{c.pointX / float64(c.points), c.pointY / float64(c.points)}, true
+	}
+	return orb.Point{}, false
+}
+
+func (c *centroidSum) add(geom orb.Geometry) {
+	switch g := geom.(type) {
+	case orb.Point:
+		c.addPoint(g)
+	case orb.MultiPoint:
+		for _, p := range g {
+			c.addPoint(p)
+		}
+	case orb.LineString:
+		c.addLine(g)
+	case orb.MultiLineString:
+		for _, l := range g {
+			c.addLine(l)
+		}
+	case orb.Ring:
+		c.add(orb.Polygon{g})
+	case orb.Polygon:
+		c.addPolygon(g)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			c.addPolygon(p)
+		}
+	case orb.Bound:
+		c.addPolygon(g.ToPolygon())
+	case orb.Collection:
+		for _, g := range g {
+			c.add(g)
+		}
+	}
+}
+
+func (c *centroidSum) addPoint(p orb.Point) {
+	c.points++
+	c.pointX += p[0]
+	c.pointY += p[1]
+}
+
+func (c *centroidSum) addLine(pp []orb.Point) {
+	if len(pp) == 0 {
+		return
+	}
+	length := 0.
+	for i := 1; i < len(pp); i++ {
+		l := math.Hypot(pp[i][0]-pp[i-1][0], pp[i][1]-pp[i-1][1])
+		length += l
+		c.lineX += l * (pp[i][0] + pp[i-1][0]) / 2
+		c.lineY += l * (pp[i][1] + pp[i-1][1]) / 2
+	}
+	c.length += length
+	if length == 0 {
+		c.addPoint(pp[0])
+	}
+}
+
+// addPolygon adds area of the shell and subtracts areas of holes regardless of rings orientation.
+func (c *centroidSum) addPolygon(poly orb.Polygon) {
+	if len(poly) == 0 {
+		return
+	}
+	var area, x, y float64
+	for i, r := range poly {
+		a, rx, ry := ringCentroidArea(r)
+		if i > 0 {
+			a = -a
+		}
+		area += a
+		x += a * rx
+		y += a * ry
+	}
+	if area <= 0 {
+		for _, r := range poly {
+			c.addLine(r)
+		}
+		return
+	}
+	c.area += area
+	c.areaX += x
+	c.areaY += y
+}
+
+// ringCentroidArea returns area and centroid of the ring, not closed ring is closed.
+func ringCentroidArea(r orb.Ring) (float64, float64, float64) {
+	if len(r) < 3 {
+		return 0, 0, 0
+	}
+	// coordinates relative to the first point reduce rounding errors
+	o := r[0]
+	var area, x, y float64
+	for i := 1; i < len(r); i++ {
+		j := (i + 1) % len(r)
+		x1, y1 := r[i][0]-o[0], r[i][1]-o[1]
+		x2, y2 := r[j][0]-o[0], r[j][1]-o[1]
+		cross := x1*y2 - x2*y1
+		area += cross
+		x += (x1 + x2) * cross
+		y += (y1 + y2) * cross
+	}
+	if area == 0 {
+		return 0, 0, 0
+	}
+	return math.Abs(area) / 2, x/(3*area) + o[0], y/(3*area) + o[1]
+}
+
+// PointOnSurface returns orb.Point that lies in the interior of polygons of geometry or on lines
+// if geometry has no polygons with non-zero area or nil for empty geometry.
+// For polygons the point is the middle of the widest interior interval of horizontal line
+// through the middle of polygon, for lines it is the interior vertex nearest to the centroid,
+// for points it is the point nearest to the centroid.
+func PointOnSurface(geom orb.Geometry) orb.Geometry {
+	var c centroidSum
+	c.add(geom)
+	centroid, ok := c.centroid()
+	if !ok {
+		return nil
+	}
+	s := surfacePoint{centroid: centroid, dist: math.Inf(1), width: -1}
+	switch {
+	case c.area > 0:
+		s.addAreas(geom)
+	case c.length > 0:
+		s.addLines(geom, false)
+		if !s.found {
+			s.addLines(geom, true)
+		}
+	default:
+		s.addPoints(geom)
+	}
+	if !s.found {
+		return centroid
+	}
+	return s.point
+}
+
+// surfacePoint selects the point on surface of geometry parts.
+type surfacePoint struct {
+	centroid orb.Point
+	point    orb.Point
+	found    bool
+	// dist is the distance to the centroid of the point of lines and points
+	dist float64
+	// width is the width of interior interval of the point of polygons
+	width float64
+}
+
+func (s *surfacePoint) addAreas(geom orb.Geometry) {
+	switch g := geom.(type) {
+	case orb.Ring:
+		s.addPolygon(orb.Polygon{g})
+	case orb.Polygon:
+		s.addPolygon(g)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			s.addPolygon(p)
+		}
+	case orb.Bound:
+		s.addPolygon(g.ToPolygon())
+	case orb.Collection:
+		for _, g := range g {
+			s.addAreas(g)
+		}
+	}
+}
+
+// addPolygon intersects the polygon by the horizontal line between vertices nearest
+// to the middle of the polygon, so the line doesn't pass through vertices.
+func (s *surfacePoint) addPolygon(poly orb.Polygon) {
+	if len(poly) == 0 || len(poly[0]) < 3 {
+		return
+	}
+	b := poly[0].Bound()
+	mid := (b.Min[1] + b.Max[1]) / 2
+	lo, hi := b.Min[1], b.Max[1]
+	for _, r := range poly {
+		for _, p := range r {
+			if p[1] <= mid {
+				lo = max(lo, p[1])
+			} else {
+				hi = min(hi, p[1])
+			}
+		}
+	}
+	y := (lo + hi) / 2
+	var xs []float64
+	for _, r := range poly {
+		for i := range r {
+			p, q := r[i], r[(i+1)%len(r)]
+			if (p[1] > y) != (q[1] > y) {
+				xs = append(xs, p[0]+(y-p[1])*(q[0]-p[0])/(q[1]-p[1]))
+			}
+		}
+	}
+	sort.Float64s(xs)
+	for i := 1; i < len(xs); i += 2 {
+		if w := xs[i] - xs[i-1]; w > s.width {
+			s.width, s.point, s.found = w, orb.Point{(xs[i] + xs[i-1]) / 2, y}, true
+		}
+	}
+}
+
+// addLines selects interior vertices of lines or end points of lines if ends is set.
+func (s *surfacePoint) addLines(geom orb.Geometry, ends bool) {
+	switch g := geom.(type) {
+	case orb.LineString:
+		s.addLine(g, ends)
+	case orb.MultiLineString:
+		for _, l := range g {
+			s.addLine(l, ends)
+		}
+	case orb.Ring:
+		s.addLine(orb.LineString(g), ends)
+	case orb.Polygon:
+		for _, r := range g {
+			s.addLine(orb.LineString(r), ends)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			s.addLines(p, ends)
+		}
+	case orb.Bound:
+		s.addLines(g.ToPolygon(), ends)
+	case orb.Collection:
+		for _, g := range g {
+			s.addLines(g, ends)
+		}
+	}
+}
+
+func (s *surfacePoint) addLine(l orb.LineString, ends bool) {
+	switch {
+	case len(l) == 0:
+	case ends:
+		s.addNearest(l[0])
+		s.addNearest(l[len(l)-1])
+	case len(l) > 2:
+		for _, p := range l[1 : len(l)-1] {
+			s.addNearest(p)
+		}
+	}
+}
+
+func (s *surfacePoint) addPoints(geom orb.Geometry) {
+	switch g := geom.(type) {
+	case orb.Point:
+		s.addNearest(g)
+	case orb.MultiPoint:
+		for _, p := range g {
+			s.addNearest(p)
+		}
+	case orb.LineString:
+		s.addPoints(orb.MultiPoint(g))
+	case orb.MultiLineString:
+		for _, l := range g {
+			s.addPoints(orb.MultiPoint(l))
+		}
+	case orb.Ring:
+		s.addPoints(orb.MultiPoint(g))
+	case orb.Polygon:
+		for _, r := range g {
+			s.addPoints(orb.MultiPoint(r))
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			s.addPoints(p)
+		}
+	case orb.Bound:
+		s.addPoints(g.Min)
+	case orb.Collection:
+		for _, g := range g {
+			s.addPoints(g)
+		}
+	}
+}
+
+// addNearest selects the point nearest to the centroid.
+func (s *surfacePoint) addNearest(p orb.Point) {
+	if d := math.Hypot(p[0]-s.centroid[0], p[1]-s.centroid[1]); d < s.dist {
+		s.point, s.dist, s.found = p, d, true
+	}
+}
@@ -0,0 +1,103 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestCentroid(t *testing.T) {
+	square := orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	tests := []struct {
+		name     string
+		geom     orb.Geometry
+		expected orb.Geometry
+	}{
+		{name: "point", geom: orb.Point{1, 2}, expected: orb.Point{1, 2}},
+		{name: "multipoint", geom: orb.MultiPoint{{0, 0}, {2, 0}, {4, 6}}, expected: orb.Point{2, 2}},
+		{name: "line", geom: orb.LineString{{0, 0}, {10, 0}, {10, 1}, {10, 2}}, expected: orb.Point{70.0 / 12, 2.0 / 12}},
+		{name: "multiline", geom: orb.MultiLineString{{{0, 0}, {2, 0}}, {{10, 0}, {10, 6}}}, expected: orb.Point{7.75, 2.25}},
+		{name: "collapsed line", geom: orb.LineString{{1, 1}, {1, 1}}, expected: orb.Point{1, 1}},
+		{name: "polygon", geom: orb.Polygon{square}, expected: orb.Point{5, 5}},
+		{name: "polygon with hole", geom: orb.Polygon{square, {{5, 0}, {10, 0}, {10, 10}, {5, 10}, {5, 0}}}, expected: orb.Point{2.5, 5}},
+		{
+			name:     "multipolygon",
+			geom:     orb.MultiPolygon{{square}, {{{20, 0}, {20, 10}, {40, 10}, {40, 0}, {20, 0}}}},
+			expected: orb.Point{65.0 / 3, 5},
+		},
+		{name: "collapsed polygon", geom: orb.Polygon{{{0, 0}, {10, 0}, {0, 0}}}, expected: orb.Point{5, 0}},
+		{name: "bound", geom: orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{2, 4}}, expected: orb.Point{1, 2}},
+		{
+			name:     "collection of polygon and lower dimensions",
+			geom:     orb.Collection{orb.Point{100, 100}, orb.LineString{{50, 50}, {60, 60}}, orb.Polygon{square}},
+			expected: orb.Point{5, 5},
+		},
+		{
+			name:     "collection of lines and points",
+			geom:     orb.Collection{orb.Point{100, 100}, orb.LineString{{0, 0}, {2, 0}}},
+			expected: orb.Point{1, 0},
+		},
+		{name: "empty", geom: orb.MultiPolygon{}, expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Centroid(tt.geom)
+			if tt.expected == nil {
+				if got != nil {
+					t.Errorf("expected nil, got %v", got)
+				}
+				return
+			}
+			p, ok := got.(orb.Point)
+			e := tt.expected.(orb.Point)
+			if !ok || math.Abs(p[0]-e[0]) > 1e-9 || math.Abs(p[1]-e[1]) > 1e-9 {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPointOnSurface(t *testing.T) {
+	// U-shaped polygon, its centroid is outside
+	u := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {8, 10}, {8, 2}, {2, 2}, {2, 10}, {0, 10}, {0, 0}}}
+	if c := Centroid(u).(orb.Point); polygonLocation(u, c, 0) != Exterior {
+		t.Fatalf("expected centroid outside, got %v", c)
+	}
+	holed := orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{1, 1}, {9, 1}, {9, 9}, {1, 9}, {1, 1}},
+	}
+	triangle := orb.Polygon{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}
+	for _, tt := range []struct {
+		geom, surface orb.Geometry
+	}{
+		{geom: u, surface: u},
+		{geom: holed, surface: holed},
+		{geom: orb.MultiPolygon{triangle, u}, surface: orb.MultiPolygon{triangle, u}},
+		{geom: orb.Collection{orb.Point{50, 50}, u}, surface: u},
+	} {
+		p, ok := PointOnSurface(tt.geom).(orb.Point)
+		if !ok || !Contains(tt.surface, p) {
+			t.Errorf("expected point inside %v, got %v", tt.geom, p)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		geom     orb.Geometry
+		expected orb.Geometry
+	}{
+		{name: "line", geom: orb.LineString{{0, 0}, {4, 1}, {5, 5}, {10, 0}}, expected: orb.Point{4, 1}},
+		{name: "segment", geom: orb.LineString{{0, 0}, {10, 0}}, expected: orb.Point{0, 0}},
+		{name: "points", geom: orb.MultiPoint{{0, 0}, {3, 0}, {10, 0}}, expected: orb.Point{3, 0}},
+		{name: "empty", geom: orb.Collection{}, expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PointOnSurface(tt.geom); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/VGSML/geobin/orbf/vector"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+// Distance returns minimal euclidean distance between geometries, 0 if geometries intersect.
+// Distance to empty geometry is +Inf.
+func Distance(a, b orb.Geometry) float64 {
+	d, _, _ := ClosestPoints(a, b)
+	return d
+}
+
+// ClosestPoints returns minimal distance between geometries and the closest points of a and b.
+// For intersecting geometries distance is 0 and both points are the same common point.
+func ClosestPoints(a, b orb.Geometry) (float64, orb.Point, orb.Point) {
+	ga, gb := newRelateGeometry(a), newRelateGeometry(b)
+	if ga.dim == DimFalse || gb.dim == DimFalse {
+		return math.Inf(1), orb.Point{}, orb.Point{}
+	}
+	tol := relateTolerance(ga.bound, gb.bound)
+	// geometry inside of area of the other geometry has no crossing edges
+	if p, ok := ga.pointIn(gb, tol); ok {
+		return 0, p, p
+	}
+	if p, ok := gb.pointIn(ga, tol); ok {
+		return 0, p, p
+	}
+	best := math.Inf(1)
+	var pa, pb orb.Point
+	sb := gb.distanceSegments()
+	for _, s1 := range ga.distanceSegments() {
+		b1 := orb.MultiPoint{s1[0], s1[1]}.Bound()
+		for _, s2 := range sb {
+			if boundDistance(b1, orb.MultiPoint{s2[0], s2[1]}.Bound()) >= best {
+				continue
+			}
+			d, p1, p2 := segmentsClosestPoints(s1, s2)
+			if d < best {
+				best, pa, pb = d, p1, p2
+				if d == 0 {
+					return 0, pa, pb
+				}
+			}
+		}
+	}
+	return best, pa, pb
+}
+
+// pointIn returns a point of any part of geometry that is not in the exterior of the other geometry.
+func (g *relateGeometry) pointIn(other *relateGeometry, tol float64) (orb.Point, bool) {
+	for _, p := range g.points {
+		if other.locate(p, tol) != Exterior {
+			return p, true
+		}
+	}
+	for _, l := range g.lines {
+		if other.locate(l[0], tol) != Exterior {
+			return l[0], true
+		}
+	}
+	for _, p := range g.polygons {
+		if other.locate(p[0][0], tol) != Exterior {
+			return p[0][0], true
+		}
+	}
+	return orb.Point{}, false
+}
+
+// distanceSegments returns segments of lines and rings of geometry,
+// points are returned as segments with equal ends.
+func (g *relateGeometry) distanceSegments() [][2]orb.Point {
+	var out [][2]orb.Point
+	for _, p := range g.points {
+		out = append(out, [2]orb.Point{p, p})
+	}
+	addLine := func(l []orb.Point) {
+		if len(l) == 1 {
+			out = append(out, [2]orb.Point{l[0], l[0]})
+		}
+		for i := 1; i < len(l); i++ {
+			out = append(out, [2]orb.Point{l[i-1], l[i]})
+		}
+	}
+	for _, l := range g.lines {
+		addLine(l)
+	}
+	for _, p := range g.polygons {
+		for _, r := range p {
+			addLine(r)
+		}
+	}
+	return out
+}
+
+// segmentsClosestPoints returns distance and the closest points of two segments.
+func segmentsClosestPoints(s1, s2 [2]orb.Point) (float64, orb.Point, orb.Point) {
+	if s1[0] != s1[1] && s2[0] != s2[1] {
+		if p, ok := vector.IntersectionPoint(s1[0], s1[1], s2[0], s2[1]); ok {
+			return 0, p, p
+		}
+	}
+	// not crossing segments have the closest point at one of the ends
+	best := math.Inf(1)
+	var pa, pb orb.Point
+	for _, e := range s1 {
+		p := segmentClosestPoint(s2[0], s2[1], e)
+		if d := planar.Distance(e, p); d < best {
+			best, pa, pb = d, e, p
+		}
+	}
+	for _, e := range s2 {
+		p := segmentClosestPoint(s1[0], s1[1], e)
+		if d := planar.Distance(e, p); d < best {
+			best, pa, pb = d, p, e
+		}
+	}
+	return best, pa, pb
+}
+
+// segmentClosestPoint returns the point of segment (p1, p2) closest to p, segment can be degenerated.
+func segmentClosestPoint(p1, p2, p orb.Point) orb.Point {
+	if p1 == p2 {
+		return p1
+	}
+	return vector.ClosestPointOnLineSegment(p1, p2, p)
+}
+
+// boundDistance returns distance between bounds, 0 if bounds intersect.
+func boundDistance(a, b orb.Bound) float64 {
+	dx := max(a.Min[0]-b.Max[0], b.Min[0]-a.Max[0], 0)
+	dy := max(a.Min[1]-b.Max[1], b.Min[1]-a.Max[1], 0)
+	return math.Hypot(dx, dy)
+}
@@ -0,0 +1,74 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestClosestPoints(t *testing.T) {
+	square := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	holed := orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}},
+	}
+
+	tests := []struct {
+		name   string
+		a, b   orb.Geometry
+		dist   float64
+		pa, pb orb.Point
+	}{
+		{name: "points", a: orb.Point{0, 0}, b: orb.Point{3, 4}, dist: 5, pa: orb.Point{0, 0}, pb: orb.Point{3, 4}},
+		{name: "equal points", a: orb.Point{1, 1}, b: orb.MultiPoint{{5, 5}, {1, 1}}, pa: orb.Point{1, 1}, pb: orb.Point{1, 1}},
+		{name: "point and line", a: orb.Point{5, 3}, b: orb.LineString{{0, 0}, {10, 0}}, dist: 3, pa: orb.Point{5, 3}, pb: orb.Point{5, 0}},
+		{name: "line and point", a: orb.LineString{{0, 0}, {10, 0}}, b: orb.Point{12, 0}, dist: 2, pa: orb.Point{10, 0}, pb: orb.Point{12, 0}},
+		{name: "parallel lines", a: orb.LineString{{0, 0}, {10, 0}}, b: orb.LineString{{5, 2}, {15, 2}}, dist: 2, pa: orb.Point{10, 0}, pb: orb.Point{10, 2}},
+		{name: "crossing lines", a: orb.LineString{{0, 0}, {10, 10}}, b: orb.LineString{{0, 10}, {10, 0}}, pa: orb.Point{5, 5}, pb: orb.Point{5, 5}},
+		{name: "point in polygon", a: square, b: orb.Point{5, 5}, pa: orb.Point{5, 5}, pb: orb.Point{5, 5}},
+		{name: "point in hole", a: holed, b: orb.Point{5, 4}, dist: 2, pa: orb.Point{5, 2}, pb: orb.Point{5, 4}},
+		{name: "line in polygon", a: orb.LineString{{1, 1}, {2, 2}}, b: square, pa: orb.Point{1, 1}, pb: orb.Point{1, 1}},
+		{
+			name: "polygon in hole",
+			a:    orb.Polygon{{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}},
+			b:    holed,
+			dist: 2,
+			pa:   orb.Point{4, 4},
+			pb:   orb.Point{4, 2},
+		},
+		{
+			name: "polygons",
+			a:    square,
+			b:    orb.MultiPolygon{{{{13, 14}, {20, 14}, {20, 20}, {13, 14}}}, {{{-10, -10}, {-5, -10}, {-5, -5}, {-10, -10}}}},
+			dist: 5,
+			pa:   orb.Point{10, 10},
+			pb:   orb.Point{13, 14},
+		},
+		{
+			name: "collection",
+			a:    orb.Collection{orb.Point{20, 20}, orb.LineString{{0, 12}, {10, 12}}},
+			b:    square,
+			dist: 2,
+			pa:   orb.Point{10, 12},
+			pb:   orb.Point{10, 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dist, pa, pb := ClosestPoints(tt.a, tt.b)
+			if math.Abs(dist-tt.dist) > 1e-9 {
+				t.Errorf("expected distance %v, got %v", tt.dist, dist)
+			}
+			if !pa.Equal(tt.pa) || !pb.Equal(tt.pb) {
+				t.Errorf("expected closest points %v %v, got %v %v", tt.pa, tt.pb, pa, pb)
+			}
+			if d := Distance(tt.b, tt.a); math.Abs(d-tt.dist) > 1e-9 {
+				t.Errorf("expected symmetric distance %v, got %v", tt.dist, d)
+			}
+		})
+	}
+	if d := Distance(square, orb.MultiPoint{}); !math.IsInf(d, 1) {
+		t.Errorf("expected infinite distance to empty geometry, got %v", d)
+	}
+}
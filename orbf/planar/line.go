@@ -281,3 +281,60 @@ func lineContains(l orb.LineString, geom orb.Geometry) bool {
 	}
 	return false
 }
+
+// EdgeTouch checks boundaries of geometries have common segment with non zero length.
+// For polygons boundaries are rings, for lines - lines itself.
+func EdgeTouch(a, b orb.Geometry) bool {
+	if !a.Bound().Intersects(b.Bound()) {
+		return false
+	}
+	bb := boundaryLines(b)
+	for _, l1 := range boundaryLines(a) {
+		for _, l2 := range bb {
+			if !l1.Bound().Intersects(l2.Bound()) {
+				continue
+			}
+			for i := 1; i < len(l1); i++ {
+				for j := 1; j < len(l2); j++ {
+					if vector.SegmentsOverlap(l1[i-1], l1[i], l2[j-1], l2[j]) {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// boundaryLines returns boundary of the geometry as lines.
+func boundaryLines(geom orb.Geometry) []orb.LineString {
+	switch g := geom.(type) {
+	case orb.Bound:
+		return []orb.LineString{orb.LineString(g.ToRing())}
+	case orb.LineString:
+		return []orb.LineString{g}
+	case orb.MultiLineString:
+		return g
+	case orb.Ring:
+		return []orb.LineString{orb.LineString(g)}
+	case orb.Polygon:
+		out := make([]orb.LineString, 0, len(g))
+		for _, r := range g {
+			out = append(out, orb.LineString(r))
+		}
+		return out
+	case orb.MultiPolygon:
+		var out []orb.LineString
+		for _, p := range g {
+			out = append(out, boundaryLines(p)...)
+		}
+		return out
+	case orb.Collection:
+		var out []orb.LineString
+		for _, g := range g {
+			out = append(out, boundaryLines(g)...)
+		}
+		return out
+	}
+	return nil
+}
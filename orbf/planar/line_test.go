@@ -474,3 +474,46 @@ func Test_lineIntersects(t *testing.T) {
 		})
 	}
 }
+
+func TestEdgeTouch(t *testing.T) {
+	square := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	tests := []struct {
+		name   string
+		geom   orb.Geometry
+		expect bool
+	}{
+		{
+			name:   "Polygon shares edge",
+			geom:   orb.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}},
+			expect: true,
+		},
+		{
+			name:   "Polygon shares part of edge",
+			geom:   orb.Polygon{{{10, 5}, {20, 5}, {20, 15}, {10, 15}, {10, 5}}},
+			expect: true,
+		},
+		{
+			name:   "Polygon shares vertex only",
+			geom:   orb.Polygon{{{10, 10}, {20, 10}, {20, 20}, {10, 20}, {10, 10}}},
+			expect: false,
+		},
+		{
+			name:   "Polygon doesn't touch",
+			geom:   orb.Polygon{{{11, 0}, {20, 0}, {20, 10}, {11, 10}, {11, 0}}},
+			expect: false,
+		},
+		{
+			name:   "Line lies on edge",
+			geom:   orb.LineString{{2, 0}, {4, 0}},
+			expect: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EdgeTouch(square, tt.geom); got != tt.expect {
+				t.Errorf("EdgeTouch() = %v, want %v", got, tt.expect)
+			}
+		})
+	}
+}
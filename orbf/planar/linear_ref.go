@@ -0,0 +1,208 @@
+package planar
+
+import (
+	"math"
+
+	"github.com/VGSML/geobin/orbf/vector"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+	"golang.org/x/exp/slices"
+)
+
+// MeasuredLine is a line with measure (M) values of its points, for example chainage of the route.
+// Measures must not decrease along the line, measures between points are interpolated linearly.
+type MeasuredLine struct {
+	Line orb.LineString
+	M    []float64
+}
+
+// NewMeasuredLine returns the line with measures from the start measure to the end measure
+// proportional to the length along the line.
+func NewMeasuredLine(line orb.LineString, from, to float64) MeasuredLine {
+	return NewMeasuredLineDistance(line, from, to, planar.Distance)
+}
+
+// NewMeasuredLineDistance returns the line with measures proportional to the length
+// along the line measured by the distance function.
+func NewMeasuredLineDistance(line orb.LineString, from, to float64, distance orb.DistanceFunc) MeasuredLine {
+	m := make([]float64, len(line))
+	for i := 1; i < len(line); i++ {
+		m[i] = m[i-1] + distance(line[i-1], line[i])
+	}
+	total := 0.
+	if len(m) > 0 {
+		total = m[len(m)-1]
+	}
+	for i := range m {
+		if total == 0 {
+			m[i] = from
+			continue
+		}
+		m[i] = from + (to-from)*m[i]/total
+	}
+	if len(m) > 1 {
+		m[len(m)-1] = to
+	}
+	return MeasuredLine{Line: line.Clone(), M: m}
+}
+
+// Valid checks the line has a measure for every point and measures don't decrease.
+func (ml MeasuredLine) Valid() bool {
+	if len(ml.Line) == 0 || len(ml.Line) != len(ml.M) {
+		return false
+	}
+	for i := 1; i < len(ml.M); i++ {
+		if !(ml.M[i] >= ml.M[i-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Locate returns the index of the segment and the fraction of the segment at the measure,
+// the first segment is used if the measure is at the shared point of segments.
+// Returns false if the measure is out of the line measures.
+func (ml MeasuredLine) Locate(m float64) (int, float64, bool) {
+	if !ml.Valid() || m < ml.M[0] || m > ml.M[len(ml.M)-1] {
+		return 0, 0, false
+	}
+	if len(ml.Line) == 1 {
+		return 0, 0, true
+	}
+	i, _ := slices.BinarySearch(ml.M[1:], m)
+	i = min(i, len(ml.M)-2)
+	if dm := ml.M[i+1] - ml.M[i]; dm > 0 {
+		return i, (m - ml.M[i]) / dm, true
+	}
+	return i, 0, true
+}
+
+// LocateAlong returns the point of the line at the measure shifted perpendicular to the line by offset,
+// positive offset places the point on the right of the line direction as LineLocatePoint distance.
+// Returns false if the measure is out of the line measures.
+func LocateAlong(ml MeasuredLine, m, offset float64) (orb.Point, bool) {
+	i, t, ok := ml.Locate(m)
+	if !ok {
+		return orb.Point{}, false
+	}
+	if len(ml.Line) == 1 {
+		return ml.Line[0], true
+	}
+	p := vector.LineSegmentInterpolatePoint(ml.Line[i], ml.Line[i+1], t)
+	if offset == 0 {
+		return p, true
+	}
+	a, b, ok := segmentDirection(ml.Line, i)
+	if !ok {
+		return p, true
+	}
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l := math.Hypot(dx, dy)
+	return orb.Point{p[0] + dy/l*offset, p[1] - dx/l*offset}, true
+}
+
+// segmentDirection returns the segment of not equal points nearest to the segment i.
+func segmentDirection(line orb.LineString, i int) (orb.Point, orb.Point, bool) {
+	for d := 0; d < len(line); d++ {
+		for _, j := range [2]int{i + d, i - d} {
+			if j >= 0 && j+1 < len(line) && line[j] != line[j+1] {
+				return line[j], line[j+1], true
+			}
+		}
+	}
+	return orb.Point{}, orb.Point{}, false
+}
+
+// LocateBetween returns the part of the line between measures, the range is clipped to the line measures.
+// Returns false if the range and the line measures don't intersect.
+func LocateBetween(ml MeasuredLine, from, to float64) (MeasuredLine, bool) {
+	if from > to {
+		from, to = to, from
+	}
+	if !ml.Valid() || to < ml.M[0] || from > ml.M[len(ml.M)-1] {
+		return MeasuredLine{}, false
+	}
+	from, to = max(from, ml.M[0]), min(to, ml.M[len(ml.M)-1])
+	start, _ := LocateAlong(ml, from, 0)
+	end, _ := LocateAlong(ml, to, 0)
+	out := MeasuredLine{Line: orb.LineString{start}, M: []float64{from}}
+	for i := range ml.Line {
+		if ml.M[i] > from && ml.M[i] < to {
+			out.Line = append(out.Line, ml.Line[i])
+			out.M = append(out.M, ml.M[i])
+		}
+	}
+	out.Line = append(out.Line, end)
+	out.M = append(out.M, to)
+	return out, true
+}
+
+// LineLocateMeasure returns the measure of the line point closest to the point
+// and signed distance between the point and the line as LineLocatePoint.
+func LineLocateMeasure(ml MeasuredLine, point orb.Point) (float64, float64) {
+	if !ml.Valid() {
+		return 0, 0
+	}
+	if len(ml.Line) == 1 {
+		return ml.M[0], planar.Distance(ml.Line[0], point)
+	}
+	m, minDist := 0., math.MaxFloat64
+	for i := 1; i < len(ml.Line); i++ {
+		cp := segmentClosestPoint(ml.Line[i-1], ml.Line[i], point)
+		dist := planar.Distance(cp, point)
+		if orientation(ml.Line[i-1], ml.Line[i], point) > 0 {
+			dist = -dist
+		}
+		if math.Abs(dist) < math.Abs(minDist) {
+			minDist = dist
+			m = ml.M[i-1]
+			if l := planar.Distance(ml.Line[i-1], ml.Line[i]); l > 0 {
+				m += (ml.M[i] - ml.M[i-1]) * planar.Distance(ml.Line[i-1], cp) / l
+			}
+		}
+	}
+	return m, minDist
+}
+
+// LineSubstring returns the part of the line between fractions of the line length,
+// fractions are clipped to [0, 1]. If fromFrac is greater than toFrac the part is reversed.
+func LineSubstring(line orb.LineString, fromFrac, toFrac float64) orb.LineString {
+	sub, ok := LocateBetween(NewMeasuredLine(line, 0, 1), max(0, min(fromFrac, 1)), max(0, min(toFrac, 1)))
+	if !ok {
+		return nil
+	}
+	if fromFrac > toFrac {
+		sub.Line.Reverse()
+	}
+	return sub.Line
+}
+
+// SplitLineAtPoints splits the line at the line points closest to the points.
+// Parts of zero length are not returned.
+func SplitLineAtPoints(line orb.LineString, points []orb.Point) orb.MultiLineString {
+	ml := NewMeasuredLine(line, 0, planar.Length(line))
+	measures := make([]float64, 0, len(points))
+	for _, p := range points {
+		m, _ := LineLocateMeasure(ml, p)
+		measures = append(measures, m)
+	}
+	return splitLine(ml, measures)
+}
+
+// splitLine splits the line at measures.
+func splitLine(ml MeasuredLine, measures []float64) orb.MultiLineString {
+	if !ml.Valid() {
+		return nil
+	}
+	measures = append(slices.Clone(measures), ml.M[0], ml.M[len(ml.M)-1])
+	slices.Sort(measures)
+	measures = slices.Compact(measures)
+	var out orb.MultiLineString
+	for i := 1; i < len(measures); i++ {
+		part, ok := LocateBetween(ml, measures[i-1], measures[i])
+		if ok && len(dedupPoints(part.Line)) > 1 {
+			out = append(out, part.Line)
+		}
+	}
+	return out
+}
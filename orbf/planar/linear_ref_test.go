@@ -0,0 +1,95 @@
+package planar
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestLineSubstring(t *testing.T) {
+	line := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+	tests := []struct {
+		name     string
+		from, to float64
+		expected orb.LineString
+	}{
+		{name: "whole", from: 0, to: 1, expected: line},
+		{name: "first segment", from: 0.25, to: 0.5, expected: orb.LineString{{5, 0}, {10, 0}}},
+		{name: "across vertex", from: 0.25, to: 0.75, expected: orb.LineString{{5, 0}, {10, 0}, {10, 5}}},
+		{name: "reversed", from: 0.75, to: 0.25, expected: orb.LineString{{10, 5}, {10, 0}, {5, 0}}},
+		{name: "clipped", from: -1, to: 0.25, expected: orb.LineString{{0, 0}, {5, 0}}},
+		{name: "point", from: 0.5, to: 0.5, expected: orb.LineString{{10, 0}, {10, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LineSubstring(line, tt.from, tt.to); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+	if got := LineSubstring(nil, 0, 1); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
+
+func TestSplitLineAtPoints(t *testing.T) {
+	line := orb.LineString{{0, 0}, {10, 0}, {10, 10}}
+	got := SplitLineAtPoints(line, []orb.Point{{10, 5}, {5, 1}, {0, 0}, {10, 0}, {20, 20}})
+	expected := orb.MultiLineString{
+		{{0, 0}, {5, 0}},
+		{{5, 0}, {10, 0}},
+		{{10, 0}, {10, 5}},
+		{{10, 5}, {10, 10}},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestMeasuredLine(t *testing.T) {
+	// chainage of the route starts at 100
+	ml := NewMeasuredLine(orb.LineString{{0, 0}, {10, 0}, {10, 0}, {10, 10}}, 100, 120)
+	if !reflect.DeepEqual(ml.M, []float64{100, 110, 110, 120}) {
+		t.Fatalf("unexpected measures %v", ml.M)
+	}
+	if !ml.Valid() || (MeasuredLine{Line: ml.Line, M: []float64{0, 2, 1, 3}}).Valid() {
+		t.Errorf("unexpected validity")
+	}
+
+	along := []struct {
+		m, offset float64
+		expected  orb.Point
+		ok        bool
+	}{
+		{m: 105, expected: orb.Point{5, 0}, ok: true},
+		{m: 105, offset: 2, expected: orb.Point{5, -2}, ok: true},
+		{m: 105, offset: -2, expected: orb.Point{5, 2}, ok: true},
+		{m: 115, offset: 2, expected: orb.Point{12, 5}, ok: true},
+		{m: 110, offset: 1, expected: orb.Point{10, -1}, ok: true},
+		{m: 90},
+		{m: 121},
+	}
+	for _, tt := range along {
+		p, ok := LocateAlong(ml, tt.m, tt.offset)
+		if ok != tt.ok || p != tt.expected {
+			t.Errorf("%v %v: expected %v %v, got %v %v", tt.m, tt.offset, tt.expected, tt.ok, p, ok)
+		}
+		if !ok || tt.offset == 0 {
+			continue
+		}
+		// the offset point is located back to its measure and offset
+		if m, d := LineLocateMeasure(ml, p); m != tt.m || d != tt.offset {
+			t.Errorf("%v %v: located at %v %v", tt.m, tt.offset, m, d)
+		}
+	}
+
+	between, ok := LocateBetween(ml, 115, 105)
+	expected := MeasuredLine{Line: orb.LineString{{5, 0}, {10, 0}, {10, 0}, {10, 5}}, M: []float64{105, 110, 110, 115}}
+	if !ok || !reflect.DeepEqual(between, expected) {
+		t.Errorf("expected %v, got %v", expected, between)
+	}
+	if _, ok := LocateBetween(ml, 0, 99); ok {
+		t.Errorf("expected range out of the line")
+	}
+}
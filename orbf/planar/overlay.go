@@ -2,7 +2,6 @@ package planar
 
 import (
 	"github.com/paulmach/orb"
-	"github.com/paulmach/orb/planar"
 )
 
 // Clone returns full copy of geometry
@@ -103,51 +102,3 @@ func Contains(a, b orb.Geometry) bool {
 	}
 	return false
 }
-
-// Centroid calculate centroid of given geometry.
-// For multipart geometry returned multiPoint.
-func Centroid(geom orb.Geometry) orb.Geometry {
-	switch geom := geom.(type) {
-	case orb.Point, orb.MultiPoint:
-		return geom
-	case orb.LineString:
-		return LineInterpolatePoint(geom, 0.5)
-	case orb.MultiLineString:
-		var mp orb.MultiPoint
-		for _, l := range geom {
-			mp = append(mp,
-				LineInterpolatePoint(l, 0.5),
-			)
-		}
-		return mp
-	case orb.Polygon:
-		pnt, _ := planar.CentroidArea(geom)
-		return pnt
-	case orb.MultiPolygon:
-		var mp orb.MultiPoint
-		for _, p := range geom {
-			pnt, _ := planar.CentroidArea(p)
-			mp = append(mp, pnt)
-		}
-		return mp
-	case orb.Collection:
-		var mp orb.Collection
-		for _, geom := range geom {
-			cp := Centroid(geom)
-			mpp, ok := cp.(orb.MultiPoint)
-			if !ok {
-				mp = append(mp, cp)
-			}
-			if ok {
-				for _, p := range mpp {
-					mp = append(mp, p)
-				}
-			}
-		}
-		return mp
-	case orb.Bound:
-		return geom.Center()
-	default:
-		return nil
-	}
-}
@@ -0,0 +1,501 @@
+package planar
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// overlayOp is a polygon overlay operation.
+type overlayOp int
+
+const (
+	opIntersection overlayOp = iota
+	opUnion
+	opDifference
+	opSymDifference
+)
+
+// Intersection returns area common for polygons of a and b.
+// Not areal parts of geometries are ignored.
+func Intersection(a, b orb.Geometry) orb.MultiPolygon {
+	return overlay(a, b, opIntersection)
+}
+
+// Union returns area covered by polygons of a or b.
+// Not areal parts of geometries are ignored.
+func Union(a, b orb.Geometry) orb.MultiPolygon {
+	return overlay(a, b, opUnion)
+}
+
+// Difference returns area of polygons of a that is not covered by polygons of b.
+// Not areal parts of geometries are ignored.
+func Difference(a, b orb.Geometry) orb.MultiPolygon {
+	return overlay(a, b, opDifference)
+}
+
+// SymDifference returns area covered by polygons of only one of a and b.
+// Not areal parts of geometries are ignored.
+func SymDifference(a, b orb.Geometry) orb.MultiPolygon {
+	return overlay(a, b, opSymDifference)
+}
+
+// LineIntersection returns parts of lines of geometry a that are inside or on the boundary of polygons of b.
+func LineIntersection(a, b orb.Geometry) orb.MultiLineString {
+	return clipLines(a, b, true)
+}
+
+// LineDifference returns parts of lines of geometry a that are outside of polygons of b.
+func LineDifference(a, b orb.Geometry) orb.MultiLineString {
+	return clipLines(a, b, false)
+}
+
+// overlayEdge is a directed edge of the result with the area on the left side.
+type overlayEdge struct {
+	from, to orb.Point
+	next     int
+}
+
+// overlay computes result of polygon overlay operation by noding boundaries of polygons,
+// selecting split edges by their location relative to the other geometry
+// and linking selected edges into rings.
+func overlay(a, b orb.Geometry, op overlayOp) orb.MultiPolygon {
+	ga, gb := areaGeometry(a), areaGeometry(b)
+	if len(ga.polygons) == 0 || len(gb.polygons) == 0 {
+		switch {
+		case op == opIntersection:
+			return nil
+		case op == opDifference:
+			return cloneMultiPolygon(ga.polygons)
+		}
+		return cloneMultiPolygon(append(ga.polygons, gb.polygons...))
+	}
+	if op != opUnion && op != opSymDifference && !ga.bound.Intersects(gb.bound) {
+		if op == opIntersection {
+			return nil
+		}
+		return cloneMultiPolygon(ga.polygons)
+	}
+	tol := relateTolerance(ga.bound, gb.bound)
+	sa, sb := ga.segments(), gb.segments()
+	sweepSegments(sa, sb, tol)
+	nodes := newNodeIndex(tol)
+	var edges []overlayEdge
+	selectEdges := func(segments []*relateSegment, other *areaLocator, first bool) {
+		for _, s := range segments {
+			points := s.splitNodes(tol)
+			for i := 1; i < len(points); i++ {
+				from, to := nodes.snap(points[i-1]), nodes.snap(points[i])
+				if from == to {
+					continue
+				}
+				keep, reverse := selectOverlayEdge(from, to, other, first, op, tol)
+				if !keep {
+					continue
+				}
+				if reverse {
+					from, to = to, from
+				}
+				edges = append(edges, overlayEdge{from: from, to: to})
+			}
+		}
+	}
+	selectEdges(sa, newAreaLocator(gb, tol), true)
+	selectEdges(sb, newAreaLocator(ga, tol), false)
+	return buildPolygons(edges)
+}
+
+// selectOverlayEdge checks the edge of self geometry with self area on the left is a part of the result boundary.
+func selectOverlayEdge(from, to orb.Point, other *areaLocator, first bool, op overlayOp, tol float64) (keep, reverse bool) {
+	mid := orb.Point{(from[0] + to[0]) / 2, (from[1] + to[1]) / 2}
+	loc := other.locate(mid)
+	if loc == Boundary {
+		// shared edge, check the other area is on the same side
+		dx, dy := to[0]-from[0], to[1]-from[1]
+		l := math.Hypot(dx, dy)
+		eps := max(l*1e-6, 8*tol)
+		left := orb.Point{mid[0] - dy/l*eps, mid[1] + dx/l*eps}
+		same := other.locate(left) == Interior
+		switch op {
+		case opIntersection, opUnion:
+			return same && first, false
+		case opDifference:
+			return !same && first, false
+		}
+		return false, false
+	}
+	inside := loc == Interior
+	switch op {
+	case opIntersection:
+		return inside, false
+	case opUnion:
+		return !inside, false
+	case opDifference:
+		if first {
+			return !inside, false
+		}
+		return inside, true
+	}
+	return true, inside
+}
+
+// areaGeometry returns polygons of geometry with counterclockwise shells and clockwise holes.
+func areaGeometry(geom orb.Geometry) *relateGeometry {
+	g := newRelateGeometry(geom)
+	out := &relateGeometry{dim: DimFalse, endpoints: map[orb.Point]int{}}
+	for _, p := range g.polygons {
+		poly := make(orb.Polygon, len(p))
+		for i, r := range p {
+			poly[i] = r.Clone()
+			if (signedArea(r) < 0) == (i == 0) {
+				poly[i].Reverse()
+			}
+		}
+		out.addPolygon(poly)
+	}
+	return out
+}
+
+// buildPolygons links edges into rings and assigns holes to shells.
+func buildPolygons(edges []overlayEdge) orb.MultiPolygon {
+	outgoing := map[orb.Point][]int{}
+	for i, e := range edges {
+		outgoing[e.from] = append(outgoing[e.from], i)
+	}
+	// the next edge is the first outgoing edge clockwise from the reversed incoming edge,
+	// so the rings are minimal and touching rings are separated
+	for i := range edges {
+		e := &edges[i]
+		back := math.Atan2(e.from[1]-e.to[1], e.from[0]-e.to[0])
+		e.next = -1
+		best := math.Inf(1)
+		for _, j := range outgoing[e.to] {
+			o := edges[j]
+			d := back - math.Atan2(o.to[1]-o.from[1], o.to[0]-o.from[0])
+			for d <= 0 {
+				d += 2 * math.Pi
+			}
+			if d < best {
+				best, e.next = d, j
+			}
+		}
+	}
+	used := make([]bool, len(edges))
+	var shells, holes []orb.Ring
+	for i := range edges {
+		if used[i] {
+			continue
+		}
+		ring := orb.Ring{edges[i].from}
+		closed := false
+		for j := i; j != -1 && !used[j]; j = edges[j].next {
+			used[j] = true
+			ring = append(ring, edges[j].to)
+			if edges[j].next == i {
+				closed = true
+			}
+		}
+		if !closed || len(ring) < 4 {
+			continue
+		}
+		switch a := signedArea(ring); {
+		case a > 0:
+			shells = append(shells, ring)
+		case a < 0:
+			holes = append(holes, ring)
+		}
+	}
+	sort.Slice(shells, func(i, j int) bool {
+		return signedArea(shells[i]) < signedArea(shells[j])
+	})
+	mp := make(orb.MultiPolygon, len(shells))
+	for i, s := range shells {
+		mp[i] = orb.Polygon{s}
+	}
+	for _, h := range holes {
+		p := orb.Point{(h[0][0] + h[1][0]) / 2, (h[0][1] + h[1][1]) / 2}
+		// the smallest shell that contains the hole
+		for i, s := range shells {
+			if s.Bound().Contains(p) && ringContainsPoint(s, p) {
+				mp[i] = append(mp[i], h)
+				break
+			}
+		}
+	}
+	return mp
+}
+
+// clipLines returns parts of lines of a inside or outside of polygons of b.
+func clipLines(a, b orb.Geometry, inside bool) orb.MultiLineString {
+	ga, gb := newRelateGeometry(a), areaGeometry(b)
+	if len(ga.lines) == 0 {
+		return nil
+	}
+	if len(gb.polygons) == 0 || !ga.bound.Intersects(gb.bound) {
+		if inside {
+			return nil
+		}
+		out := make(orb.MultiLineString, 0, len(ga.lines))
+		for _, l := range ga.lines {
+			out = append(out, l.Clone())
+		}
+		return out
+	}
+	tol := relateTolerance(ga.bound, gb.bound)
+	var sa []*relateSegment
+	for _, l := range ga.lines {
+		for i := 1; i < len(l); i++ {
+			sa = append(sa, &relateSegment{p: l[i-1], q: l[i]})
+		}
+	}
+	sweepSegments(sa, gb.segments(), tol)
+	locator := newAreaLocator(gb, tol)
+	var out orb.MultiLineString
+	for _, l := range ga.lines {
+		var cur orb.LineString
+		for i := 1; i < len(l); i++ {
+			points := sa[0].splitNodes(tol)
+			sa = sa[1:]
+			for j := 1; j < len(points); j++ {
+				x, y := points[j-1], points[j]
+				mid := orb.Point{(x[0] + y[0]) / 2, (x[1] + y[1]) / 2}
+				if (locator.locate(mid) != Exterior) != inside {
+					if len(cur) > 1 {
+						out = append(out, cur)
+					}
+					cur = nil
+					continue
+				}
+				if len(cur) == 0 {
+					cur = orb.LineString{x}
+				}
+				cur = append(cur, y)
+			}
+		}
+		if len(cur) > 1 {
+			out = append(out, cur)
+		}
+	}
+	return out
+}
+
+// sweepSegments nodes segments of a with segments of b which bounds intersect.
+// Segments are swept in order of minimum x, so only segments overlapping by x are compared.
+func sweepSegments(sa, sb []*relateSegment, tol float64) {
+	type sweepSegment struct {
+		s     *relateSegment
+		bound orb.Bound
+		first bool
+	}
+	segments := make([]sweepSegment, 0, len(sa)+len(sb))
+	for _, s := range sa {
+		segments = append(segments, sweepSegment{s: s, bound: segmentBound(s).Pad(tol), first: true})
+	}
+	for _, s := range sb {
+		segments = append(segments, sweepSegment{s: s, bound: segmentBound(s)})
+	}
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].bound.Min[0] < segments[j].bound.Min[0]
+	})
+	var active [2][]sweepSegment
+	for _, s := range segments {
+		self, other := 0, 1
+		if !s.first {
+			self, other = 1, 0
+		}
+		// segments of other geometry that end before the segment starts are not active
+		kept := active[other][:0]
+		for _, o := range active[other] {
+			if o.bound.Max[0] < s.bound.Min[0] {
+				continue
+			}
+			kept = append(kept, o)
+			if !s.bound.Intersects(o.bound) {
+				continue
+			}
+			if s.first {
+				nodeSegments(s.s, o.s, tol)
+			} else {
+				nodeSegments(o.s, s.s, tol)
+			}
+		}
+		active[other] = kept
+		active[self] = append(active[self], s)
+	}
+}
+
+func segmentBound(s *relateSegment) orb.Bound {
+	return orb.Bound{
+		Min: orb.Point{min(s.p[0], s.q[0]), min(s.p[1], s.q[1])},
+		Max: orb.Point{max(s.p[0], s.q[0]), max(s.p[1], s.q[1])},
+	}
+}
+
+// signedArea returns signed area of the ring, positive for counterclockwise rings.
+func signedArea(r orb.Ring) float64 {
+	area := 0.
+	for i := 1; i < len(r); i++ {
+		area += r[i-1][0]*r[i][1] - r[i][0]*r[i-1][1]
+	}
+	return area / 2
+}
+
+func cloneMultiPolygon(pp []orb.Polygon) orb.MultiPolygon {
+	if len(pp) == 0 {
+		return nil
+	}
+	out := make(orb.MultiPolygon, 0, len(pp))
+	for _, p := range pp {
+		out = append(out, p.Clone())
+	}
+	return out
+}
+
+// nodeIndex snaps points that are closer than tolerance to the same node.
+type nodeIndex struct {
+	tol  float64
+	grid map[[2]int64][]orb.Point
+}
+
+func newNodeIndex(tol float64) *nodeIndex {
+	return &nodeIndex{
+		tol:  tol,
+		grid: map[[2]int64][]orb.Point{},
+	}
+}
+
+func (n *nodeIndex) snap(p orb.Point) orb.Point {
+	size := n.tol * 4
+	x, y := int64(math.Floor(p[0]/size)), int64(math.Floor(p[1]/size))
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, q := range n.grid[[2]int64{x + dx, y + dy}] {
+				if pointsEqual(p, q, n.tol) {
+					return q
+				}
+			}
+		}
+	}
+	key := [2]int64{x, y}
+	n.grid[key] = append(n.grid[key], p)
+	return p
+}
+
+// areaLocator locates points relative to polygons of area geometry as relateGeometry.locate,
+// edges of polygons are indexed by horizontal bands, so only edges of the band of the point are checked.
+type areaLocator struct {
+	tol    float64
+	bound  orb.Bound
+	height float64
+	bands  [][]locatorEdge
+	// polygons with odd count of crossings and polygons with the point on the boundary,
+	// touched are polygons with edges in the band of the point
+	odd, boundary, seen []bool
+	touched             []int
+}
+
+// locatorEdge is an edge of the polygon with number poly.
+type locatorEdge struct {
+	a, b orb.Point
+	poly int
+}
+
+func newAreaLocator(g *relateGeometry, tol float64) *areaLocator {
+	l := &areaLocator{
+		tol:      tol,
+		bound:    g.bound,
+		odd:      make([]bool, len(g.polygons)),
+		boundary: make([]bool, len(g.polygons)),
+		seen:     make([]bool, len(g.polygons)),
+	}
+	var edges []locatorEdge
+	for n, p := range g.polygons {
+		for _, r := range p {
+			for i := 1; i < len(r); i++ {
+				edges = append(edges, locatorEdge{a: r[i-1], b: r[i], poly: n})
+			}
+		}
+	}
+	// about 4 edges in a band for evenly distributed edges
+	bands := max(len(edges)/4, 1)
+	l.height = (l.bound.Max[1] - l.bound.Min[1] + 2*tol) / float64(bands)
+	if l.height <= 0 {
+		bands, l.height = 1, 1
+	}
+	l.bands = make([][]locatorEdge, bands)
+	for _, e := range edges {
+		from, to := l.band(min(e.a[1], e.b[1])-tol), l.band(max(e.a[1], e.b[1])+tol)
+		for b := from; b <= to; b++ {
+			l.bands[b] = append(l.bands[b], e)
+		}
+	}
+	return l
+}
+
+// band returns number of the band with y coordinate.
+func (l *areaLocator) band(y float64) int {
+	b := int(math.Floor((y - l.bound.Min[1] + l.tol) / l.height))
+	return min(max(b, 0), len(l.bands)-1)
+}
+
+// locate returns location of the point, interior of any polygon has priority over boundary.
+func (l *areaLocator) locate(p orb.Point) Location {
+	if !pointInBound(l.bound, p, l.tol) {
+		return Exterior
+	}
+	l.touched = l.touched[:0]
+	for _, e := range l.bands[l.band(p[1])] {
+		if !l.seen[e.poly] {
+			l.seen[e.poly] = true
+			l.touched = append(l.touched, e.poly)
+		}
+		if pointOnSegment(e.a, e.b, p, l.tol) {
+			l.boundary[e.poly] = true
+		}
+		// crossing number as ringContainsPoint
+		if (e.a[1] > p[1]) != (e.b[1] > p[1]) &&
+			p[0] < e.a[0]+(p[1]-e.a[1])*(e.b[0]-e.a[0])/(e.b[1]-e.a[1]) {
+			l.odd[e.poly] = !l.odd[e.poly]
+		}
+	}
+	loc := Exterior
+	for _, n := range l.touched {
+		switch {
+		case l.odd[n] && !l.boundary[n]:
+			loc = Interior
+		case l.boundary[n] && loc == Exterior:
+			loc = Boundary
+		}
+		l.odd[n], l.boundary[n], l.seen[n] = false, false, false
+	}
+	return loc
+}
+
+// UnaryUnion returns union of all polygons of geometry, for example adjacent districts.
+func UnaryUnion(geom orb.Geometry) orb.MultiPolygon {
+	g := areaGeometry(geom)
+	if len(g.polygons) == 0 {
+		return nil
+	}
+	// cascaded union merges pairs of results, so the merged parts stay small
+	parts := make([]orb.MultiPolygon, len(g.polygons))
+	for i, p := range g.polygons {
+		parts[i] = orb.MultiPolygon{p}
+	}
+	for len(parts) > 1 {
+		merged := parts[:0]
+		for i := 0; i < len(parts); i += 2 {
+			if i+1 == len(parts) {
+				merged = append(merged, parts[i])
+				continue
+			}
+			merged = append(merged, Union(parts[i], parts[i+1]))
+		}
+		parts = merged
+	}
+	if len(g.polygons) == 1 {
+		return cloneMultiPolygon(parts[0])
+	}
+	return parts[0]
+}
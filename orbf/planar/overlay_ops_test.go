@@ -0,0 +1,231 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestOverlay(t *testing.T) {
+	square := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	holed := orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{3, 3}, {3, 6}, {6, 6}, {6, 3}, {3, 3}},
+	}
+	shifted := orb.Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}
+	// clockwise ring
+	neighbor := orb.Polygon{{{10, 0}, {10, 10}, {20, 10}, {20, 0}, {10, 0}}}
+	corner := orb.Polygon{{{10, 10}, {20, 10}, {20, 20}, {10, 20}, {10, 10}}}
+	inner := orb.Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}}
+	far := orb.Polygon{{{30, 30}, {40, 30}, {40, 40}, {30, 40}, {30, 30}}}
+	triangle := orb.Polygon{{{5, -5}, {15, 5}, {5, 5}, {5, -5}}}
+
+	tests := []struct {
+		name string
+		a, b orb.Geometry
+		// areas and count of polygons and holes of intersection, union, difference and symmetric difference
+		areas    [4]float64
+		polygons [4]int
+		holes    [4]int
+		equals   [4]orb.Geometry
+	}{
+		{
+			name:     "overlapping squares",
+			a:        square,
+			b:        shifted,
+			areas:    [4]float64{25, 175, 75, 150},
+			polygons: [4]int{1, 1, 1, 2},
+			equals: [4]orb.Geometry{
+				orb.Polygon{{{5, 5}, {10, 5}, {10, 10}, {5, 10}, {5, 5}}},
+				orb.Polygon{{{0, 0}, {10, 0}, {10, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 10}, {0, 10}, {0, 0}}},
+			},
+		},
+		{
+			name:     "adjacent squares",
+			a:        square,
+			b:        neighbor,
+			areas:    [4]float64{0, 200, 100, 200},
+			polygons: [4]int{0, 1, 1, 1},
+			equals: [4]orb.Geometry{
+				nil,
+				orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{20, 10}},
+				square,
+				orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{20, 10}},
+			},
+		},
+		{
+			name:     "squares touching by corner",
+			a:        square,
+			b:        corner,
+			areas:    [4]float64{0, 200, 100, 200},
+			polygons: [4]int{0, 2, 1, 2},
+		},
+		{
+			name:     "contained square",
+			a:        square,
+			b:        inner,
+			areas:    [4]float64{36, 100, 64, 64},
+			polygons: [4]int{1, 1, 1, 1},
+			holes:    [4]int{0, 0, 1, 1},
+			equals:   [4]orb.Geometry{inner, square},
+		},
+		{
+			name:     "polygon with hole",
+			a:        holed,
+			b:        shifted,
+			areas:    [4]float64{24, 167, 67, 143},
+			polygons: [4]int{1, 1, 1, 3},
+			holes:    [4]int{0, 1, 0, 0},
+		},
+		{
+			name:     "hole filled by polygon",
+			a:        holed,
+			b:        orb.Polygon{{{3, 3}, {6, 3}, {6, 6}, {3, 6}, {3, 3}}},
+			areas:    [4]float64{0, 100, 91, 100},
+			polygons: [4]int{0, 1, 1, 1},
+			holes:    [4]int{0, 0, 1, 0},
+			equals:   [4]orb.Geometry{nil, square, holed, square},
+		},
+		{
+			name:     "equal polygons",
+			a:        square,
+			b:        orb.Polygon{{{0, 0}, {5, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+			areas:    [4]float64{100, 100, 0, 0},
+			polygons: [4]int{1, 1, 0, 0},
+		},
+		{
+			name:     "disjoint polygons",
+			a:        square,
+			b:        far,
+			areas:    [4]float64{0, 200, 100, 200},
+			polygons: [4]int{0, 2, 1, 2},
+		},
+		{
+			name:     "triangle crossing boundary",
+			a:        square,
+			b:        triangle,
+			areas:    [4]float64{25, 125, 75, 100},
+			polygons: [4]int{1, 1, 1, 3},
+		},
+		{
+			name:     "multipolygon",
+			a:        orb.MultiPolygon{square, far},
+			b:        orb.Bound{Min: orb.Point{5, 5}, Max: orb.Point{35, 35}},
+			areas:    [4]float64{50, 1050, 150, 1000},
+			polygons: [4]int{2, 1, 2, 3},
+			holes:    [4]int{0, 0, 0, 0},
+		},
+	}
+	ops := [4]func(a, b orb.Geometry) orb.MultiPolygon{Intersection, Union, Difference, SymDifference}
+	names := [4]string{"intersection", "union", "difference", "symmetric difference"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for n, op := range ops {
+				got := op(tt.a, tt.b)
+				if area := math.Abs(planar.Area(got)); math.Abs(area-tt.areas[n]) > 1e-9 {
+					t.Errorf("%s: expected area %v, got %v: %v", names[n], tt.areas[n], area, got)
+				}
+				if len(got) != tt.polygons[n] {
+					t.Errorf("%s: expected %d polygons, got %d: %v", names[n], tt.polygons[n], len(got), got)
+				}
+				holes := 0
+				for _, p := range got {
+					holes += len(p) - 1
+					for i, r := range p {
+						if !r.Closed() {
+							t.Errorf("%s: ring is not closed", names[n])
+						}
+						if (signedArea(r) > 0) != (i == 0) {
+							t.Errorf("%s: shells should be counterclockwise and holes clockwise", names[n])
+						}
+					}
+				}
+				if holes != tt.holes[n] {
+					t.Errorf("%s: expected %d holes, got %d", names[n], tt.holes[n], holes)
+				}
+				if tt.equals[n] != nil && !Equals(got, tt.equals[n]) {
+					t.Errorf("%s: expected %v, got %v", names[n], tt.equals[n], got)
+				}
+			}
+		})
+	}
+}
+
+func TestUnaryUnion(t *testing.T) {
+	var districts orb.MultiPolygon
+	for x := 0.; x < 3; x++ {
+		for y := 0.; y < 3; y++ {
+			districts = append(districts, orb.Bound{Min: orb.Point{x, y}, Max: orb.Point{x + 1, y + 1}}.ToPolygon())
+		}
+	}
+	got := UnaryUnion(districts)
+	if len(got) != 1 || len(got[0]) != 1 {
+		t.Fatalf("expected one polygon without holes, got %v", got)
+	}
+	if !Equals(got, orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{3, 3}}) {
+		t.Errorf("unexpected union %v", got)
+	}
+}
+
+func TestLineClip(t *testing.T) {
+	holed := orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{3, 3}, {3, 6}, {6, 6}, {6, 3}, {3, 3}},
+	}
+	tests := []struct {
+		name    string
+		line    orb.Geometry
+		inside  orb.MultiLineString
+		outside orb.MultiLineString
+	}{
+		{
+			name:    "line crosses polygon and hole",
+			line:    orb.LineString{{-5, 4}, {15, 4}},
+			inside:  orb.MultiLineString{{{0, 4}, {3, 4}}, {{6, 4}, {10, 4}}},
+			outside: orb.MultiLineString{{{-5, 4}, {0, 4}}, {{3, 4}, {6, 4}}, {{10, 4}, {15, 4}}},
+		},
+		{
+			name:    "line on boundary",
+			line:    orb.LineString{{-5, 0}, {5, 0}, {5, 1}},
+			inside:  orb.MultiLineString{{{0, 0}, {5, 0}, {5, 1}}},
+			outside: orb.MultiLineString{{{-5, 0}, {0, 0}}},
+		},
+		{
+			name:    "line outside",
+			line:    orb.MultiLineString{{{20, 20}, {30, 30}}},
+			outside: orb.MultiLineString{{{20, 20}, {30, 30}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LineIntersection(tt.line, holed); !got.Equal(tt.inside) {
+				t.Errorf("LineIntersection() = %v, want %v", got, tt.inside)
+			}
+			if got := LineDifference(tt.line, holed); !got.Equal(tt.outside) {
+				t.Errorf("LineDifference() = %v, want %v", got, tt.outside)
+			}
+		})
+	}
+}
+
+func TestAreaLocator(t *testing.T) {
+	// overlapping polygons, polygon with hole and polygon inside the hole
+	g := areaGeometry(orb.MultiPolygon{
+		{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}, {{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}},
+		{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}},
+		{{{4, 4}, {6, 4}, {6, 6}, {4, 6}, {4, 4}}},
+		{{{20, 0}, {30, 5}, {20, 10}, {25, 5}, {20, 0}}},
+	})
+	tol := 1e-9
+	l := newAreaLocator(g, tol)
+	for x := -1.; x <= 31; x += 0.5 {
+		for y := -1.; y <= 16; y += 0.25 {
+			p := orb.Point{x, y}
+			if got, want := l.locate(p), g.locate(p, tol); got != want {
+				t.Errorf("locate(%v) = %v, want %v", p, got, want)
+			}
+		}
+	}
+}
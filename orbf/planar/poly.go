@@ -76,7 +76,8 @@ func polyIntersects(poly orb.Polygon, geom orb.Geometry) bool {
 				}
 			}
 		}
-		return false
+		// polygon can be inside of geometry
+		return len(poly) != 0 && len(poly[0]) != 0 && planar.PolygonContains(g, poly[0][0])
 	case orb.MultiPolygon:
 		for _, p2 := range g {
 			if polyIntersects(poly, p2) {
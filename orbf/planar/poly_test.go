@@ -315,6 +315,19 @@ func TestPolyIntersects(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name:     "Input polygon contained within polygon",
+			geom:     orb.Polygon{{{-1, -1}, {11, -1}, {11, 11}, {-1, 11}, {-1, -1}}},
+			expected: true,
+		},
+		{
+			name: "Input polygon inside hole of polygon",
+			geom: orb.Polygon{
+				{{-2, -2}, {12, -2}, {12, 12}, {-2, 12}, {-2, -2}},
+				{{-1, -1}, {-1, 11}, {11, 11}, {11, -1}, {-1, -1}},
+			},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,234 @@
+package planar
+
+import (
+	"strings"
+
+	"github.com/paulmach/orb"
+)
+
+// Location is a topological location of the point relative to geometry.
+type Location int8
+
+const (
+	Interior Location = iota
+	Boundary
+	Exterior
+)
+
+// Dimension is a dimension of the intersection of geometry parts.
+type Dimension int8
+
+const (
+	// DimFalse means empty intersection.
+	DimFalse Dimension = iota - 1
+	DimPoint
+	DimLine
+	DimArea
+)
+
+// IntersectionMatrix is a DE-9IM matrix of two geometries,
+// rows are locations in the first geometry and columns are locations in the second one.
+type IntersectionMatrix [3][3]Dimension
+
+// newIntersectionMatrix returns matrix of empty intersections.
+func newIntersectionMatrix() IntersectionMatrix {
+	var m IntersectionMatrix
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = DimFalse
+		}
+	}
+	return m
+}
+
+// set increases dimension of intersection of locations.
+func (m *IntersectionMatrix) set(a, b Location, d Dimension) {
+	if d > m[a][b] {
+		m[a][b] = d
+	}
+}
+
+// String returns matrix in form of "212101212".
+func (m IntersectionMatrix) String() string {
+	var sb strings.Builder
+	for i := range m {
+		for j := range m[i] {
+			if m[i][j] == DimFalse {
+				sb.WriteByte('F')
+				continue
+			}
+			sb.WriteByte('0' + byte(m[i][j]))
+		}
+	}
+	return sb.String()
+}
+
+// Matches checks matrix matches the DE-9IM pattern of 9 symbols: T, F, *, 0, 1 and 2.
+func (m IntersectionMatrix) Matches(pattern string) bool {
+	if len(pattern) != 9 {
+		return false
+	}
+	for n := 0; n < 9; n++ {
+		d := m[n/3][n%3]
+		switch pattern[n] {
+		case '*':
+		case 'T', 't':
+			if d == DimFalse {
+				return false
+			}
+		case 'F', 'f':
+			if d != DimFalse {
+				return false
+			}
+		case '0', '1', '2':
+			if d != Dimension(pattern[n]-'0') {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Transpose returns matrix of geometries in reverse order.
+func (m IntersectionMatrix) Transpose() IntersectionMatrix {
+	var t IntersectionMatrix
+	for i := range m {
+		for j := range m[i] {
+			t[j][i] = m[i][j]
+		}
+	}
+	return t
+}
+
+// IsDisjoint checks geometries have no common points.
+func (m IntersectionMatrix) IsDisjoint() bool {
+	return m.Matches("FF*FF****")
+}
+
+// IsIntersects checks geometries have at least one common point.
+func (m IntersectionMatrix) IsIntersects() bool {
+	return !m.IsDisjoint()
+}
+
+// IsWithin checks the first geometry is within the second one.
+func (m IntersectionMatrix) IsWithin() bool {
+	return m.Matches("T*F**F***")
+}
+
+// IsContains checks the first geometry contains the second one.
+func (m IntersectionMatrix) IsContains() bool {
+	return m.Matches("T*****FF*")
+}
+
+// IsCovers checks every point of the second geometry is a point of the first one.
+func (m IntersectionMatrix) IsCovers() bool {
+	return m.Matches("T*****FF*") ||
+		m.Matches("*T****FF*") ||
+		m.Matches("***T**FF*") ||
+		m.Matches("****T*FF*")
+}
+
+// IsCoveredBy checks every point of the first geometry is a point of the second one.
+func (m IntersectionMatrix) IsCoveredBy() bool {
+	return m.Matches("T*F**F***") ||
+		m.Matches("*TF**F***") ||
+		m.Matches("**FT*F***") ||
+		m.Matches("**F*TF***")
+}
+
+// IsEquals checks geometries are topologically equal.
+func (m IntersectionMatrix) IsEquals(dimA, dimB Dimension) bool {
+	return dimA == dimB && m.Matches("T*F**FFF*")
+}
+
+// IsTouches checks geometries have common points only on boundaries.
+func (m IntersectionMatrix) IsTouches(dimA, dimB Dimension) bool {
+	if dimA == DimPoint && dimB == DimPoint {
+		return false
+	}
+	return m.Matches("FT*******") ||
+		m.Matches("F**T*****") ||
+		m.Matches("F***T****")
+}
+
+// IsCrosses checks geometries have some but not all interior points in common
+// and dimension of intersection is less than maximal dimension of geometries.
+func (m IntersectionMatrix) IsCrosses(dimA, dimB Dimension) bool {
+	switch {
+	case dimA == DimLine && dimB == DimLine:
+		return m.Matches("0********")
+	case dimA < dimB:
+		return m.Matches("T*T******")
+	case dimA > dimB:
+		return m.Matches("T*****T**")
+	}
+	return false
+}
+
+// IsOverlaps checks geometries of the same dimension have common interior points
+// and each of them has points out of the other one.
+func (m IntersectionMatrix) IsOverlaps(dimA, dimB Dimension) bool {
+	if dimA != dimB {
+		return false
+	}
+	if dimA == DimLine {
+		return m.Matches("1*T***T**")
+	}
+	return m.Matches("T*T***T**")
+}
+
+// Relate returns DE-9IM intersection matrix of geometries.
+func Relate(a, b orb.Geometry) IntersectionMatrix {
+	return relate(newRelateGeometry(a), newRelateGeometry(b))
+}
+
+// GeometryDimension returns maximal dimension of geometry parts, DimFalse for empty geometry.
+func GeometryDimension(geom orb.Geometry) Dimension {
+	return newRelateGeometry(geom).dim
+}
+
+// Disjoint checks geometries have no common points.
+func Disjoint(a, b orb.Geometry) bool {
+	return Relate(a, b).IsDisjoint()
+}
+
+// Within checks a is within b.
+func Within(a, b orb.Geometry) bool {
+	return Relate(a, b).IsWithin()
+}
+
+// Covers checks every point of b is a point of a.
+func Covers(a, b orb.Geometry) bool {
+	return Relate(a, b).IsCovers()
+}
+
+// CoveredBy checks every point of a is a point of b.
+func CoveredBy(a, b orb.Geometry) bool {
+	return Relate(a, b).IsCoveredBy()
+}
+
+// Equals checks geometries are topologically equal.
+func Equals(a, b orb.Geometry) bool {
+	ga, gb := newRelateGeometry(a), newRelateGeometry(b)
+	return relate(ga, gb).IsEquals(ga.dim, gb.dim)
+}
+
+// Touches checks geometries have common points only on boundaries.
+func Touches(a, b orb.Geometry) bool {
+	ga, gb := newRelateGeometry(a), newRelateGeometry(b)
+	return relate(ga, gb).IsTouches(ga.dim, gb.dim)
+}
+
+// Crosses checks geometries have some but not all interior points in common.
+func Crosses(a, b orb.Geometry) bool {
+	ga, gb := newRelateGeometry(a), newRelateGeometry(b)
+	return relate(ga, gb).IsCrosses(ga.dim, gb.dim)
+}
+
+// Overlaps checks geometries of the same dimension overlap.
+func Overlaps(a, b orb.Geometry) bool {
+	ga, gb := newRelateGeometry(a), newRelateGeometry(b)
+	return relate(ga, gb).IsOverlaps(ga.dim, gb.dim)
+}
@@ -0,0 +1,405 @@
+package planar
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// relateGeometry is a geometry decomposed to parts of different dimensions.
+type relateGeometry struct {
+	dim      Dimension
+	points   []orb.Point
+	lines    []orb.LineString
+	polygons []orb.Polygon
+	// endpoints counts ends of lines, ends with odd count are boundary of lines (mod-2 rule)
+	endpoints map[orb.Point]int
+	bound     orb.Bound
+}
+
+func newRelateGeometry(geom orb.Geometry) *relateGeometry {
+	g := &relateGeometry{
+		dim:       DimFalse,
+		endpoints: map[orb.Point]int{},
+	}
+	g.add(geom)
+	return g
+}
+
+func (g *relateGeometry) add(geom orb.Geometry) {
+	switch geom := geom.(type) {
+	case orb.Point:
+		g.addPoint(geom)
+	case orb.MultiPoint:
+		for _, p := range geom {
+			g.addPoint(p)
+		}
+	case orb.LineString:
+		g.addLine(geom)
+	case orb.MultiLineString:
+		for _, l := range geom {
+			g.addLine(l)
+		}
+	case orb.Ring:
+		g.addLine(orb.LineString(geom))
+	case orb.Polygon:
+		g.addPolygon(geom)
+	case orb.MultiPolygon:
+		for _, p := range geom {
+			g.addPolygon(p)
+		}
+	case orb.Bound:
+		switch {
+		case geom.Min == geom.Max:
+			g.addPoint(geom.Min)
+		case geom.Min.X() == geom.Max.X() || geom.Min.Y() == geom.Max.Y():
+			g.addLine(orb.LineString{geom.Min, geom.Max})
+		default:
+			g.addPolygon(geom.ToPolygon())
+		}
+	case orb.Collection:
+		for _, geom := range geom {
+			g.add(geom)
+		}
+	}
+}
+
+func (g *relateGeometry) extend(dim Dimension, b orb.Bound) {
+	if g.dim == DimFalse {
+		g.bound = b
+	} else {
+		g.bound = g.bound.Union(b)
+	}
+	g.dim = max(g.dim, dim)
+}
+
+func (g *relateGeometry) addPoint(p orb.Point) {
+	g.points = append(g.points, p)
+	g.extend(DimPoint, p.Bound())
+}
+
+func (g *relateGeometry) addLine(l orb.LineString) {
+	// remove repeated points
+	line := make(orb.LineString, 0, len(l))
+	for _, p := range l {
+		if len(line) == 0 || line[len(line)-1] != p {
+			line = append(line, p)
+		}
+	}
+	switch len(line) {
+	case 0:
+		return
+	case 1:
+		g.addPoint(line[0])
+		return
+	}
+	g.lines = append(g.lines, line)
+	if line[0] != line[len(line)-1] {
+		g.endpoints[line[0]]++
+		g.endpoints[line[len(line)-1]]++
+	}
+	g.extend(DimLine, line.Bound())
+}
+
+func (g *relateGeometry) addPolygon(p orb.Polygon) {
+	if len(p) == 0 || len(p[0]) < 4 {
+		return
+	}
+	poly := make(orb.Polygon, 0, len(p))
+	for _, r := range p {
+		if len(r) < 4 {
+			continue
+		}
+		if !r.Closed() {
+			r = append(r.Clone(), r[0])
+		}
+		poly = append(poly, r)
+	}
+	g.polygons = append(g.polygons, poly)
+	g.extend(DimArea, poly.Bound())
+}
+
+// boundaryDimension returns dimension of the geometry boundary.
+func (g *relateGeometry) boundaryDimension() Dimension {
+	if len(g.polygons) != 0 {
+		return DimLine
+	}
+	for _, n := range g.endpoints {
+		if n%2 == 1 {
+			return DimPoint
+		}
+	}
+	return DimFalse
+}
+
+// relateSegment is a segment of lines or polygon rings with the nodes that split it.
+type relateSegment struct {
+	p, q  orb.Point
+	area  bool
+	nodes []orb.Point
+}
+
+func (g *relateGeometry) segments() []*relateSegment {
+	var out []*relateSegment
+	for _, l := range g.lines {
+		for i := 1; i < len(l); i++ {
+			out = append(out, &relateSegment{p: l[i-1], q: l[i]})
+		}
+	}
+	for _, poly := range g.polygons {
+		for _, r := range poly {
+			for i := 1; i < len(r); i++ {
+				if r[i-1] != r[i] {
+					out = append(out, &relateSegment{p: r[i-1], q: r[i], area: true})
+				}
+			}
+		}
+	}
+	return out
+}
+
+// locate returns location of the point relative to geometry.
+// Interior of any part has priority over boundary.
+func (g *relateGeometry) locate(p orb.Point, tol float64) Location {
+	if !pointInBound(g.bound, p, tol) {
+		return Exterior
+	}
+	loc := Exterior
+	for _, poly := range g.polygons {
+		switch polygonLocation(poly, p, tol) {
+		case Interior:
+			return Interior
+		case Boundary:
+			loc = Boundary
+		}
+	}
+	for _, l := range g.lines {
+		for i := 1; i < len(l); i++ {
+			if !pointOnSegment(l[i-1], l[i], p, tol) {
+				continue
+			}
+			if !g.isLineBoundary(p, tol) {
+				return Interior
+			}
+			loc = Boundary
+		}
+	}
+	for _, pnt := range g.points {
+		if pointsEqual(pnt, p, tol) {
+			return Interior
+		}
+	}
+	return loc
+}
+
+// isLineBoundary checks point is an end of odd number of lines.
+func (g *relateGeometry) isLineBoundary(p orb.Point, tol float64) bool {
+	if n, ok := g.endpoints[p]; ok {
+		return n%2 == 1
+	}
+	for e, n := range g.endpoints {
+		if pointsEqual(e, p, tol) {
+			return n%2 == 1
+		}
+	}
+	return false
+}
+
+// relate computes DE-9IM matrix by noding linework of geometries and locating
+// nodes, middle points of split segments and points near the area boundaries.
+func relate(a, b *relateGeometry) IntersectionMatrix {
+	m := newIntersectionMatrix()
+	m.set(Exterior, Exterior, DimArea)
+	if a.dim == DimFalse || b.dim == DimFalse {
+		if a.dim != DimFalse {
+			m.set(Interior, Exterior, a.dim)
+			m.set(Boundary, Exterior, a.boundaryDimension())
+		}
+		if b.dim != DimFalse {
+			m.set(Exterior, Interior, b.dim)
+			m.set(Exterior, Boundary, b.boundaryDimension())
+		}
+		return m
+	}
+	tol := relateTolerance(a.bound, b.bound)
+	sa, sb := a.segments(), b.segments()
+	for _, s1 := range sa {
+		sBound := orb.MultiPoint{s1.p, s1.q}.Bound().Pad(tol)
+		for _, s2 := range sb {
+			if sBound.Intersects(orb.MultiPoint{s2.p, s2.q}.Bound()) {
+				nodeSegments(s1, s2, tol)
+			}
+		}
+		for _, p := range b.points {
+			if pointOnSegment(s1.p, s1.q, p, tol) {
+				s1.nodes = append(s1.nodes, p)
+			}
+		}
+	}
+	for _, s2 := range sb {
+		for _, p := range a.points {
+			if pointOnSegment(s2.p, s2.q, p, tol) {
+				s2.nodes = append(s2.nodes, p)
+			}
+		}
+	}
+	relateParts(a, b, sa, tol, m.set)
+	relateParts(b, a, sb, tol, func(lb, la Location, d Dimension) {
+		m.set(la, lb, d)
+	})
+	return m
+}
+
+// relateParts sets dimensions of intersections of self parts with other geometry.
+func relateParts(self, other *relateGeometry, segments []*relateSegment, tol float64, set func(Location, Location, Dimension)) {
+	for _, p := range self.points {
+		set(self.locate(p, tol), other.locate(p, tol), DimPoint)
+	}
+	for _, s := range segments {
+		nodes := s.splitNodes(tol)
+		for i, x := range nodes {
+			set(self.locate(x, tol), other.locate(x, tol), DimPoint)
+			if i == 0 {
+				continue
+			}
+			y := nodes[i-1]
+			mid := orb.Point{(x[0] + y[0]) / 2, (x[1] + y[1]) / 2}
+			set(self.locate(mid, tol), other.locate(mid, tol), DimLine)
+			if !s.area {
+				continue
+			}
+			// points on the both sides of area boundary
+			dx, dy := x[0]-y[0], x[1]-y[1]
+			l := math.Hypot(dx, dy)
+			eps := max(l*1e-6, 8*tol)
+			nx, ny := dy/l*eps, -dx/l*eps
+			for _, side := range []orb.Point{{mid[0] + nx, mid[1] + ny}, {mid[0] - nx, mid[1] - ny}} {
+				set(self.locate(side, tol), other.locate(side, tol), DimArea)
+			}
+		}
+	}
+}
+
+// splitNodes returns ends and nodes of the segment ordered from p to q without duplicates.
+func (s *relateSegment) splitNodes(tol float64) []orb.Point {
+	dx, dy := s.q[0]-s.p[0], s.q[1]-s.p[1]
+	l2 := dx*dx + dy*dy
+	param := func(x orb.Point) float64 {
+		return ((x[0]-s.p[0])*dx + (x[1]-s.p[1])*dy) / l2
+	}
+	nodes := make([]orb.Point, 0, len(s.nodes)+2)
+	for _, n := range s.nodes {
+		if !pointsEqual(n, s.p, tol) && !pointsEqual(n, s.q, tol) {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return param(nodes[i]) < param(nodes[j])
+	})
+	out := append(make([]orb.Point, 0, len(nodes)+2), s.p)
+	for _, n := range nodes {
+		if !pointsEqual(out[len(out)-1], n, tol) {
+			out = append(out, n)
+		}
+	}
+	return append(out, s.q)
+}
+
+// nodeSegments adds intersection points of segments to their nodes.
+func nodeSegments(s1, s2 *relateSegment, tol float64) {
+	for _, x := range [2]orb.Point{s2.p, s2.q} {
+		if pointOnSegment(s1.p, s1.q, x, tol) {
+			s1.nodes = append(s1.nodes, x)
+		}
+	}
+	for _, x := range [2]orb.Point{s1.p, s1.q} {
+		if pointOnSegment(s2.p, s2.q, x, tol) {
+			s2.nodes = append(s2.nodes, x)
+		}
+	}
+	d1 := orientation(s2.p, s2.q, s1.p)
+	d2 := orientation(s2.p, s2.q, s1.q)
+	d3 := orientation(s1.p, s1.q, s2.p)
+	d4 := orientation(s1.p, s1.q, s2.q)
+	if d1*d2 >= 0 || d3*d4 >= 0 {
+		return
+	}
+	// proper crossing
+	t := d1 / (d1 - d2)
+	x := orb.Point{s1.p[0] + t*(s1.q[0]-s1.p[0]), s1.p[1] + t*(s1.q[1]-s1.p[1])}
+	s1.nodes = append(s1.nodes, x)
+	s2.nodes = append(s2.nodes, x)
+}
+
+// orientation returns doubled signed area of triangle abc,
+// positive if c is on the left of ab.
+func orientation(a, b, c orb.Point) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// relateTolerance returns tolerance of coordinates comparison for geometries bounds.
+func relateTolerance(a, b orb.Bound) float64 {
+	scale := 1.
+	for _, v := range []float64{a.Min[0], a.Min[1], a.Max[0], a.Max[1], b.Min[0], b.Min[1], b.Max[0], b.Max[1]} {
+		scale = max(scale, math.Abs(v))
+	}
+	return scale * 1e-12
+}
+
+func pointsEqual(a, b orb.Point, tol float64) bool {
+	return math.Abs(a[0]-b[0]) <= tol && math.Abs(a[1]-b[1]) <= tol
+}
+
+func pointInBound(b orb.Bound, p orb.Point, tol float64) bool {
+	return p[0] >= b.Min[0]-tol && p[0] <= b.Max[0]+tol &&
+		p[1] >= b.Min[1]-tol && p[1] <= b.Max[1]+tol
+}
+
+// pointOnSegment checks distance between point and segment ab is not greater than tol.
+func pointOnSegment(a, b, p orb.Point, tol float64) bool {
+	if p[0] < min(a[0], b[0])-tol || p[0] > max(a[0], b[0])+tol ||
+		p[1] < min(a[1], b[1])-tol || p[1] > max(a[1], b[1])+tol {
+		return false
+	}
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	l2 := dx*dx + dy*dy
+	if l2 == 0 {
+		return pointsEqual(a, p, tol)
+	}
+	t := min(max(((p[0]-a[0])*dx+(p[1]-a[1])*dy)/l2, 0), 1)
+	return math.Hypot(a[0]+t*dx-p[0], a[1]+t*dy-p[1]) <= tol
+}
+
+// polygonLocation returns location of the point relative to polygon.
+func polygonLocation(poly orb.Polygon, p orb.Point, tol float64) Location {
+	for _, r := range poly {
+		for i := 1; i < len(r); i++ {
+			if pointOnSegment(r[i-1], r[i], p, tol) {
+				return Boundary
+			}
+		}
+	}
+	if !ringContainsPoint(poly[0], p) {
+		return Exterior
+	}
+	for _, h := range poly[1:] {
+		if ringContainsPoint(h, p) {
+			return Exterior
+		}
+	}
+	return Interior
+}
+
+// ringContainsPoint checks point is inside ring using crossing number, point must not be on the ring.
+func ringContainsPoint(r orb.Ring, p orb.Point) bool {
+	in := false
+	for i := 1; i < len(r); i++ {
+		a, b := r[i-1], r[i]
+		if (a[1] > p[1]) != (b[1] > p[1]) &&
+			p[0] < a[0]+(p[1]-a[1])*(b[0]-a[0])/(b[1]-a[1]) {
+			in = !in
+		}
+	}
+	return in
+}
@@ -0,0 +1,248 @@
+package planar
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestRelate(t *testing.T) {
+	square := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	holed := orb.Polygon{
+		{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+		{{3, 3}, {3, 6}, {6, 6}, {6, 3}, {3, 3}},
+	}
+
+	tests := []struct {
+		name string
+		a, b orb.Geometry
+		want string
+	}{
+		{
+			name: "equal points",
+			a:    orb.Point{1, 1},
+			b:    orb.Point{1, 1},
+			want: "0FFFFFFF2",
+		},
+		{
+			name: "disjoint points",
+			a:    orb.Point{1, 1},
+			b:    orb.Point{2, 2},
+			want: "FF0FFF0F2",
+		},
+		{
+			name: "point in line interior",
+			a:    orb.Point{1, 0},
+			b:    orb.LineString{{0, 0}, {2, 0}},
+			want: "0FFFFF102",
+		},
+		{
+			name: "point at line end",
+			a:    orb.Point{2, 0},
+			b:    orb.LineString{{0, 0}, {2, 0}},
+			want: "F0FFFF102",
+		},
+		{
+			name: "point in polygon",
+			a:    orb.Point{1, 1},
+			b:    square,
+			want: "0FFFFF212",
+		},
+		{
+			name: "point on polygon boundary",
+			a:    orb.Point{10, 5},
+			b:    square,
+			want: "F0FFFF212",
+		},
+		{
+			name: "point in polygon hole",
+			a:    orb.Point{4, 4},
+			b:    holed,
+			want: "FF0FFF212",
+		},
+		{
+			name: "crossing lines",
+			a:    orb.LineString{{0, 0}, {2, 2}},
+			b:    orb.LineString{{0, 2}, {2, 0}},
+			want: "0F1FF0102",
+		},
+		{
+			name: "lines touching at ends",
+			a:    orb.LineString{{0, 0}, {1, 1}},
+			b:    orb.LineString{{1, 1}, {2, 0}},
+			want: "FF1F00102",
+		},
+		{
+			name: "overlapping lines",
+			a:    orb.LineString{{0, 0}, {2, 0}},
+			b:    orb.LineString{{1, 0}, {3, 0}},
+			want: "1010F0102",
+		},
+		{
+			name: "closed line and line",
+			a:    orb.LineString{{0, 0}, {2, 0}, {2, 2}, {0, 0}},
+			b:    orb.LineString{{0, 0}, {-1, -1}},
+			want: "F01FFF102",
+		},
+		{
+			name: "line inside polygon",
+			a:    orb.LineString{{1, 1}, {5, 5}, {9, 1}},
+			b:    square,
+			want: "1FF0FF212",
+		},
+		{
+			name: "line crosses polygon",
+			a:    orb.LineString{{-1, 5}, {11, 5}},
+			b:    square,
+			want: "101FF0212",
+		},
+		{
+			name: "line on polygon boundary",
+			a:    orb.LineString{{0, 0}, {10, 0}},
+			b:    square,
+			want: "F1FF0F212",
+		},
+		{
+			name: "overlapping polygons",
+			a:    square,
+			b:    orb.Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}},
+			want: "212101212",
+		},
+		{
+			name: "polygons touching by edge",
+			a:    square,
+			b:    orb.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}},
+			want: "FF2F11212",
+		},
+		{
+			name: "polygons touching by corner",
+			a:    square,
+			b:    orb.Polygon{{{10, 10}, {20, 10}, {20, 20}, {10, 20}, {10, 10}}},
+			want: "FF2F01212",
+		},
+		{
+			name: "polygon within polygon",
+			a:    orb.Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}},
+			b:    square,
+			want: "2FF1FF212",
+		},
+		{
+			name: "polygon in hole",
+			a:    orb.Polygon{{{4, 4}, {5, 4}, {5, 5}, {4, 5}, {4, 4}}},
+			b:    holed,
+			want: "FF2FF1212",
+		},
+		{
+			name: "equal polygons with different vertices",
+			a:    square,
+			b:    orb.Polygon{{{0, 0}, {5, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}},
+			want: "2FFF1FFF2",
+		},
+		{
+			name: "bound and polygon",
+			a:    orb.Bound{Min: orb.Point{0, 0}, Max: orb.Point{10, 10}},
+			b:    square,
+			want: "2FFF1FFF2",
+		},
+		{
+			name: "multipolygon and point",
+			a: orb.MultiPolygon{
+				square,
+				{{{20, 20}, {30, 20}, {30, 30}, {20, 30}, {20, 20}}},
+			},
+			b:    orb.MultiPoint{{25, 25}, {40, 40}},
+			want: "0F2FF10F2",
+		},
+		{
+			name: "collection and line",
+			a:    orb.Collection{square, orb.Point{20, 20}},
+			b:    orb.LineString{{5, 5}, {20, 20}},
+			want: "1020F11F2",
+		},
+		{
+			name: "empty geometry",
+			a:    orb.MultiPoint{},
+			b:    square,
+			want: "FFFFFF212",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Relate(tt.a, tt.b)
+			if got := m.String(); got != tt.want {
+				t.Errorf("Relate() = %v, want %v", got, tt.want)
+			}
+			if got := Relate(tt.b, tt.a).String(); got != m.Transpose().String() {
+				t.Errorf("Relate(b, a) = %v, want transposed %v", got, m.Transpose())
+			}
+		})
+	}
+}
+
+func TestRelatePredicates(t *testing.T) {
+	square := orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}
+	inner := orb.Polygon{{{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}}
+	neighbor := orb.Polygon{{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}}
+	overlapping := orb.Polygon{{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}
+	crossing := orb.LineString{{-1, 5}, {11, 5}}
+	edge := orb.LineString{{0, 0}, {10, 0}}
+
+	type predicate func(a, b orb.Geometry) bool
+	tests := []struct {
+		name string
+		f    predicate
+		a, b orb.Geometry
+		want bool
+	}{
+		{"equals lines", Equals, orb.LineString{{0, 0}, {2, 0}}, orb.LineString{{0, 0}, {1, 0}, {2, 0}}, true},
+		{"equals reversed lines", Equals, orb.LineString{{0, 0}, {2, 0}}, orb.LineString{{2, 0}, {0, 0}}, true},
+		{"not equals polygons", Equals, square, inner, false},
+		{"disjoint", Disjoint, inner, neighbor, true},
+		{"not disjoint", Disjoint, square, neighbor, false},
+		{"touches polygons", Touches, square, neighbor, true},
+		{"not touches overlapping", Touches, square, overlapping, false},
+		{"touches line on edge", Touches, edge, square, true},
+		{"not touches points", Touches, orb.Point{1, 1}, orb.Point{1, 1}, false},
+		{"crosses line polygon", Crosses, crossing, square, true},
+		{"crosses polygon line", Crosses, square, crossing, true},
+		{"crosses lines", Crosses, orb.LineString{{0, 0}, {2, 2}}, orb.LineString{{0, 2}, {2, 0}}, true},
+		{"not crosses overlapping lines", Crosses, orb.LineString{{0, 0}, {2, 0}}, orb.LineString{{1, 0}, {3, 0}}, false},
+		{"not crosses line inside", Crosses, orb.LineString{{1, 1}, {2, 2}}, square, false},
+		{"overlaps polygons", Overlaps, square, overlapping, true},
+		{"not overlaps inner", Overlaps, inner, square, false},
+		{"overlaps lines", Overlaps, orb.LineString{{0, 0}, {2, 0}}, orb.LineString{{1, 0}, {3, 0}}, true},
+		{"not overlaps different dimensions", Overlaps, crossing, square, false},
+		{"within", Within, inner, square, true},
+		{"not within", Within, square, inner, false},
+		{"not within boundary", Within, edge, square, false},
+		{"covers boundary", Covers, square, edge, true},
+		{"covers inner", Covers, square, inner, true},
+		{"not covers", Covers, square, overlapping, false},
+		{"covered by", CoveredBy, orb.Point{0, 5}, square, true},
+		{"not covered by", CoveredBy, crossing, square, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.f(tt.a, tt.b); got != tt.want {
+				t.Errorf("got %v, want %v, matrix %v", got, tt.want, Relate(tt.a, tt.b))
+			}
+		})
+	}
+}
+
+func TestIntersectionMatrix_Matches(t *testing.T) {
+	m := Relate(orb.LineString{{0, 0}, {2, 2}}, orb.LineString{{0, 2}, {2, 0}})
+	for pattern, want := range map[string]bool{
+		"0F1FF0102": true,
+		"T*T******": true,
+		"*********": true,
+		"1********": false,
+		"F********": false,
+		"0F1FF010":  false,
+		"0F1FF010X": false,
+	} {
+		if got := m.Matches(pattern); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", pattern, got, want)
+		}
+	}
+}
@@ -0,0 +1,467 @@
+package planar
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// Simplify simplifies lines and rings of geometry by Douglas-Peucker algorithm,
+// vertices are removed if they are not farther than tolerance from the simplified line.
+// Simplification preserves topology: simplified lines and rings don't intersect
+// each other or themselves if they didn't intersect before, rings keep at least three vertices
+// and holes stay inside of shells.
+func Simplify(geom orb.Geometry, tolerance float64) orb.Geometry {
+	s := newSimplifier(geom)
+	for ci, c := range s.chains {
+		if !c.simplifiable() {
+			continue
+		}
+		if c.closed {
+			n := len(c.pts) - 1
+			k := farthestPoint(c.pts, 0, n)
+			s.douglasPeucker(ci, 0, k, tolerance)
+			s.douglasPeucker(ci, k, n, tolerance)
+			continue
+		}
+		s.douglasPeucker(ci, 0, len(c.pts)-1, tolerance)
+	}
+	return s.geometry(geom)
+}
+
+// SimplifyVW simplifies lines and rings of geometry by Visvalingam-Whyatt algorithm,
+// vertices are removed while the area of triangle formed by the vertex and its neighbors is less than area.
+// Simplification preserves topology as Simplify.
+func SimplifyVW(geom orb.Geometry, area float64) orb.Geometry {
+	s := newSimplifier(geom)
+	for ci, c := range s.chains {
+		if c.simplifiable() {
+			s.visvalingam(ci, area)
+		}
+	}
+	return s.geometry(geom)
+}
+
+// Densify inserts points to segments of lines and rings of geometry,
+// so distance between consecutive points is not greater than maxSpacing.
+func Densify(geom orb.Geometry, maxSpacing float64) orb.Geometry {
+	if maxSpacing <= 0 {
+		return orb.Clone(geom)
+	}
+	return densify(geom, func(p, q orb.Point) []orb.Point {
+		n := int(math.Ceil(math.Hypot(q[0]-p[0], q[1]-p[1]) / maxSpacing))
+		pp := make([]orb.Point, 0, n)
+		for i := 1; i < n; i++ {
+			f := float64(i) / float64(n)
+			pp = append(pp, orb.Point{p[0] + (q[0]-p[0])*f, p[1] + (q[1]-p[1])*f})
+		}
+		return pp
+	})
+}
+
+// DensifyFunc inserts points returned by insert function between every two consecutive points of lines and rings.
+func DensifyFunc(geom orb.Geometry, insert func(p, q orb.Point) []orb.Point) orb.Geometry {
+	return densify(geom, insert)
+}
+
+func densify(geom orb.Geometry, insert func(p, q orb.Point) []orb.Point) orb.Geometry {
+	points := func(pp []orb.Point) []orb.Point {
+		if len(pp) == 0 {
+			return nil
+		}
+		out := make([]orb.Point, 0, len(pp))
+		out = append(out, pp[0])
+		for i := 1; i < len(pp); i++ {
+			out = append(out, insert(pp[i-1], pp[i])...)
+			out = append(out, pp[i])
+		}
+		return out
+	}
+	switch g := geom.(type) {
+	case orb.LineString:
+		return orb.LineString(points(g))
+	case orb.MultiLineString:
+		out := make(orb.MultiLineString, len(g))
+		for i, l := range g {
+			out[i] = points(l)
+		}
+		return out
+	case orb.Ring:
+		return orb.Ring(points(g))
+	case orb.Polygon:
+		out := make(orb.Polygon, len(g))
+		for i, r := range g {
+			out[i] = points(r)
+		}
+		return out
+	case orb.MultiPolygon:
+		out := make(orb.MultiPolygon, len(g))
+		for i, p := range g {
+			out[i] = densify(p, insert).(orb.Polygon)
+		}
+		return out
+	case orb.Bound:
+		return densify(g.ToPolygon(), insert)
+	case orb.Collection:
+		out := make(orb.Collection, len(g))
+		for i, g := range g {
+			out[i] = densify(g, insert)
+		}
+		return out
+	}
+	return orb.Clone(geom)
+}
+
+// simplifyChain is a line or a ring of simplified geometry,
+// removed vertices are excluded from the linked list of kept vertices.
+type simplifyChain struct {
+	// pts are points without consecutive duplicates, the last point of ring is equal to the first one
+	pts        []orb.Point
+	closed     bool
+	next, prev []int
+	kept       int
+}
+
+// simplifiable checks chain has vertices that can be removed.
+func (c *simplifyChain) simplifiable() bool {
+	if c.closed {
+		return len(c.pts) > 4
+	}
+	return len(c.pts) > 2
+}
+
+// minKept returns minimal count of vertices of the simplified chain.
+func (c *simplifyChain) minKept() int {
+	if c.closed {
+		return 4
+	}
+	return 2
+}
+
+// simplifyKey identifies a vertex or a segment between kept vertices from and to of the chain.
+type simplifyKey struct {
+	chain, from, to int
+}
+
+// simplifier keeps segments and vertices of all chains of geometry in the grid index,
+// so the shortcut of vertices can be checked against the current state of simplification.
+type simplifier struct {
+	chains   []*simplifyChain
+	cell     float64
+	segments map[[2]int64]map[simplifyKey]struct{}
+	vertices map[[2]int64]map[simplifyKey]struct{}
+}
+
+func newSimplifier(geom orb.Geometry) *simplifier {
+	s := &simplifier{
+		segments: map[[2]int64]map[simplifyKey]struct{}{},
+		vertices: map[[2]int64]map[simplifyKey]struct{}{},
+	}
+	s.collect(geom)
+	total := 0
+	var bound orb.Bound
+	for i, c := range s.chains {
+		total += len(c.pts)
+		if i == 0 {
+			bound = orb.MultiPoint(c.pts).Bound()
+			continue
+		}
+		bound = bound.Union(orb.MultiPoint(c.pts).Bound())
+	}
+	s.cell = max(bound.Max[0]-bound.Min[0], bound.Max[1]-bound.Min[1]) / math.Sqrt(float64(max(total, 1)))
+	if s.cell == 0 || math.IsNaN(s.cell) || math.IsInf(s.cell, 0) {
+		s.cell = 1
+	}
+	for ci, c := range s.chains {
+		n := len(c.pts)
+		c.next, c.prev, c.kept = make([]int, n), make([]int, n), n
+		for i := range c.pts {
+			c.next[i], c.prev[i] = i+1, i-1
+			if i > 0 {
+				s.insert(s.segments, simplifyKey{ci, i - 1, i}, orb.MultiPoint{c.pts[i-1], c.pts[i]}.Bound())
+			}
+			if !c.closed || i < n-1 {
+				s.insert(s.vertices, simplifyKey{ci, i, i}, c.pts[i].Bound())
+			}
+		}
+	}
+	return s
+}
+
+// collect adds chains of geometry in the order of geometry method.
+func (s *simplifier) collect(geom orb.Geometry) {
+	add := func(pp []orb.Point, closed bool) {
+		s.chains = append(s.chains, &simplifyChain{pts: dedupPoints(pp), closed: closed})
+	}
+	switch g := geom.(type) {
+	case orb.LineString:
+		add(g, len(g) > 3 && g[0] == g[len(g)-1])
+	case orb.MultiLineString:
+		for _, l := range g {
+			s.collect(l)
+		}
+	case orb.Ring:
+		add(g, g.Closed())
+	case orb.Polygon:
+		for _, r := range g {
+			s.collect(r)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			s.collect(p)
+		}
+	case orb.Collection:
+		for _, g := range g {
+			s.collect(g)
+		}
+	}
+}
+
+// geometry returns simplified geometry, chains are used in the order of collect method.
+func (s *simplifier) geometry(geom orb.Geometry) orb.Geometry {
+	pos := 0
+	var build func(geom orb.Geometry) orb.Geometry
+	build = func(geom orb.Geometry) orb.Geometry {
+		switch g := geom.(type) {
+		case orb.LineString:
+			pos++
+			return orb.LineString(s.chains[pos-1].keptPoints())
+		case orb.MultiLineString:
+			out := make(orb.MultiLineString, len(g))
+			for i, l := range g {
+				out[i] = build(l).(orb.LineString)
+			}
+			return out
+		case orb.Ring:
+			pos++
+			return orb.Ring(s.chains[pos-1].keptPoints())
+		case orb.Polygon:
+			out := make(orb.Polygon, len(g))
+			for i, r := range g {
+				out[i] = build(r).(orb.Ring)
+			}
+			return out
+		case orb.MultiPolygon:
+			out := make(orb.MultiPolygon, len(g))
+			for i, p := range g {
+				out[i] = build(p).(orb.Polygon)
+			}
+			return out
+		case orb.Collection:
+			out := make(orb.Collection, len(g))
+			for i, g := range g {
+				out[i] = build(g)
+			}
+			return out
+		}
+		return orb.Clone(geom)
+	}
+	return build(geom)
+}
+
+func (c *simplifyChain) keptPoints() []orb.Point {
+	if len(c.pts) == 0 {
+		return nil
+	}
+	out := make([]orb.Point, 0, c.kept)
+	for i := 0; i < len(c.pts); i = c.next[i] {
+		out = append(out, c.pts[i])
+	}
+	return out
+}
+
+func (s *simplifier) douglasPeucker(ci, i, j int, tolerance float64) {
+	if j-i < 2 {
+		return
+	}
+	c := s.chains[ci]
+	k := farthestPoint(c.pts, i, j)
+	if segmentPointDistance(c.pts[i], c.pts[j], c.pts[k]) <= tolerance && s.shortcut(ci, i, j, c.pts[i:j+1]) {
+		return
+	}
+	s.douglasPeucker(ci, i, k, tolerance)
+	s.douglasPeucker(ci, k, j, tolerance)
+}
+
+func (s *simplifier) visvalingam(ci int, area float64) {
+	c := s.chains[ci]
+	last := len(c.pts) - 1
+	// the first vertex of ring is kept as the vertex of line
+	version := make([]int, len(c.pts))
+	h := &vertexHeap{}
+	push := func(k int) {
+		if k <= 0 || k >= last {
+			return
+		}
+		version[k]++
+		p, q := c.pts[c.prev[k]], c.pts[c.next[k]]
+		heap.Push(h, vertexArea{k: k, version: version[k], area: math.Abs(orientation(p, c.pts[k], q)) / 2})
+	}
+	for k := 1; k < last; k++ {
+		push(k)
+	}
+	for h.Len() > 0 {
+		v := heap.Pop(h).(vertexArea)
+		if v.version != version[v.k] {
+			continue
+		}
+		if v.area >= area {
+			return
+		}
+		p, q := c.prev[v.k], c.next[v.k]
+		if !s.shortcut(ci, p, q, []orb.Point{c.pts[p], c.pts[v.k], c.pts[q]}) {
+			continue
+		}
+		push(p)
+		push(q)
+	}
+}
+
+// shortcut replaces kept vertices of chain between i and j by segment from i to j
+// if it doesn't change topology of geometry: the new segment doesn't intersect other segments
+// except at its ends and the area between section and the new segment doesn't contain other vertices.
+func (s *simplifier) shortcut(ci, i, j int, section []orb.Point) bool {
+	c := s.chains[ci]
+	removed := 0
+	for k := c.next[i]; k != j; k = c.next[k] {
+		removed++
+	}
+	if c.kept-removed < c.minKept() {
+		return false
+	}
+	a, b := c.pts[i], c.pts[j]
+	if a == b {
+		return false
+	}
+	for key := range s.query(s.segments, orb.MultiPoint{a, b}.Bound()) {
+		if key.chain == ci && key.from >= i && key.to <= j {
+			continue
+		}
+		other := s.chains[key.chain]
+		if shortcutIntersects(a, b, other.pts[key.from], other.pts[key.to]) {
+			return false
+		}
+	}
+	area := orb.Polygon{append(append(orb.Ring{}, section...), a)}
+	for key := range s.query(s.vertices, orb.MultiPoint(section).Bound()) {
+		if key.chain == ci && key.from > i && key.from < j {
+			continue
+		}
+		p := s.chains[key.chain].pts[key.from]
+		if p == a || p == b {
+			continue
+		}
+		if polygonLocation(area, p, 0) != Exterior {
+			return false
+		}
+	}
+	prev := i
+	for k := c.next[i]; k != j; k = c.next[k] {
+		s.remove(s.segments, simplifyKey{ci, prev, k}, orb.MultiPoint{c.pts[prev], c.pts[k]}.Bound())
+		s.remove(s.vertices, simplifyKey{ci, k, k}, c.pts[k].Bound())
+		prev = k
+	}
+	s.remove(s.segments, simplifyKey{ci, prev, j}, orb.MultiPoint{c.pts[prev], c.pts[j]}.Bound())
+	s.insert(s.segments, simplifyKey{ci, i, j}, orb.MultiPoint{a, b}.Bound())
+	c.next[i], c.prev[j] = j, i
+	c.kept -= removed
+	return true
+}
+
+// shortcutIntersects checks segment ab has common points with segment pq other than a and b.
+func shortcutIntersects(a, b, p, q orb.Point) bool {
+	if !segmentsIntersect(a, b, p, q) {
+		return false
+	}
+	d1, d2 := orientation(a, b, p), orientation(a, b, q)
+	if d1 == 0 && d2 == 0 {
+		// collinear segments, overlap of projections
+		dx, dy := b[0]-a[0], b[1]-a[1]
+		l2 := dx*dx + dy*dy
+		t1 := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / l2
+		t2 := ((q[0]-a[0])*dx + (q[1]-a[1])*dy) / l2
+		return max(min(t1, t2), 0) < min(max(t1, t2), 1)
+	}
+	d3, d4 := orientation(p, q, a), orientation(p, q, b)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	// segments touch, ends of pq on ab other than a and b are bad
+	for _, x := range [2]orb.Point{p, q} {
+		if x != a && x != b && pointOnSegment(a, b, x, 0) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *simplifier) cells(b orb.Bound, fn func(key [2]int64)) {
+	x0, x1 := int64(math.Floor(b.Min[0]/s.cell)), int64(math.Floor(b.Max[0]/s.cell))
+	y0, y1 := int64(math.Floor(b.Min[1]/s.cell)), int64(math.Floor(b.Max[1]/s.cell))
+	for x := x0; x <= x1; x++ {
+		for y := y0; y <= y1; y++ {
+			fn([2]int64{x, y})
+		}
+	}
+}
+
+func (s *simplifier) insert(grid map[[2]int64]map[simplifyKey]struct{}, key simplifyKey, b orb.Bound) {
+	s.cells(b, func(cell [2]int64) {
+		if grid[cell] == nil {
+			grid[cell] = map[simplifyKey]struct{}{}
+		}
+		grid[cell][key] = struct{}{}
+	})
+}
+
+func (s *simplifier) remove(grid map[[2]int64]map[simplifyKey]struct{}, key simplifyKey, b orb.Bound) {
+	s.cells(b, func(cell [2]int64) {
+		delete(grid[cell], key)
+	})
+}
+
+func (s *simplifier) query(grid map[[2]int64]map[simplifyKey]struct{}, b orb.Bound) map[simplifyKey]struct{} {
+	out := map[simplifyKey]struct{}{}
+	s.cells(b, func(cell [2]int64) {
+		for key := range grid[cell] {
+			out[key] = struct{}{}
+		}
+	})
+	return out
+}
+
+// farthestPoint returns index of point between i and j that is the farthest from segment of points i and j.
+func farthestPoint(pp []orb.Point, i, j int) int {
+	k, dist := i, -1.
+	for n := i + 1; n < j; n++ {
+		if d := segmentPointDistance(pp[i], pp[j], pp[n]); d > dist {
+			k, dist = n, d
+		}
+	}
+	return k
+}
+
+func segmentPointDistance(a, b, p orb.Point) float64 {
+	c := segmentClosestPoint(a, b, p)
+	return math.Hypot(p[0]-c[0], p[1]-c[1])
+}
+
+// vertexArea is an effective area of the vertex in Visvalingam-Whyatt algorithm.
+type vertexArea struct {
+	k, version int
+	area       float64
+}
+
+type vertexHeap []vertexArea
+
+func (h vertexHeap) Len() int           { return len(h) }
+func (h vertexHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+func (h vertexHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *vertexHeap) Push(x any)        { *h = append(*h, x.(vertexArea)) }
+func (h *vertexHeap) Pop() any {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
@@ -0,0 +1,141 @@
+package planar
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestSimplify(t *testing.T) {
+	tests := []struct {
+		name      string
+		geom      orb.Geometry
+		tolerance float64
+		expected  orb.Geometry
+	}{
+		{
+			name:      "line",
+			geom:      orb.LineString{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 5}, {4, 6}, {5, 7}, {6, 8.1}, {7, 9}, {8, 9}, {9, 9}},
+			tolerance: 1,
+			expected:  orb.LineString{{0, 0}, {2, -0.1}, {3, 5}, {7, 9}, {9, 9}},
+		},
+		{
+			name:      "repeated points",
+			geom:      orb.LineString{{0, 0}, {0, 0}, {5, 0}, {5, 0}},
+			tolerance: 0,
+			expected:  orb.LineString{{0, 0}, {5, 0}},
+		},
+		{
+			name:      "ring collapse",
+			geom:      orb.Polygon{{{0, 0}, {5, 0.1}, {10, 0}, {10, 10}, {5, 10.1}, {0, 10}, {0, 0}}},
+			tolerance: 100,
+			expected:  orb.Polygon{{{0, 0}, {10, 10}, {0, 10}, {0, 0}}},
+		},
+		{
+			name: "hole stays inside",
+			geom: orb.Polygon{
+				{{0, 0}, {5, -1}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{4.5, -0.6}, {4.5, -0.3}, {5.5, -0.3}, {5.5, -0.6}, {4.5, -0.6}},
+			},
+			tolerance: 2,
+			expected: orb.Polygon{
+				{{0, 0}, {5, -1}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{4.5, -0.6}, {5.5, -0.3}, {5.5, -0.6}, {4.5, -0.6}},
+			},
+		},
+		{
+			name: "lines don't cross",
+			geom: orb.MultiLineString{
+				{{0, 0}, {5, 2}, {10, 0}},
+				{{4, 1}, {6, 1}},
+			},
+			tolerance: 5,
+			expected: orb.MultiLineString{
+				{{0, 0}, {5, 2}, {10, 0}},
+				{{4, 1}, {6, 1}},
+			},
+		},
+		{
+			name:      "self intersection",
+			geom:      orb.LineString{{0, 0}, {5, 1}, {10, 0}, {10, -5}, {6, -5}, {5, 0.5}, {4, -5}, {2, -5.2}, {0, -5}},
+			tolerance: 1.5,
+			expected:  orb.LineString{{0, 0}, {5, 1}, {10, 0}, {10, -5}, {6, -5}, {5, 0.5}, {4, -5}, {0, -5}},
+		},
+		{name: "point", geom: orb.Point{1, 2}, tolerance: 1, expected: orb.Point{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Simplify(tt.geom, tt.tolerance)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+			if valid, reason := IsValid(got); !valid {
+				t.Errorf("simplified geometry is invalid: %s", reason)
+			}
+		})
+	}
+}
+
+func TestSimplifyVW(t *testing.T) {
+	tests := []struct {
+		name     string
+		geom     orb.Geometry
+		area     float64
+		expected orb.Geometry
+	}{
+		{
+			name:     "line",
+			geom:     orb.LineString{{0, 0}, {1, 0.1}, {2, 0}, {3, 3}, {4, 0}},
+			area:     1,
+			expected: orb.LineString{{0, 0}, {2, 0}, {3, 3}, {4, 0}},
+		},
+		{
+			name:     "ring collapse",
+			geom:     orb.Ring{{0, 0}, {5, 0.1}, {10, 0}, {10, 10}, {5, 10.1}, {0, 10}, {0, 0}},
+			area:     1000,
+			expected: orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 0}},
+		},
+		{
+			name: "hole stays inside",
+			geom: orb.Polygon{
+				{{0, 0}, {5, -1}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{4.5, -0.6}, {4.5, -0.3}, {5.5, -0.3}, {5.5, -0.6}, {4.5, -0.6}},
+			},
+			area: 10,
+			expected: orb.Polygon{
+				{{0, 0}, {5, -1}, {10, 0}, {10, 10}, {0, 10}, {0, 0}},
+				{{4.5, -0.6}, {5.5, -0.3}, {5.5, -0.6}, {4.5, -0.6}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SimplifyVW(tt.geom, tt.area)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDensify(t *testing.T) {
+	got := Densify(orb.Polygon{{{0, 0}, {10, 0}, {10, 2}, {0, 0}}}, 4).(orb.Polygon)
+	expected := orb.Polygon{{{0, 0}, {10. / 3, 0}, {20. / 3, 0}, {10, 0}, {10, 2}, {20. / 3, 4. / 3}, {10. / 3, 2. / 3}, {0, 0}}}
+	if len(got[0]) != len(expected[0]) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i, p := range got[0] {
+		if math.Abs(p[0]-expected[0][i][0]) > 1e-9 || math.Abs(p[1]-expected[0][i][1]) > 1e-9 {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	}
+	if line := Densify(orb.LineString{{0, 0}, {3, 4}}, 5).(orb.LineString); len(line) != 2 {
+		t.Errorf("expected no inserted points, got %v", line)
+	}
+	long := Densify(orb.LineString{{0, 0}, {100, 0}}, 3).(orb.LineString)
+	if len(long) != 35 {
+		t.Errorf("expected 35 points, got %d", len(long))
+	}
+}
@@ -0,0 +1,421 @@
+package planar
+
+import (
+	"math"
+	"sort"
+
+	"github.com/paulmach/orb"
+)
+
+// ValidityReason describes why geometry is not valid, empty reason means valid geometry.
+type ValidityReason string
+
+const (
+	ReasonInvalidCoordinate   ValidityReason = "invalid coordinate"
+	ReasonTooFewPoints        ValidityReason = "too few points"
+	ReasonNotClosed           ValidityReason = "ring is not closed"
+	ReasonSelfIntersection    ValidityReason = "self-intersection"
+	ReasonRingIntersection    ValidityReason = "ring intersection"
+	ReasonHoleOutsideShell    ValidityReason = "hole outside shell"
+	ReasonNestedHoles         ValidityReason = "nested holes"
+	ReasonOverlappingPolygons ValidityReason = "overlapping polygons"
+	ReasonWrongOrientation    ValidityReason = "wrong ring orientation"
+)
+
+// IsValid checks geometry by OGC simple features validity rules:
+// coordinates are finite, lines have at least two distinct points,
+// rings are closed, have at least three distinct points and don't intersect themselves,
+// holes are inside of shell, rings of polygon and polygons of multipolygon touch only at points
+// and interiors of polygons of multipolygon don't intersect.
+// Shells must be counterclockwise and holes clockwise, as MakeValid returns them.
+// Repeated points are not checked, as OGC allows them.
+func IsValid(geom orb.Geometry) (bool, ValidityReason) {
+	reason := validityReason(geom)
+	return reason == "", reason
+}
+
+func validityReason(geom orb.Geometry) ValidityReason {
+	switch g := geom.(type) {
+	case orb.Point:
+		return pointValidity(g)
+	case orb.MultiPoint:
+		for _, p := range g {
+			if r := pointValidity(p); r != "" {
+				return r
+			}
+		}
+	case orb.LineString:
+		return lineValidity(g)
+	case orb.MultiLineString:
+		for _, l := range g {
+			if r := lineValidity(l); r != "" {
+				return r
+			}
+		}
+	case orb.Ring:
+		return ringValidity(g)
+	case orb.Polygon:
+		return polygonValidity(g)
+	case orb.MultiPolygon:
+		for _, p := range g {
+			if r := polygonValidity(p); r != "" {
+				return r
+			}
+		}
+		for i := range g {
+			for j := i + 1; j < len(g); j++ {
+				if !g[i].Bound().Intersects(g[j].Bound()) {
+					continue
+				}
+				m := Relate(g[i], g[j])
+				if m[Interior][Interior] != DimFalse || m[Boundary][Boundary] == DimLine {
+					return ReasonOverlappingPolygons
+				}
+			}
+		}
+	case orb.Bound:
+		if r := pointValidity(g.Min); r != "" {
+			return r
+		}
+		return pointValidity(g.Max)
+	case orb.Collection:
+		for _, g := range g {
+			if r := validityReason(g); r != "" {
+				return r
+			}
+		}
+	}
+	return ""
+}
+
+func pointValidity(p orb.Point) ValidityReason {
+	for _, v := range p {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return ReasonInvalidCoordinate
+		}
+	}
+	return ""
+}
+
+func lineValidity(l orb.LineString) ValidityReason {
+	for _, p := range l {
+		if r := pointValidity(p); r != "" {
+			return r
+		}
+	}
+	if len(dedupPoints(l)) < 2 {
+		return ReasonTooFewPoints
+	}
+	return ""
+}
+
+func ringValidity(r orb.Ring) ValidityReason {
+	for _, p := range r {
+		if reason := pointValidity(p); reason != "" {
+			return reason
+		}
+	}
+	if len(r) < 4 {
+		return ReasonTooFewPoints
+	}
+	if !r.Closed() {
+		return ReasonNotClosed
+	}
+	pp := dedupPoints(r)
+	if len(pp) < 4 {
+		return ReasonTooFewPoints
+	}
+	if ringSelfIntersects(pp) {
+		return ReasonSelfIntersection
+	}
+	return ""
+}
+
+func polygonValidity(p orb.Polygon) ValidityReason {
+	if len(p) == 0 {
+		return ""
+	}
+	for n, r := range p {
+		if reason := ringValidity(r); reason != "" {
+			return reason
+		}
+		want := orb.CW
+		if n == 0 {
+			want = orb.CCW
+		}
+		if r.Orientation() != want {
+			return ReasonWrongOrientation
+		}
+	}
+	shell := orb.Polygon{p[0]}
+	for i, h := range p[1:] {
+		hole := orb.Polygon{h}
+		m := Relate(shell, hole)
+		if m[Boundary][Boundary] == DimLine {
+			return ReasonRingIntersection
+		}
+		if !m.IsCovers() {
+			if m[Interior][Interior] != DimFalse {
+				return ReasonRingIntersection
+			}
+			return ReasonHoleOutsideShell
+		}
+		for _, h2 := range p[i+2:] {
+			if !h.Bound().Intersects(h2.Bound()) {
+				continue
+			}
+			m := Relate(hole, orb.Polygon{h2})
+			if m[Boundary][Boundary] == DimLine {
+				return ReasonRingIntersection
+			}
+			if m[Interior][Interior] != DimFalse {
+				if m.IsCovers() || m.IsCoveredBy() {
+					return ReasonNestedHoles
+				}
+				return ReasonRingIntersection
+			}
+		}
+	}
+	return ""
+}
+
+// ringSelfIntersects checks closed ring without repeated points has intersecting segments,
+// adjacent segments may only share their common point.
+func ringSelfIntersects(pp []orb.Point) bool {
+	n := len(pp) - 1
+	type segment struct {
+		i      int
+		minX   float64
+		maxX   float64
+		bounds orb.Bound
+	}
+	segments := make([]segment, n)
+	for i := 0; i < n; i++ {
+		b := orb.MultiPoint{pp[i], pp[i+1]}.Bound()
+		segments[i] = segment{i: i, minX: b.Min[0], maxX: b.Max[0], bounds: b}
+	}
+	sort.Slice(segments, func(a, b int) bool {
+		return segments[a].minX < segments[b].minX
+	})
+	// sweep segments ordered by minimal x
+	for a := range segments {
+		s1 := segments[a]
+		for b := a + 1; b < len(segments) && segments[b].minX <= s1.maxX; b++ {
+			s2 := segments[b]
+			if !s1.bounds.Intersects(s2.bounds) {
+				continue
+			}
+			i, j := min(s1.i, s2.i), max(s1.i, s2.i)
+			p, q, r, s := pp[i], pp[i+1], pp[j], pp[j+1]
+			switch {
+			case j == i+1:
+				// r == q, spike if s goes back along the segment
+				if orientation(p, q, s) == 0 && (p[0]-q[0])*(s[0]-q[0])+(p[1]-q[1])*(s[1]-q[1]) > 0 {
+					return true
+				}
+			case i == 0 && j == n-1:
+				// s == p, spike if r goes back along the segment
+				if orientation(q, p, r) == 0 && (q[0]-p[0])*(r[0]-p[0])+(q[1]-p[1])*(r[1]-p[1]) > 0 {
+					return true
+				}
+			default:
+				if segmentsIntersect(p, q, r, s) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// segmentsIntersect checks segments pq and rs have common points.
+func segmentsIntersect(p, q, r, s orb.Point) bool {
+	d1, d2 := orientation(r, s, p), orientation(r, s, q)
+	d3, d4 := orientation(p, q, r), orientation(p, q, s)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return d1 == 0 && pointOnSegment(r, s, p, 0) ||
+		d2 == 0 && pointOnSegment(r, s, q, 0) ||
+		d3 == 0 && pointOnSegment(p, q, r, 0) ||
+		d4 == 0 && pointOnSegment(p, q, s, 0)
+}
+
+// MakeValid repairs geometry: removes invalid coordinates and repeated points, closes rings,
+// splits self-intersecting rings to valid polygons with counterclockwise shells and clockwise holes
+// and merges overlapping polygons of multipolygon.
+// The interior of polygon is defined by even-odd rule, so parts of rings that cover each other
+// even times are excluded and holes outside of shell become separate polygons.
+// Collapsed lines become points and collapsed rings are removed.
+// Returns nil if nothing left of geometry.
+func MakeValid(geom orb.Geometry) orb.Geometry {
+	switch g := geom.(type) {
+	case orb.Point:
+		if pointValidity(g) != "" {
+			return nil
+		}
+		return g
+	case orb.MultiPoint:
+		out := make(orb.MultiPoint, 0, len(g))
+		for _, p := range g {
+			if pointValidity(p) == "" {
+				out = append(out, p)
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case orb.LineString:
+		pp := validPoints(g)
+		switch len(pp) {
+		case 0:
+			return nil
+		case 1:
+			return pp[0]
+		}
+		return orb.LineString(pp)
+	case orb.MultiLineString:
+		out := make(orb.MultiLineString, 0, len(g))
+		for _, l := range g {
+			if pp := validPoints(l); len(pp) > 1 {
+				out = append(out, pp)
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case orb.Ring:
+		return makeValidPolygons(orb.MultiPolygon{{g}}, false)
+	case orb.Polygon:
+		return makeValidPolygons(orb.MultiPolygon{g}, false)
+	case orb.MultiPolygon:
+		return makeValidPolygons(g, true)
+	case orb.Bound:
+		return g
+	case orb.Collection:
+		out := make(orb.Collection, 0, len(g))
+		for _, g := range g {
+			if v := MakeValid(g); v != nil {
+				out = append(out, v)
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	}
+	return geom
+}
+
+// makeValidPolygons repairs every polygon and merges the results,
+// returns Polygon if there is only one polygon and multi isn't set.
+func makeValidPolygons(mp orb.MultiPolygon, multi bool) orb.Geometry {
+	var out orb.MultiPolygon
+	for _, p := range mp {
+		repaired := makeValidPolygon(p)
+		if len(repaired) == 0 {
+			continue
+		}
+		if len(out) == 0 {
+			out = repaired
+			continue
+		}
+		out = Union(out, repaired)
+	}
+	switch {
+	case len(out) == 0:
+		return nil
+	case len(out) == 1 && !multi:
+		return out[0]
+	}
+	return out
+}
+
+// makeValidPolygon nodes rings of polygon and selects edges with the interior on the one side only,
+// the interior is defined by even-odd rule.
+func makeValidPolygon(p orb.Polygon) orb.MultiPolygon {
+	var rings []orb.Ring
+	var bound orb.Bound
+	for _, r := range p {
+		pp := validPoints(r)
+		if len(pp) > 1 && pp[0] == pp[len(pp)-1] {
+			pp = pp[:len(pp)-1]
+		}
+		if len(pp) < 3 {
+			continue
+		}
+		ring := orb.Ring(append(pp, pp[0]))
+		if len(rings) == 0 {
+			bound = ring.Bound()
+		} else {
+			bound = bound.Union(ring.Bound())
+		}
+		rings = append(rings, ring)
+	}
+	if len(rings) == 0 {
+		return nil
+	}
+	var segments []*relateSegment
+	for _, r := range rings {
+		for i := 1; i < len(r); i++ {
+			segments = append(segments, &relateSegment{p: r[i-1], q: r[i]})
+		}
+	}
+	tol := relateTolerance(bound, bound)
+	for i, s1 := range segments {
+		sBound := orb.MultiPoint{s1.p, s1.q}.Bound().Pad(tol)
+		for _, s2 := range segments[i+1:] {
+			if sBound.Intersects(orb.MultiPoint{s2.p, s2.q}.Bound()) {
+				nodeSegments(s1, s2, tol)
+			}
+		}
+	}
+	inside := func(p orb.Point) bool {
+		in := false
+		for _, r := range rings {
+			if ringContainsPoint(r, p) {
+				in = !in
+			}
+		}
+		return in
+	}
+	nodes := newNodeIndex(tol)
+	seen := map[[2]orb.Point]bool{}
+	var edges []overlayEdge
+	for _, s := range segments {
+		points := s.splitNodes(tol)
+		for i := 1; i < len(points); i++ {
+			from, to := nodes.snap(points[i-1]), nodes.snap(points[i])
+			if from == to || seen[[2]orb.Point{from, to}] || seen[[2]orb.Point{to, from}] {
+				continue
+			}
+			seen[[2]orb.Point{from, to}] = true
+			dx, dy := to[0]-from[0], to[1]-from[1]
+			l := math.Hypot(dx, dy)
+			eps := max(l*1e-6, 8*tol)
+			mid := orb.Point{(from[0] + to[0]) / 2, (from[1] + to[1]) / 2}
+			left := inside(orb.Point{mid[0] - dy/l*eps, mid[1] + dx/l*eps})
+			right := inside(orb.Point{mid[0] + dy/l*eps, mid[1] - dx/l*eps})
+			switch {
+			case left && !right:
+				edges = append(edges, overlayEdge{from: from, to: to})
+			case right && !left:
+				edges = append(edges, overlayEdge{from: to, to: from})
+			}
+		}
+	}
+	return buildPolygons(edges)
+}
+
+// validPoints returns points with finite coordinates without consecutive duplicates.
+func validPoints(pp []orb.Point) []orb.Point {
+	out := make([]orb.Point, 0, len(pp))
+	for _, p := range pp {
+		if pointValidity(p) == "" {
+			out = append(out, p)
+		}
+	}
+	return dedupPoints(out)
+}
@@ -0,0 +1,131 @@
+package planar
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/planar"
+)
+
+func TestIsValid(t *testing.T) {
+	square := orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	tests := []struct {
+		name   string
+		geom   orb.Geometry
+		reason ValidityReason
+	}{
+		{name: "point", geom: orb.Point{1, 2}},
+		{name: "nan point", geom: orb.Point{math.NaN(), 2}, reason: ReasonInvalidCoordinate},
+		{name: "line", geom: orb.LineString{{0, 0}, {1, 1}, {1, 1}}},
+		{name: "collapsed line", geom: orb.LineString{{1, 1}, {1, 1}}, reason: ReasonTooFewPoints},
+		{name: "polygon", geom: orb.Polygon{square}},
+		{name: "repeated points", geom: orb.Polygon{{{0, 0}, {10, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}},
+		{name: "clockwise shell", geom: orb.Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}}, reason: ReasonWrongOrientation},
+		{name: "counterclockwise hole", geom: orb.Polygon{square, {{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}}, reason: ReasonWrongOrientation},
+		{name: "not closed", geom: orb.Polygon{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}, reason: ReasonNotClosed},
+		{name: "too few points", geom: orb.Polygon{{{0, 0}, {10, 0}, {0, 0}}}, reason: ReasonTooFewPoints},
+		{name: "repeated points only", geom: orb.Polygon{{{0, 0}, {10, 0}, {10, 0}, {0, 0}}}, reason: ReasonTooFewPoints},
+		{name: "bowtie", geom: orb.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}}, reason: ReasonSelfIntersection},
+		{name: "spike", geom: orb.Polygon{{{0, 0}, {10, 0}, {15, 0}, {10, 0}, {10, 10}, {0, 0}}}, reason: ReasonSelfIntersection},
+		{name: "self touching ring", geom: orb.Polygon{{{0, 0}, {10, 0}, {5, 5}, {10, 10}, {0, 10}, {5, 5}, {0, 0}}}, reason: ReasonSelfIntersection},
+		{name: "hole", geom: orb.Polygon{square, {{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}}},
+		{name: "hole touches shell", geom: orb.Polygon{square, {{0, 5}, {5, 8}, {5, 2}, {0, 5}}}},
+		{name: "hole outside shell", geom: orb.Polygon{square, {{20, 20}, {20, 30}, {30, 30}, {20, 20}}}, reason: ReasonHoleOutsideShell},
+		{name: "hole crosses shell", geom: orb.Polygon{square, {{5, 5}, {5, 15}, {15, 15}, {5, 5}}}, reason: ReasonRingIntersection},
+		{name: "hole shares edge", geom: orb.Polygon{square, {{0, 0}, {0, 5}, {5, 5}, {0, 0}}}, reason: ReasonRingIntersection},
+		{
+			name:   "nested holes",
+			geom:   orb.Polygon{square, {{1, 1}, {1, 9}, {9, 9}, {9, 1}, {1, 1}}, {{2, 2}, {2, 8}, {8, 8}, {8, 2}, {2, 2}}},
+			reason: ReasonNestedHoles,
+		},
+		{
+			name:   "overlapping holes",
+			geom:   orb.Polygon{square, {{1, 1}, {1, 5}, {5, 5}, {5, 1}, {1, 1}}, {{3, 3}, {3, 8}, {8, 8}, {8, 3}, {3, 3}}},
+			reason: ReasonRingIntersection,
+		},
+		{name: "touching polygons", geom: orb.MultiPolygon{{square}, {{{10, 10}, {20, 10}, {20, 20}, {10, 10}}}}},
+		{name: "adjacent polygons", geom: orb.MultiPolygon{{square}, {{{10, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 0}}}}, reason: ReasonOverlappingPolygons},
+		{name: "overlapping polygons", geom: orb.MultiPolygon{{square}, {{{5, 5}, {20, 5}, {20, 20}, {5, 5}}}}, reason: ReasonOverlappingPolygons},
+		{name: "collection", geom: orb.Collection{orb.Point{0, 0}, orb.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}}}, reason: ReasonSelfIntersection},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, reason := IsValid(tt.geom)
+			if valid != (tt.reason == "") || reason != tt.reason {
+				t.Errorf("expected reason %q, got %v %q", tt.reason, valid, reason)
+			}
+		})
+	}
+}
+
+func TestMakeValid(t *testing.T) {
+	square := orb.Ring{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	tests := []struct {
+		name     string
+		geom     orb.Geometry
+		area     float64
+		polygons int
+		holes    int
+	}{
+		{name: "valid", geom: orb.Polygon{square}, area: 100, polygons: 1},
+		{name: "clockwise not closed", geom: orb.Polygon{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}}, area: 100, polygons: 1},
+		{name: "repeated points", geom: orb.Polygon{{{0, 0}, {10, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}}, area: 100, polygons: 1},
+		{name: "bowtie", geom: orb.Polygon{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}}, area: 50, polygons: 2},
+		{name: "spike", geom: orb.Polygon{{{0, 0}, {10, 0}, {15, 0}, {10, 0}, {10, 10}, {0, 0}}}, area: 50, polygons: 1},
+		{name: "self touching ring", geom: orb.Polygon{{{0, 0}, {10, 0}, {5, 5}, {10, 10}, {0, 10}, {5, 5}, {0, 0}}}, area: 50, polygons: 2},
+		{name: "hole outside shell", geom: orb.Polygon{square, {{20, 20}, {20, 30}, {30, 30}, {20, 20}}}, area: 150, polygons: 2},
+		{name: "hole crosses shell", geom: orb.Polygon{square, {{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}, area: 150, polygons: 2},
+		{name: "counterclockwise hole", geom: orb.Polygon{square, {{2, 2}, {8, 2}, {8, 8}, {2, 8}, {2, 2}}}, area: 64, polygons: 1, holes: 1},
+		{name: "collapsed", geom: orb.Polygon{{{0, 0}, {10, 0}, {0, 0}}}},
+		{
+			name:     "overlapping polygons",
+			geom:     orb.MultiPolygon{{square}, {{{5, 5}, {15, 5}, {15, 15}, {5, 15}, {5, 5}}}},
+			area:     175,
+			polygons: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MakeValid(tt.geom)
+			if valid, reason := IsValid(got); !valid {
+				t.Fatalf("repaired geometry is invalid: %s %v", reason, got)
+			}
+			var mp orb.MultiPolygon
+			switch g := got.(type) {
+			case orb.Polygon:
+				mp = orb.MultiPolygon{g}
+			case orb.MultiPolygon:
+				mp = g
+			case nil:
+			default:
+				t.Fatalf("unexpected type %T", got)
+			}
+			if a := planar.Area(mp); math.Abs(a-tt.area) > 1e-9 {
+				t.Errorf("expected area %v, got %v", tt.area, a)
+			}
+			if len(mp) != tt.polygons {
+				t.Fatalf("expected %d polygons, got %d: %v", tt.polygons, len(mp), mp)
+			}
+			holes := 0
+			for _, p := range mp {
+				if signedArea(p[0]) <= 0 {
+					t.Errorf("shell is not counterclockwise: %v", p[0])
+				}
+				for _, h := range p[1:] {
+					if signedArea(h) >= 0 {
+						t.Errorf("hole is not clockwise: %v", h)
+					}
+					holes++
+				}
+			}
+			if holes != tt.holes {
+				t.Errorf("expected %d holes, got %d", tt.holes, holes)
+			}
+		})
+	}
+
+	if got := MakeValid(orb.LineString{{1, 1}, {1, 1}, {math.Inf(1), 0}}); got != (orb.Point{1, 1}) {
+		t.Errorf("expected collapsed line to be point, got %v", got)
+	}
+}
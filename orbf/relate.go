@@ -0,0 +1,223 @@
+package orbf
+
+import (
+	"math"
+
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+// Relate returns DE-9IM intersection matrix of WGS84 geometries.
+// Geometries at antimeridian are related in the continuous longitude window,
+// so the antimeridian doesn't become a part of their boundaries.
+func Relate(a, b orb.Geometry) planar.IntersectionMatrix {
+	geom1, geom2 := relatePair(a, b)
+	return planar.Relate(geom1, geom2)
+}
+
+// relatePair prepares WGS84 geometries for planar relate. If geometries cross or touch antimeridian,
+// their longitudes are moved to the 360° window centered at the geometry that crosses antimeridian,
+// only parts out of the window are split. Polygons that encircle pole are split at antimeridian.
+func relatePair(a, b orb.Geometry) (orb.Geometry, orb.Geometry) {
+	if a == nil || b == nil {
+		return projectPair(a, b)
+	}
+	ref := a
+	if !CrossesAntimeridian(a) && CrossesAntimeridian(b) {
+		ref = b
+	}
+	if !CrossesAntimeridian(ref) && !touchesAntimeridian(a) && !touchesAntimeridian(b) {
+		return projectPair(a, b)
+	}
+	center, ok := lonCenter(ref)
+	if !ok {
+		return projectPair(a, b)
+	}
+	a, b = lonWindow(a, center), lonWindow(b, center)
+	if IsPolar(a) || IsPolar(b) {
+		return a, b
+	}
+	return projectToMercator(a), projectToMercator(b)
+}
+
+// touchesAntimeridian checks geometry has points at ±180° longitude.
+func touchesAntimeridian(geom orb.Geometry) bool {
+	b := geom.Bound()
+	return b.Min.Lon() <= -180 || b.Max.Lon() >= 180
+}
+
+// lonWindow returns geometry with longitudes in range [center-180, center+180],
+// parts of geometry that cross the window edges are split.
+func lonWindow(geom orb.Geometry, center float64) orb.Geometry {
+	geom = project.Geometry(orb.Clone(geom), func(p orb.Point) orb.Point {
+		return orb.Point{NormalizeLon(p.Lon() - center), p.Lat()}
+	})
+	return project.Geometry(SplitAntimeridian(geom), func(p orb.Point) orb.Point {
+		return orb.Point{p.Lon() + center, p.Lat()}
+	})
+}
+
+// lonCenter returns center longitude of the first part of geometry that crosses antimeridian,
+// or of the first part if no one crosses. Returns false for rings that encircle pole.
+func lonCenter(geom orb.Geometry) (float64, bool) {
+	switch g := geom.(type) {
+	case orb.Point:
+		return g.Lon(), true
+	case orb.MultiPoint:
+		if len(g) > 0 {
+			return g[0].Lon(), true
+		}
+	case orb.Bound:
+		return g.Center().Lon(), true
+	case orb.LineString:
+		return pointsLonCenter(g, false)
+	case orb.Ring:
+		return pointsLonCenter(g, true)
+	case orb.MultiLineString:
+		for _, l := range g {
+			if pointsCrossAntimeridian(l) {
+				return pointsLonCenter(l, false)
+			}
+		}
+		if len(g) > 0 {
+			return pointsLonCenter(g[0], false)
+		}
+	case orb.Polygon:
+		if len(g) > 0 {
+			return pointsLonCenter(g[0], true)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			if CrossesAntimeridian(p) {
+				return lonCenter(p)
+			}
+		}
+		if len(g) > 0 {
+			return lonCenter(g[0])
+		}
+	case orb.Collection:
+		for _, g := range g {
+			if CrossesAntimeridian(g) {
+				return lonCenter(g)
+			}
+		}
+		if len(g) > 0 {
+			return lonCenter(g[0])
+		}
+	}
+	return 0, false
+}
+
+func pointsLonCenter(pp []orb.Point, closed bool) (float64, bool) {
+	u := unwrap(pp)
+	if len(u) == 0 {
+		return 0, false
+	}
+	if closed && math.Abs(u[len(u)-1].Lon()-u[0].Lon()) > 180 {
+		return 0, false
+	}
+	minLon, maxLon := u[0].Lon(), u[0].Lon()
+	for _, p := range u[1:] {
+		minLon, maxLon = math.Min(minLon, p.Lon()), math.Max(maxLon, p.Lon())
+	}
+	return (minLon + maxLon) / 2, true
+}
+
+// Disjoint checks WGS84 geometries have no common points.
+func Disjoint(a, b orb.Geometry) bool {
+	return Relate(a, b).IsDisjoint()
+}
+
+// Within checks WGS84 geometry a is within b.
+func Within(a, b orb.Geometry) bool {
+	return Relate(a, b).IsWithin()
+}
+
+// Covers checks every point of WGS84 geometry b is a point of a.
+func Covers(a, b orb.Geometry) bool {
+	return Relate(a, b).IsCovers()
+}
+
+// CoveredBy checks every point of WGS84 geometry a is a point of b.
+func CoveredBy(a, b orb.Geometry) bool {
+	return Relate(a, b).IsCoveredBy()
+}
+
+// Equals checks WGS84 geometries are topologically equal.
+func Equals(a, b orb.Geometry) bool {
+	return Relate(a, b).IsEquals(planar.GeometryDimension(a), planar.GeometryDimension(b))
+}
+
+// Touches checks WGS84 geometries have common points only on boundaries.
+func Touches(a, b orb.Geometry) bool {
+	return Relate(a, b).IsTouches(planar.GeometryDimension(a), planar.GeometryDimension(b))
+}
+
+// Crosses checks WGS84 geometries have some but not all interior points in common.
+func Crosses(a, b orb.Geometry) bool {
+	return Relate(a, b).IsCrosses(planar.GeometryDimension(a), planar.GeometryDimension(b))
+}
+
+// Overlaps checks WGS84 geometries of the same dimension overlap.
+func Overlaps(a, b orb.Geometry) bool {
+	return Relate(a, b).IsOverlaps(planar.GeometryDimension(a), planar.GeometryDimension(b))
+}
+
+// EdgeTouch checks boundaries of WGS84 geometries have common segment with non zero length.
+// Boundaries are split at antimeridian without cut edges, so edges at 180° and -180°
+// longitude are the same and polygons that cross antimeridian don't touch by cut edges.
+func EdgeTouch(a, b orb.Geometry) bool {
+	return planar.EdgeTouch(boundaryAntimeridian(a), boundaryAntimeridian(b))
+}
+
+// boundaryAntimeridian returns lines of the geometry boundary split at antimeridian,
+// segments along antimeridian are returned as separate lines at 180° longitude.
+func boundaryAntimeridian(geom orb.Geometry) orb.MultiLineString {
+	var out orb.MultiLineString
+	add := func(pp []orb.Point) {
+		for _, l := range splitLine(orb.LineString(pp)) {
+			start := 0
+			for i := 1; i < len(l); i++ {
+				if math.Abs(l[i-1].Lon()) != 180 || math.Abs(l[i].Lon()) != 180 {
+					continue
+				}
+				if i-1 > start {
+					out = append(out, l[start:i])
+				}
+				out = append(out, orb.LineString{{180, l[i-1].Lat()}, {180, l[i].Lat()}})
+				start = i
+			}
+			if len(l)-1 > start {
+				out = append(out, l[start:])
+			}
+		}
+	}
+	switch g := geom.(type) {
+	case orb.Bound:
+		add(g.ToRing())
+	case orb.LineString:
+		add(g)
+	case orb.MultiLineString:
+		for _, l := range g {
+			add(l)
+		}
+	case orb.Ring:
+		add(g)
+	case orb.Polygon:
+		for _, r := range g {
+			add(r)
+		}
+	case orb.MultiPolygon:
+		for _, p := range g {
+			for _, r := range p {
+				add(r)
+			}
+		}
+	case orb.Collection:
+		for _, g := range g {
+			out = append(out, boundaryAntimeridian(g)...)
+		}
+	}
+	return out
+}
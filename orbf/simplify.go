@@ -0,0 +1,76 @@
+package orbf
+
+import (
+	"math"
+
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/VGSML/geobin/orbf/sphere"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/project"
+)
+
+// Simplify simplifies lines and rings of WGS84 geometry by Douglas-Peucker algorithm with tolerance in meters.
+// Simplification preserves topology as planar.Simplify, kept vertices are not changed.
+// Geometry is simplified in azimuthal equidistant projection centered at the geometry,
+// so tolerance is accurate for geometries up to a few thousand kilometers at any latitude.
+func Simplify(geom orb.Geometry, meters float64) orb.Geometry {
+	return simplifyLocal(geom, func(geom orb.Geometry) orb.Geometry {
+		return planar.Simplify(geom, meters)
+	})
+}
+
+// SimplifyVW simplifies lines and rings of WGS84 geometry by Visvalingam-Whyatt algorithm
+// with the area threshold in square meters, see Simplify.
+func SimplifyVW(geom orb.Geometry, squareMeters float64) orb.Geometry {
+	return simplifyLocal(geom, func(geom orb.Geometry) orb.Geometry {
+		return planar.SimplifyVW(geom, squareMeters)
+	})
+}
+
+// Densify inserts points to segments of lines and rings of WGS84 geometry along great-circle arcs,
+// so distance between consecutive points is not greater than meters.
+// Longitudes of inserted points are in [-180, 180].
+func Densify(geom orb.Geometry, meters float64) orb.Geometry {
+	if meters <= 0 {
+		return orb.Clone(geom)
+	}
+	return planar.DensifyFunc(geom, func(p, q orb.Point) []orb.Point {
+		a, b := sphere.FromPoint(p), sphere.FromPoint(q)
+		angle := sphere.Angle(a, b)
+		n := int(math.Ceil(angle * orb.EarthRadius / meters))
+		if n < 2 || math.Sin(angle) < 1e-15 {
+			return nil
+		}
+		arc := sphere.ArcInterpolator(a, b)
+		pp := make([]orb.Point, 0, n-1)
+		for i := 1; i < n; i++ {
+			pp = append(pp, arc(float64(i)/float64(n)).Point())
+		}
+		return pp
+	})
+}
+
+// simplifyLocal simplifies geometry in local projection and restores original coordinates of kept vertices,
+// new vertices are projected back.
+func simplifyLocal(geom orb.Geometry, simplify func(orb.Geometry) orb.Geometry) orb.Geometry {
+	center, ok := sphereCenter(geom)
+	if !ok {
+		return orb.Clone(geom)
+	}
+	proj := newLocalProjection(center)
+	original := map[orb.Point]orb.Point{}
+	local := project.Geometry(orb.Clone(geom), func(p orb.Point) orb.Point {
+		lp := proj.toLocal(p)
+		if _, ok := original[lp]; !ok {
+			original[lp] = p
+		}
+		return lp
+	})
+	return project.Geometry(simplify(local), func(p orb.Point) orb.Point {
+		if o, ok := original[p]; ok {
+			return o
+		}
+		// vertex made by the simplification
+		return proj.toWGS84(p)
+	})
+}
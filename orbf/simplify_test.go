@@ -0,0 +1,62 @@
+package orbf
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+)
+
+func TestSimplify(t *testing.T) {
+	// 0.0001 degree of latitude is about 11m, 0.01 is about 1.1km
+	line := orb.LineString{{10, 60}, {10.01, 60.0001}, {10.02, 60}, {10.03, 60.01}, {10.04, 60}}
+	expected := orb.LineString{{10, 60}, {10.02, 60}, {10.03, 60.01}, {10.04, 60}}
+	if got := Simplify(line, 100); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := SimplifyVW(line, 100*100); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := Simplify(line, 5); !reflect.DeepEqual(got, line) {
+		t.Errorf("expected unchanged line, got %v", got)
+	}
+
+	antimeridian := orb.Polygon{{{179.9, -1}, {-179.9, -1}, {-179.9, 1}, {180, 1.0001}, {179.9, 1}, {179.9, -1}}}
+	expectedPoly := orb.Polygon{{{179.9, -1}, {-179.9, -1}, {-179.9, 1}, {179.9, 1}, {179.9, -1}}}
+	if got := Simplify(antimeridian, 100); !reflect.DeepEqual(got, expectedPoly) {
+		t.Errorf("expected %v, got %v", expectedPoly, got)
+	}
+	// simplification that makes new vertex in the middle of the line
+	got := simplifyLocal(orb.LineString{{10, 60}, {10.02, 60}}, func(geom orb.Geometry) orb.Geometry {
+		l := geom.(orb.LineString)
+		return orb.LineString{l[0], orb.Point{(l[0][0] + l[1][0]) / 2, (l[0][1] + l[1][1]) / 2}, l[1]}
+	}).(orb.LineString)
+	if mid := (orb.Point{10.01, 60}); len(got) != 3 || geo.Distance(got[1], mid) > 1 {
+		t.Errorf("expected new vertex near %v, got %v", mid, got)
+	}
+}
+
+func TestDensify(t *testing.T) {
+	line := orb.LineString{{179, 10}, {-179, 10}, {-179, 12}}
+	got := Densify(line, 10000).(orb.LineString)
+	if len(got) != 46 {
+		t.Fatalf("expected 46 points, got %d", len(got))
+	}
+	if got[0] != line[0] || got[len(got)-1] != line[2] {
+		t.Errorf("expected original ends, got %v %v", got[0], got[len(got)-1])
+	}
+	for i := 1; i < len(got); i++ {
+		if d := geo.DistanceHaversine(got[i-1], got[i]); d > 10000 {
+			t.Errorf("expected spacing not greater than 10000m, got %v", d)
+		}
+		// great circle between points of the same latitude bulges to the pole
+		if i < 22 && (got[i].Lat() <= 10 || math.Abs(got[i].Lon()) < 179) {
+			t.Errorf("expected point of great circle, got %v", got[i])
+		}
+	}
+	if got := Densify(orb.Point{1, 2}, 10); got != (orb.Point{1, 2}) {
+		t.Errorf("expected point, got %v", got)
+	}
+}
@@ -0,0 +1,94 @@
+package sphere
+
+import (
+	"math"
+
+	"github.com/paulmach/orb"
+)
+
+// Package to work with points on the unit sphere and great-circle arcs
+
+// Vector is a point on the unit sphere.
+type Vector [3]float64
+
+// FromPoint returns unit vector of WGS84 point.
+func FromPoint(p orb.Point) Vector {
+	lat, lon := p.Lat()*math.Pi/180, p.Lon()*math.Pi/180
+	return Vector{
+		math.Cos(lat) * math.Cos(lon),
+		math.Cos(lat) * math.Sin(lon),
+		math.Sin(lat),
+	}
+}
+
+// Point returns WGS84 point of the vector.
+func (v Vector) Point() orb.Point {
+	return orb.Point{
+		math.Atan2(v[1], v[0]) * 180 / math.Pi,
+		math.Atan2(v[2], math.Hypot(v[0], v[1])) * 180 / math.Pi,
+	}
+}
+
+func (v Vector) Dot(u Vector) float64 {
+	return v[0]*u[0] + v[1]*u[1] + v[2]*u[2]
+}
+
+func (v Vector) Cross(u Vector) Vector {
+	return Vector{
+		v[1]*u[2] - v[2]*u[1],
+		v[2]*u[0] - v[0]*u[2],
+		v[0]*u[1] - v[1]*u[0],
+	}
+}
+
+// Angle returns angle between unit vectors in radians.
+func Angle(a, b Vector) float64 {
+	c := a.Cross(b)
+	return math.Atan2(math.Sqrt(c.Dot(c)), a.Dot(b))
+}
+
+// ArcInterpolator returns function that interpolates point on the great-circle arc from a to b
+// (spherical linear interpolation) by fraction of the arc. Equal or antipodal points are interpolated linearly.
+func ArcInterpolator(a, b Vector) func(f float64) Vector {
+	omega := Angle(a, b)
+	sin := math.Sin(omega)
+	return func(f float64) Vector {
+		if sin < 1e-12 {
+			return Vector{
+				a[0] + (b[0]-a[0])*f,
+				a[1] + (b[1]-a[1])*f,
+				a[2] + (b[2]-a[2])*f,
+			}
+		}
+		ka, kb := math.Sin((1-f)*omega)/sin, math.Sin(f*omega)/sin
+		return Vector{
+			ka*a[0] + kb*b[0],
+			ka*a[1] + kb*b[1],
+			ka*a[2] + kb*b[2],
+		}
+	}
+}
+
+// ArcClosestPoint returns the point of great-circle arc from a to b closest to p
+// and index of the arc end if the point is one of the ends, else -1.
+func ArcClosestPoint(a, b, p Vector) (Vector, int) {
+	n := a.Cross(b)
+	l := math.Sqrt(n.Dot(n))
+	if l < 1e-15 {
+		return a, 0
+	}
+	n = Vector{n[0] / l, n[1] / l, n[2] / l}
+	// projection of p to the plane of great circle
+	k := p.Dot(n)
+	c := Vector{p[0] - n[0]*k, p[1] - n[1]*k, p[2] - n[2]*k}
+	if cl := math.Sqrt(c.Dot(c)); cl > 1e-15 {
+		c = Vector{c[0] / cl, c[1] / cl, c[2] / cl}
+		if a.Cross(c).Dot(n) >= 0 && c.Cross(b).Dot(n) >= 0 {
+			return c, -1
+		}
+	}
+	if Angle(p, a) <= Angle(p, b) {
+		return a, 0
+	}
+	return b, 1
+}
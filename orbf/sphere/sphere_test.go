@@ -0,0 +1,50 @@
+package sphere
+
+import (
+	"math"
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestArcInterpolator(t *testing.T) {
+	a, b := FromPoint(orb.Point{0, 0}), FromPoint(orb.Point{90, 0})
+	if d := Angle(a, b); math.Abs(d-math.Pi/2) > 1e-12 {
+		t.Errorf("expected angle π/2, got %v", d)
+	}
+	arc := ArcInterpolator(a, b)
+	for _, tt := range []struct {
+		f    float64
+		want orb.Point
+	}{
+		{f: 0, want: orb.Point{0, 0}},
+		{f: 0.5, want: orb.Point{45, 0}},
+		{f: 1, want: orb.Point{90, 0}},
+	} {
+		if p := arc(tt.f).Point(); math.Abs(p[0]-tt.want[0]) > 1e-9 || math.Abs(p[1]-tt.want[1]) > 1e-9 {
+			t.Errorf("fraction %v: expected %v, got %v", tt.f, tt.want, p)
+		}
+	}
+	if p := ArcInterpolator(a, a)(0.5).Point(); p != (orb.Point{0, 0}) {
+		t.Errorf("expected point of arc with equal ends, got %v", p)
+	}
+}
+
+func TestArcClosestPoint(t *testing.T) {
+	a, b := FromPoint(orb.Point{0, 0}), FromPoint(orb.Point{10, 0})
+	tests := []struct {
+		p    orb.Point
+		want orb.Point
+		end  int
+	}{
+		{p: orb.Point{5, 5}, want: orb.Point{5, 0}, end: -1},
+		{p: orb.Point{-5, 5}, want: orb.Point{0, 0}, end: 0},
+		{p: orb.Point{15, -5}, want: orb.Point{10, 0}, end: 1},
+	}
+	for _, tt := range tests {
+		c, end := ArcClosestPoint(a, b, FromPoint(tt.p))
+		if p := c.Point(); end != tt.end || math.Abs(p[0]-tt.want[0]) > 1e-9 || math.Abs(p[1]-tt.want[1]) > 1e-9 {
+			t.Errorf("point %v: expected %v of end %d, got %v of end %d", tt.p, tt.want, tt.end, p, end)
+		}
+	}
+}
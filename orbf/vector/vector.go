@@ -124,3 +124,23 @@ func IntersectionPoint(p1, p2, p3, p4 orb.Point) (orb.Point, bool) {
 
 	return orb.Point{x, y}, true
 }
+
+// SegmentsOverlap checks line segments p1p2 and p3p4 are collinear and have common part with non zero length.
+func SegmentsOverlap(p1, p2, p3, p4 orb.Point) bool {
+	d := SubtractPoints(p2, p1)
+	l := math.Sqrt(DotProduct(d, d))
+	if l == 0 {
+		return false
+	}
+	// distance of p3 and p4 to the line p1p2
+	v3 := SubtractPoints(p3, p1)
+	v4 := SubtractPoints(p4, p1)
+	if !FloatEqual((d.X()*v3.Y()-d.Y()*v3.X())/l, 0) ||
+		!FloatEqual((d.X()*v4.Y()-d.Y()*v4.X())/l, 0) {
+		return false
+	}
+	// positions of p3 and p4 on the segment p1p2
+	t3 := DotProduct(v3, d) / l
+	t4 := DotProduct(v4, d) / l
+	return min(max(t3, t4), l)-max(min(t3, t4), 0) > epsilon
+}
@@ -0,0 +1,99 @@
+package geobin
+
+import (
+	"fmt"
+
+	"github.com/VGSML/geobin/h3b"
+	"github.com/VGSML/geobin/h3f"
+	"github.com/paulmach/orb/geo"
+	"github.com/paulmach/orb/project"
+	"github.com/uber/h3-go/v4"
+)
+
+// Stats represents statistics of the index.
+type Stats struct {
+	Items  int       `json:"items"`
+	Bitmap h3b.Stats `json:"bitmap"`
+
+	// ItemCellsHistogram count of items by count of indexed cells.
+	ItemCellsHistogram map[int]int `json:"item_cells_histogram"`
+	// CellResHistogram count of indexed cells by resolution.
+	CellResHistogram [16]int `json:"cell_res_histogram"`
+	// BoundFalsePositiveRate estimated rate of false positive candidates for items indexed by bounds,
+	// calculated as average share of indexed cells area outside of item bound.
+	// Items with bounds of zero area (points, meridian or parallel lines) are not included.
+	BoundFalsePositiveRate float64 `json:"bound_false_positive_rate"`
+	// ZeroAreaBoundItems count of items indexed by bounds of zero area.
+	ZeroAreaBoundItems int `json:"zero_area_bound_items"`
+}
+
+// Stats returns statistics of the index.
+func (i *Index) Stats() Stats {
+	s := Stats{
+		Items:              len(i.items),
+		Bitmap:             i.bitmap.Stats(),
+		ItemCellsHistogram: map[int]int{},
+	}
+	boundItems := 0
+	for _, item := range i.items {
+		cells := item.indexedCells()
+		s.ItemCellsHistogram[len(cells)]++
+		for _, c := range cells {
+			s.CellResHistogram[h3f.H3Res(c)]++
+		}
+		item, ok := item.(*BoundIndexedItem)
+		if !ok {
+			continue
+		}
+		rate, ok := i.boundFalsePositiveRate(item)
+		if !ok {
+			s.ZeroAreaBoundItems++
+			continue
+		}
+		s.BoundFalsePositiveRate += rate
+		boundItems++
+	}
+	if boundItems != 0 {
+		s.BoundFalsePositiveRate /= float64(boundItems)
+	}
+	return s
+}
+
+// boundFalsePositiveRate returns share of indexed cells area outside of item bound,
+// returns false if item bound has zero area.
+func (i *Index) boundFalsePositiveRate(item *BoundIndexedItem) (float64, bool) {
+	bound := item.geom.Bound()
+	if i.proj == Mercator {
+		bound = project.Bound(bound, project.Mercator.ToWGS84)
+	}
+	boundArea := geo.Area(bound.ToPolygon())
+	if boundArea == 0 {
+		return 0, false
+	}
+	cellsArea := 0.
+	for _, c := range item.baseCells {
+		cellsArea += h3.CellAreaM2(c)
+	}
+	if cellsArea == 0 {
+		return 0, true
+	}
+	return max(0, 1-boundArea/cellsArea), true
+}
+
+// Metrics returns statistics as flat metrics, names of metrics contain labels in Prometheus format.
+func (s Stats) Metrics() map[string]float64 {
+	m := s.Bitmap.Metrics()
+	m["geobin_items"] = float64(s.Items)
+	m["geobin_bound_false_positive_rate"] = s.BoundFalsePositiveRate
+	m["geobin_zero_area_bound_items"] = float64(s.ZeroAreaBoundItems)
+	for n, c := range s.ItemCellsHistogram {
+		m[fmt.Sprintf(`geobin_item_cells_histogram{cells="%d"}`, n)] = float64(c)
+	}
+	for res, c := range s.CellResHistogram {
+		if c == 0 {
+			continue
+		}
+		m[fmt.Sprintf(`geobin_cell_res_histogram{res="%d"}`, res)] = float64(c)
+	}
+	return m
+}
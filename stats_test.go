@@ -0,0 +1,55 @@
+package geobin
+
+import (
+	"testing"
+
+	"github.com/paulmach/orb"
+)
+
+func TestIndex_Stats(t *testing.T) {
+	index := NewIndex(WithMaxResolution(9))
+	for idx, poly := range gridSquares(orb.Point{10, 10}, 2, 0.01) {
+		index.Insert(idx, poly)
+	}
+	index.Insert(10, orb.Point{20, 20})
+	index.Insert(11, orb.LineString{{20, 20}, {20, 20.1}})
+
+	s := index.Stats()
+	if s.Items != 6 || s.Bitmap.Items != 6 {
+		t.Errorf("Stats() items got %d, bitmap items %d, want 6", s.Items, s.Bitmap.Items)
+	}
+	items, cells := 0, 0
+	for n, c := range s.ItemCellsHistogram {
+		items += c
+		cells += n * c
+	}
+	resCells := 0
+	for _, c := range s.CellResHistogram {
+		resCells += c
+	}
+	if items != 6 || cells != resCells {
+		t.Errorf("Stats() histograms have %d items, %d cells and %d cells by resolution", items, cells, resCells)
+	}
+	if s.ZeroAreaBoundItems != 2 {
+		t.Errorf("Stats() zero area bound items got %d, want 2", s.ZeroAreaBoundItems)
+	}
+	if s.BoundFalsePositiveRate <= 0 || s.BoundFalsePositiveRate >= 1 {
+		t.Errorf("Stats() bound false positive rate got %v, want in (0, 1)", s.BoundFalsePositiveRate)
+	}
+
+	m := s.Metrics()
+	for _, name := range []string{"geobin_items", "geobin_bound_false_positive_rate", "geobin_zero_area_bound_items"} {
+		if _, ok := m[name]; !ok {
+			t.Errorf("Metrics() doesn't have %s", name)
+		}
+	}
+	if m["geobin_items"] != 6 {
+		t.Errorf("Metrics() geobin_items got %v, want 6", m["geobin_items"])
+	}
+
+	points := NewIndex()
+	points.Insert(1, orb.Point{1, 1})
+	if s := points.Stats(); s.BoundFalsePositiveRate != 0 || s.ZeroAreaBoundItems != 1 {
+		t.Errorf("Stats() of points got rate %v, zero area items %d, want 0, 1", s.BoundFalsePositiveRate, s.ZeroAreaBoundItems)
+	}
+}
@@ -0,0 +1,291 @@
+package geobin
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/VGSML/geobin/bjoin"
+	"github.com/VGSML/geobin/h3b"
+	"github.com/VGSML/geobin/orbf"
+	"github.com/VGSML/geobin/orbf/planar"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"github.com/paulmach/orb/project"
+)
+
+// Contiguity type of contiguity between items.
+type Contiguity int
+
+const (
+	// QueenContiguity items are neighbors if they have at least one common point.
+	QueenContiguity Contiguity = iota + 1
+	// RookContiguity items are neighbors if they have common edge.
+	RookContiguity
+)
+
+var (
+	ErrNoGeometry       = errors.New("item doesn't store geometry")
+	ErrInvalidNeighbors = errors.New("number of neighbors should be positive")
+)
+
+// Weights sparse spatial weights matrix.
+// For each item contains neighbors and corresponded weights, items without neighbors have empty slices.
+type Weights struct {
+	Neighbors map[int][]int
+	Weights   map[int][]float64
+}
+
+func newWeights() *Weights {
+	return &Weights{
+		Neighbors: map[int][]int{},
+		Weights:   map[int][]float64{},
+	}
+}
+
+// add adds neighbor b to item a with weight w.
+func (w *Weights) add(a, b int, weight float64) {
+	w.Neighbors[a] = append(w.Neighbors[a], b)
+	w.Weights[a] = append(w.Weights[a], weight)
+}
+
+// Len returns number of items.
+func (w *Weights) Len() int {
+	return len(w.Neighbors)
+}
+
+// RowStandardize makes sum of weights for each item equal 1.
+func (w *Weights) RowStandardize() {
+	for idx, ww := range w.Weights {
+		sum := 0.
+		for _, v := range ww {
+			sum += v
+		}
+		if sum == 0 {
+			continue
+		}
+		for i := range ww {
+			ww[i] /= sum
+		}
+		w.Weights[idx] = ww
+	}
+}
+
+// SelfJoinIntersects perform intersection join of the index with itself by bitmap index.
+// Pairs (a, a) are excluded, if unique is true each unordered pair is returned once.
+// Returns context error if the context is done before the join is completed.
+func (i *Index) SelfJoinIntersects(ctx context.Context, unique bool) (*bjoin.Index, error) {
+	return h3b.SelfJoinIntersects(ctx, i.bitmap, unique)
+}
+
+// ContiguityWeights returns binary contiguity weights of indexed items.
+// Candidates are taken from the bitmap self join and checked by items geometries.
+// Rook contiguity requires items that store geometry, otherwise returns ErrNoGeometry.
+func (i *Index) ContiguityWeights(ctx context.Context, contiguity Contiguity) (*Weights, error) {
+	w := newWeights()
+	for idx := range i.items {
+		w.Neighbors[idx] = nil
+		w.Weights[idx] = nil
+	}
+	join, err := i.SelfJoinIntersects(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	for pair := range join.ABGen(ctx) {
+		a, aok := i.items[int(pair[0])]
+		b, bok := i.items[int(pair[1])]
+		if !aok || !bok {
+			continue
+		}
+		ok, err := i.itemsContiguous(ctx, a, b, contiguity)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		w.add(a.Index(), b.Index(), 1)
+		w.add(b.Index(), a.Index(), 1)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for idx := range w.Neighbors {
+		sortNeighbors(w, idx)
+	}
+	return w, nil
+}
+
+// itemsContiguous checks items are neighbors with given contiguity.
+// Rook contiguity of WGS84 items is checked by orbf.EdgeTouch, so edges at antimeridian are handled.
+func (i *Index) itemsContiguous(ctx context.Context, a, b Item, contiguity Contiguity) (bool, error) {
+	if contiguity != RookContiguity {
+		return a.Intersects(ctx, b), nil
+	}
+	ga, ok := a.(Geometry)
+	if !ok {
+		return false, ErrNoGeometry
+	}
+	gb, ok := b.(Geometry)
+	if !ok {
+		return false, ErrNoGeometry
+	}
+	if i.proj == Mercator {
+		return planar.EdgeTouch(ga.Geom(), gb.Geom()), nil
+	}
+	return orbf.EdgeTouch(ga.Geom(), gb.Geom()), nil
+}
+
+// KNearestWeights returns binary weights of k nearest items by distance between items centroids.
+// Neighbors are searched by the index in the bound of the circle around item centroid, the circle radius
+// is doubled until it contains k centroids, circles that reach pole or antimeridian are searched by all items. Items which centroid is out of their indexed cells can be missed by the search.
+// Returns ErrInvalidNeighbors if k isn't positive.
+func (i *Index) KNearestWeights(ctx context.Context, k int) (*Weights, error) {
+	if k <= 0 {
+		return nil, ErrInvalidNeighbors
+	}
+	ids := make([]int, 0, len(i.items))
+	for idx := range i.items {
+		ids = append(ids, idx)
+	}
+	sort.Ints(ids)
+	centroids := make(map[int]orb.Point, len(ids))
+	var bound orb.Bound
+	for n, idx := range ids {
+		c := i.itemCentroidWGS84(i.items[idx])
+		centroids[idx] = c
+		if n == 0 {
+			bound = c.Bound()
+		}
+		bound = bound.Extend(c)
+	}
+	// initial radius of the circle that contains k centroids if they are uniformly distributed
+	radius := 1.
+	if len(ids) > 1 {
+		radius = max(radius, math.Sqrt(geo.Area(bound)*float64(k)/(math.Pi*float64(len(ids)))))
+	}
+
+	type neighbor struct {
+		idx  int
+		dist float64
+	}
+	w := newWeights()
+	var nn []neighbor
+	for _, idx := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		c := centroids[idx]
+		for r := radius; ; r *= 2 {
+			candidates := ids
+			if search, ok := i.circleBound(c, r); ok {
+				candidates = i.IntersectionWith(ctx, search)
+			}
+			nn = nn[:0]
+			inside := 0
+			for _, other := range candidates {
+				if other == idx {
+					continue
+				}
+				d := geo.Distance(c, centroids[other])
+				if d <= r {
+					inside++
+				}
+				nn = append(nn, neighbor{idx: other, dist: d})
+			}
+			if inside >= k || len(candidates) == len(ids) {
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		sort.Slice(nn, func(a, b int) bool {
+			if nn[a].dist != nn[b].dist {
+				return nn[a].dist < nn[b].dist
+			}
+			return nn[a].idx < nn[b].idx
+		})
+		nn = nn[:min(k, len(nn))]
+		w.Neighbors[idx] = make([]int, 0, len(nn))
+		w.Weights[idx] = make([]float64, 0, len(nn))
+		for _, nb := range nn {
+			w.add(idx, nb.idx, 1)
+		}
+	}
+	return w, nil
+}
+
+// circleBound returns polygon in the index projection that contains the circle of radius meters around WGS84 point.
+// Returns false if the circle reaches pole or antimeridian.
+func (i *Index) circleBound(c orb.Point, meters float64) (orb.Geometry, bool) {
+	dLat := meters / orb.EarthRadius * 180 / math.Pi
+	minLat, maxLat := c.Lat()-dLat, c.Lat()+dLat
+	maxAbsLat := max(math.Abs(minLat), math.Abs(maxLat))
+	if maxAbsLat >= 90 || i.proj == Mercator && maxAbsLat > 85.05112878 {
+		return nil, false
+	}
+	// the widest longitude range of the circle is less than at its farthest from equator latitude
+	x := math.Sin(meters/orb.EarthRadius) / math.Cos(maxAbsLat*math.Pi/180)
+	if x >= 1 {
+		return nil, false
+	}
+	dLon := math.Asin(x) * 180 / math.Pi
+	west, east := c.Lon()-dLon, c.Lon()+dLon
+	if west < -180 || east > 180 {
+		return nil, false
+	}
+	search := orb.Geometry(orb.Bound{Min: orb.Point{west, minLat}, Max: orb.Point{east, maxLat}}.ToPolygon())
+	if i.proj == Mercator {
+		search = project.Geometry(search, project.WGS84.ToMercator)
+	}
+	return search, true
+}
+
+// itemCentroidWGS84 returns centroid of item in WGS84 projection.
+// For items that doesn't store geometry returns center of indexed cells.
+func (i *Index) itemCentroidWGS84(item Item) orb.Point {
+	g, ok := item.(Geometry)
+	if !ok {
+		cells := item.indexedCells()
+		var lat, lng float64
+		for _, c := range cells {
+			ll := c.LatLng()
+			lat += ll.Lat
+			lng += ll.Lng
+		}
+		if len(cells) == 0 {
+			return orb.Point{}
+		}
+		return orb.Point{lng / float64(len(cells)), lat / float64(len(cells))}
+	}
+	c, ok := planar.Centroid(g.Geom()).(orb.Point)
+	if !ok {
+		c = g.Geom().Bound().Center()
+	}
+	if i.proj == Mercator {
+		return project.Point(c, project.Mercator.ToWGS84)
+	}
+	return c
+}
+
+// sortNeighbors sorts neighbors of the item by index.
+func sortNeighbors(w *Weights, idx int) {
+	nn, ww := w.Neighbors[idx], w.Weights[idx]
+	sort.Sort(neighborsSorter{nn, ww})
+}
+
+type neighborsSorter struct {
+	nn []int
+	ww []float64
+}
+
+func (s neighborsSorter) Len() int           { return len(s.nn) }
+func (s neighborsSorter) Less(i, j int) bool { return s.nn[i] < s.nn[j] }
+func (s neighborsSorter) Swap(i, j int) {
+	s.nn[i], s.nn[j] = s.nn[j], s.nn[i]
+	s.ww[i], s.ww[j] = s.ww[j], s.ww[i]
+}
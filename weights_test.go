@@ -0,0 +1,150 @@
+package geobin
+
+import (
+	"context"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/VGSML/geobin/h3f"
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geo"
+	"github.com/paulmach/orb/project"
+)
+
+// gridSquares returns n×n squares with given size starting at point p, item index is row*n+column.
+func gridSquares(p orb.Point, n int, size float64) []orb.Polygon {
+	out := make([]orb.Polygon, 0, n*n)
+	for row := 0; row < n; row++ {
+		for col := 0; col < n; col++ {
+			x, y := p[0]+float64(col)*size, p[1]+float64(row)*size
+			out = append(out, orb.Polygon{{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}, {x, y}}})
+		}
+	}
+	return out
+}
+
+func TestIndex_ContiguityWeights(t *testing.T) {
+	index := NewIndex(WithMaxResolution(9))
+	for idx, poly := range gridSquares(orb.Point{10, 10}, 3, 0.01) {
+		index.Insert(idx, poly)
+	}
+	tests := []struct {
+		contiguity Contiguity
+		expected   map[int][]int
+	}{
+		{contiguity: QueenContiguity, expected: map[int][]int{0: {1, 3, 4}, 4: {0, 1, 2, 3, 5, 6, 7, 8}, 5: {1, 2, 4, 7, 8}}},
+		{contiguity: RookContiguity, expected: map[int][]int{0: {1, 3}, 4: {1, 3, 5, 7}, 5: {2, 4, 8}}},
+	}
+	for _, tt := range tests {
+		w, err := index.ContiguityWeights(context.Background(), tt.contiguity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Len() != 9 {
+			t.Errorf("ContiguityWeights(%d) returns %d items, want 9", tt.contiguity, w.Len())
+		}
+		for idx, want := range tt.expected {
+			if got := w.Neighbors[idx]; !slices.Equal(got, want) {
+				t.Errorf("ContiguityWeights(%d) item %d neighbors = %v, want %v", tt.contiguity, idx, got, want)
+			}
+		}
+		w.RowStandardize()
+		if ww := w.Weights[0]; len(ww) == 0 || ww[0] != 1/float64(len(ww)) {
+			t.Errorf("RowStandardize() item 0 weights = %v", ww)
+		}
+	}
+}
+
+func TestIndex_ContiguityWeightsAntimeridian(t *testing.T) {
+	index := NewIndex(WithGeometryCoverer(h3f.Coverer{MaxCells: 64, MaxRes: 6}))
+	index.Insert(1, orb.Polygon{{{175, 0}, {180, 0}, {180, 10}, {175, 10}, {175, 0}}})
+	index.Insert(2, orb.Polygon{{{-180, 5}, {-175, 5}, {-175, 15}, {-180, 15}, {-180, 5}}})
+	index.Insert(3, orb.Polygon{{{170, 10}, {-170, 10}, {-170, 20}, {170, 20}, {170, 10}}})
+	w, err := index.ContiguityWeights(context.Background(), RookContiguity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[int][]int{1: {2, 3}, 2: {1}, 3: {1}}
+	for idx, want := range expected {
+		if got := w.Neighbors[idx]; !slices.Equal(got, want) {
+			t.Errorf("ContiguityWeights() item %d neighbors = %v, want %v", idx, got, want)
+		}
+	}
+}
+
+func TestIndex_ContiguityWeightsNoGeometry(t *testing.T) {
+	index := NewIndex(WithIndexedItems(false), WithMaxResolution(9))
+	for idx, poly := range gridSquares(orb.Point{10, 10}, 2, 0.01) {
+		index.Insert(idx, poly)
+	}
+	if _, err := index.ContiguityWeights(context.Background(), RookContiguity); err != ErrNoGeometry {
+		t.Errorf("ContiguityWeights() error = %v, want %v", err, ErrNoGeometry)
+	}
+	if _, err := index.ContiguityWeights(context.Background(), QueenContiguity); err != nil {
+		t.Errorf("ContiguityWeights() unexpected error: %v", err)
+	}
+}
+
+func TestIndex_KNearestWeights(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	points := make([]orb.Point, 200)
+	for n := range points {
+		// dense cluster and sparse points around it
+		size := 0.1
+		if n%10 == 0 {
+			size = 20
+		}
+		points[n] = orb.Point{30 + rnd.Float64()*size, 40 + rnd.Float64()*size}
+	}
+	for _, proj := range []Projection{WGS84, Mercator} {
+		var options []IndexOptions
+		if proj == Mercator {
+			options = append(options, WithMercatorProjection())
+		}
+		index := NewIndex(append(options, WithMaxResolution(8))...)
+		for idx, p := range points {
+			if proj == Mercator {
+				p = project.Point(p, project.WGS84.ToMercator)
+			}
+			index.Insert(idx, p)
+		}
+		const k = 4
+		w, err := index.KNearestWeights(context.Background(), k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for idx, p := range points {
+			ids := make([]int, 0, len(points)-1)
+			for other := range points {
+				if other != idx {
+					ids = append(ids, other)
+				}
+			}
+			sort.SliceStable(ids, func(a, b int) bool {
+				return geo.Distance(p, points[ids[a]]) < geo.Distance(p, points[ids[b]])
+			})
+			if got := w.Neighbors[idx]; !slices.Equal(got, ids[:k]) {
+				t.Fatalf("KNearestWeights() projection %d item %d neighbors = %v, want %v", proj, idx, got, ids[:k])
+			}
+		}
+	}
+
+	index := NewIndex()
+	index.Insert(1, orb.Point{0, 0})
+	index.Insert(2, orb.Point{1, 0})
+	index.Insert(3, orb.Point{-179, 0})
+	w, err := index.KNearestWeights(context.Background(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := w.Neighbors[3]; !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("KNearestWeights() with k greater than items returns %v, want [1 2]", got)
+	}
+	for _, k := range []int{0, -1} {
+		if _, err := index.KNearestWeights(context.Background(), k); err != ErrInvalidNeighbors {
+			t.Errorf("KNearestWeights(%d) error = %v, want %v", k, err, ErrInvalidNeighbors)
+		}
+	}
+}